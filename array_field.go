@@ -0,0 +1,152 @@
+package trenovaorm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ArrayField represents a one-dimensional array column whose elements have
+// the type of the Element field, e.g. &CharField{MaxLength: 50} for a
+// VARCHAR(50)[] column. Only the element's type is used: its column name,
+// nullability, default and other options are ignored.
+type ArrayField struct {
+	ColumnName string
+	Element    Field
+	Nullable   bool
+	// Default lists the elements of the default array. A nil Default means no
+	// default, an empty non-nil Default means the empty array.
+	Default     []string
+	Index       bool // Create a GIN index on the column
+	Comment     string
+	Constraints []string
+	StructTag   string
+}
+
+// Definition generates the SQL definition for the ArrayField.
+func (f *ArrayField) Definition() string {
+	def := fmt.Sprintf("%s %s", QuoteIdentifier(f.ColumnName), f.sqlType())
+
+	if !f.Nullable {
+		def += fmt.Sprintf(" %s", ConstraintNotNull.String())
+	}
+
+	if f.Default != nil {
+		def += " DEFAULT " + arrayLiteral(f.Default)
+	}
+
+	if len(f.Constraints) > 0 {
+		def += " " + strings.Join(f.Constraints, " ")
+	}
+	return def
+}
+
+// Name returns the column name for the ArrayField.
+func (f *ArrayField) Name() string {
+	return f.ColumnName
+}
+
+// CommentSQL generates the SQL statement for adding a comment to the ArrayField.
+func (f *ArrayField) CommentSQL(tableName string) string {
+	if f.Comment == "" {
+		return ""
+	}
+	return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;", QuoteQualifiedName(tableName), QuoteIdentifier(f.ColumnName), QuoteLiteral(f.Comment))
+}
+
+// Validate checks if the field's configuration is valid.
+func (f *ArrayField) Validate() error {
+	if f.ColumnName == "" {
+		return errors.New("column name cannot be empty")
+	}
+	if f.Element == nil {
+		return fmt.Errorf("ArrayField %s has no element field", f.ColumnName)
+	}
+	if _, ok := f.Element.(*ArrayField); ok {
+		return fmt.Errorf("ArrayField %s: multidimensional arrays are not supported", f.ColumnName)
+	}
+	if _, ok := f.Element.(*ForeignKeyField); ok {
+		return fmt.Errorf("ArrayField %s: array elements cannot be foreign keys", f.ColumnName)
+	}
+	if ColumnOf(f.Element).Type == "" {
+		return fmt.Errorf("ArrayField %s: unsupported element field %T", f.ColumnName, f.Element)
+	}
+	if enum, ok := f.Enum(); ok {
+		if err := enum.Validate(); err != nil {
+			return err
+		}
+		for _, v := range f.Default {
+			if !enum.Has(v) {
+				return fmt.Errorf("ArrayField %s default %q is not a value of %s", f.ColumnName, v, enum.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// Enum returns the enum type of the elements, if they are of an enum type.
+func (f *ArrayField) Enum() (EnumType, bool) {
+	if e, ok := f.Element.(*EnumField); ok {
+		return e.Enum(), true
+	}
+	return EnumType{}, false
+}
+
+// GoType returns the Go type for the ArrayField: an Array of the element's
+// Go type. A nil Array is stored as NULL, so nullable columns use the same type.
+func (f *ArrayField) GoType() string {
+	if f.Element == nil {
+		return ""
+	}
+	return fmt.Sprintf("Array[%s]", strings.TrimPrefix(f.Element.GoType(), "*"))
+}
+
+// IndexSQL generates the SQL statement for creating a GIN index if Index is true.
+func (f *ArrayField) IndexSQL(tableName string) string {
+	if !f.Index {
+		return ""
+	}
+	indexName := f.IndexName(tableName)
+	return fmt.Sprintf("CREATE INDEX %s ON %s USING GIN (%s);", QuoteIdentifier(indexName), QuoteQualifiedName(tableName), QuoteIdentifier(f.ColumnName))
+}
+
+// IndexName returns the name of the index generated by IndexSQL.
+func (f *ArrayField) IndexName(tableName string) string {
+	return fmt.Sprintf("%s_%s_idx", unqualifiedName(tableName), f.ColumnName)
+}
+
+// sqlType returns the SQL type of the column, the element type followed by [].
+func (f *ArrayField) sqlType() string {
+	if f.Element == nil {
+		return "[]"
+	}
+	return ColumnOf(f.Element).Type + "[]"
+}
+
+// arrayLiteral renders values as a quoted PostgreSQL array literal such as
+// '{"a","b"}'. Every element is double-quoted so that commas, braces, spaces
+// and the word NULL are taken literally.
+func arrayLiteral(values []string) string {
+	return QuoteLiteral(formatArray(values))
+}
+
+// formatArray renders values in the PostgreSQL array input syntax.
+func formatArray(values []string) string {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(quoteArrayElement(v))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// quoteArrayElement double-quotes an array element, escaping quotes and backslashes.
+func quoteArrayElement(v string) string {
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, `"`, `\"`)
+	return `"` + v + `"`
+}
@@ -0,0 +1,219 @@
+package trenovaorm
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestArrayField_Definition(t *testing.T) {
+	tests := []struct {
+		name     string
+		field    ArrayField
+		expected string
+	}{
+		{
+			name:     "Varchar array",
+			field:    ArrayField{ColumnName: "tags", Element: &CharField{MaxLength: 50}},
+			expected: `"tags" VARCHAR(50)[] NOT NULL`,
+		},
+		{
+			name:     "Nullable uuid array",
+			field:    ArrayField{ColumnName: "ids", Element: &UUIDField{}, Nullable: true},
+			expected: `"ids" uuid[]`,
+		},
+		{
+			name:     "Empty default",
+			field:    ArrayField{ColumnName: "scores", Element: &IntegerField{}, Default: []string{}},
+			expected: `"scores" INTEGER[] NOT NULL DEFAULT '{}'`,
+		},
+		{
+			name:     "Default with special characters",
+			field:    ArrayField{ColumnName: "tags", Element: &TextField{}, Default: []string{"a,b", `say "hi"`, "O'Brien", "NULL"}},
+			expected: `"tags" TEXT[] NOT NULL DEFAULT $${"a,b","say \"hi\"","O'Brien","NULL"}$$`,
+		},
+		{
+			name: "Enum array",
+			field: ArrayField{
+				ColumnName: "statuses",
+				Element:    &EnumField{TypeName: "shipment_status", Values: []string{"new", "delivered"}},
+				Default:    []string{"new"},
+			},
+			expected: `"statuses" "shipment_status"[] NOT NULL DEFAULT '{"new"}'`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.field.Definition(); got != tt.expected {
+				t.Errorf("ArrayField.Definition() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestArrayField_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   ArrayField
+		wantErr bool
+	}{
+		{
+			name:    "Valid",
+			field:   ArrayField{ColumnName: "tags", Element: &TextField{}},
+			wantErr: false,
+		},
+		{
+			name:    "Missing column name",
+			field:   ArrayField{Element: &TextField{}},
+			wantErr: true,
+		},
+		{
+			name:    "Missing element",
+			field:   ArrayField{ColumnName: "tags"},
+			wantErr: true,
+		},
+		{
+			name:    "Nested array",
+			field:   ArrayField{ColumnName: "tags", Element: &ArrayField{Element: &TextField{}}},
+			wantErr: true,
+		},
+		{
+			name:    "Foreign key element",
+			field:   ArrayField{ColumnName: "ids", Element: &ForeignKeyField{ReferenceTable: "users", ReferenceField: "id"}},
+			wantErr: true,
+		},
+		{
+			name: "Enum default not in values",
+			field: ArrayField{
+				ColumnName: "statuses",
+				Element:    &EnumField{TypeName: "status", Values: []string{"a"}},
+				Default:    []string{"b"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.field.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("ArrayField.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestArrayField_GoType(t *testing.T) {
+	tests := []struct {
+		field ArrayField
+		want  string
+	}{
+		{ArrayField{ColumnName: "tags", Element: &CharField{MaxLength: 10}}, "Array[string]"},
+		{ArrayField{ColumnName: "ids", Element: &UUIDField{Nullable: true}, Nullable: true}, "Array[uuid.UUID]"},
+		{ArrayField{ColumnName: "times", Element: &TimeField{}}, "Array[TimeOnly]"},
+		{ArrayField{ColumnName: "scores", Element: &IntegerField{}}, "Array[int]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field.ColumnName, func(t *testing.T) {
+			if got := tt.field.GoType(); got != tt.want {
+				t.Errorf("ArrayField.GoType() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArrayField_IndexSQL(t *testing.T) {
+	field := ArrayField{ColumnName: "tags", Element: &TextField{}, Index: true}
+	want := `CREATE INDEX "posts_tags_idx" ON "posts" USING GIN ("tags");`
+	if got := field.IndexSQL("posts"); got != want {
+		t.Errorf("ArrayField.IndexSQL() = %v, want %v", got, want)
+	}
+}
+
+func TestArray_Scan(t *testing.T) {
+	var strs Array[string]
+	if err := strs.Scan([]byte(`{plain,"a,b","say \"hi\"","back\\slash",NULL,"NULL"}`)); err != nil {
+		t.Fatalf("Array.Scan() error = %v", err)
+	}
+	if want := (Array[string]{"plain", "a,b", `say "hi"`, `back\slash`, "", "NULL"}); !reflect.DeepEqual(strs, want) {
+		t.Errorf("Array.Scan() = %q, want %q", strs, want)
+	}
+
+	var ints Array[int16]
+	if err := ints.Scan("{1,-2,3}"); err != nil {
+		t.Fatalf("Array.Scan() error = %v", err)
+	}
+	if want := (Array[int16]{1, -2, 3}); !reflect.DeepEqual(ints, want) {
+		t.Errorf("Array.Scan() = %v, want %v", ints, want)
+	}
+
+	var bools Array[bool]
+	if err := bools.Scan("{t,f}"); err != nil || !reflect.DeepEqual(bools, Array[bool]{true, false}) {
+		t.Errorf("Array.Scan() = %v, %v, want [true false]", bools, err)
+	}
+
+	var times Array[TimeOnly]
+	if err := times.Scan("{12:30:00}"); err != nil || times[0].Time.Hour() != 12 || times[0].Time.Minute() != 30 {
+		t.Errorf("Array.Scan() = %v, %v, want [12:30:00]", times, err)
+	}
+
+	var empty Array[string]
+	if err := empty.Scan("{}"); err != nil || empty == nil || len(empty) != 0 {
+		t.Errorf("Array.Scan() = %#v, %v, want empty non-nil array", empty, err)
+	}
+
+	if err := strs.Scan(nil); err != nil || strs != nil {
+		t.Errorf("Array.Scan(nil) = %v, %v, want nil", strs, err)
+	}
+
+	for _, bad := range []string{"a,b", "{{1,2},{3,4}}", `{"open}`, "{1,x}"} {
+		if err := ints.Scan(bad); err == nil {
+			t.Errorf("Array.Scan(%q) error = nil, want error", bad)
+		}
+	}
+}
+
+func TestArray_Value(t *testing.T) {
+	tests := []struct {
+		name  string
+		array driver.Valuer
+		want  driver.Value
+	}{
+		{"Nil", Array[string](nil), nil},
+		{"Empty", Array[string]{}, "{}"},
+		{"Strings", Array[string]{"a,b", `say "hi"`, `back\slash`}, `{"a,b","say \"hi\"","back\\slash"}`},
+		{"Integers", Array[int64]{1, -2}, `{"1","-2"}`},
+		{"Floats", Array[float64]{1.5}, `{"1.5"}`},
+		{"Zero TimeOnly is NULL", Array[TimeOnly]{{}}, `{NULL}`},
+		{"Times", Array[time.Time]{time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}, `{"2024-01-02T03:04:05Z"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.array.Value()
+			if err != nil {
+				t.Fatalf("Array.Value() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Array.Value() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArray_RoundTrip(t *testing.T) {
+	in := Array[string]{"", " padded ", "{braces}", `"`, `\`, "NULL"}
+	value, err := in.Value()
+	if err != nil {
+		t.Fatalf("Array.Value() error = %v", err)
+	}
+	var out Array[string]
+	if err := out.Scan(value); err != nil {
+		t.Fatalf("Array.Scan() error = %v", err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("Array round trip = %q, want %q", out, in)
+	}
+}
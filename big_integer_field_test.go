@@ -0,0 +1,146 @@
+package trenovaorm
+
+import "testing"
+
+func TestBigIntegerField_Definition(t *testing.T) {
+	tests := []struct {
+		name     string
+		field    BigIntegerField
+		expected string
+	}{
+		{
+			name:     "Basic BigIntegerField",
+			field:    BigIntegerField{ColumnName: "views", Default: 1 << 40},
+			expected: `"views" BIGINT NOT NULL DEFAULT 1099511627776`,
+		},
+		{
+			name:     "Serial primary key",
+			field:    BigIntegerField{ColumnName: "id", PrimaryKey: true, Serial: true},
+			expected: `"id" BIGSERIAL NOT NULL PRIMARY KEY`,
+		},
+		{
+			name:     "Identity primary key",
+			field:    BigIntegerField{ColumnName: "id", PrimaryKey: true, Identity: Identity{Generation: IdentityAlways}},
+			expected: `"id" BIGINT NOT NULL GENERATED ALWAYS AS IDENTITY PRIMARY KEY`,
+		},
+		{
+			name: "Identity with sequence options",
+			field: BigIntegerField{
+				ColumnName: "id",
+				Identity:   Identity{Generation: IdentityByDefault, Start: 1000, Increment: 10},
+			},
+			expected: `"id" BIGINT NOT NULL GENERATED BY DEFAULT AS IDENTITY (START WITH 1000 INCREMENT BY 10)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.field.Definition(); got != tt.expected {
+				t.Errorf("BigIntegerField.Definition() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBigIntegerField_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   BigIntegerField
+		wantErr bool
+	}{
+		{
+			name:    "Valid identity",
+			field:   BigIntegerField{ColumnName: "id", PrimaryKey: true, Identity: Identity{Generation: IdentityAlways, Start: 5}},
+			wantErr: false,
+		},
+		{
+			name:    "Nullable primary key",
+			field:   BigIntegerField{ColumnName: "id", PrimaryKey: true, Nullable: true},
+			wantErr: true,
+		},
+		{
+			name:    "Serial and identity",
+			field:   BigIntegerField{ColumnName: "id", Serial: true, Identity: Identity{Generation: IdentityAlways}},
+			wantErr: true,
+		},
+		{
+			name:    "Identity with default",
+			field:   BigIntegerField{ColumnName: "id", Default: 1, Identity: Identity{Generation: IdentityByDefault}},
+			wantErr: true,
+		},
+		{
+			name:    "Nullable serial",
+			field:   BigIntegerField{ColumnName: "id", Serial: true, Nullable: true},
+			wantErr: true,
+		},
+		{
+			name:    "Unknown generation",
+			field:   BigIntegerField{ColumnName: "id", Identity: Identity{Generation: "SOMETIMES"}},
+			wantErr: true,
+		},
+		{
+			name:    "Sequence options without generation",
+			field:   BigIntegerField{ColumnName: "id", Identity: Identity{Start: 10}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.field.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("BigIntegerField.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIntegerFields_ColumnOf(t *testing.T) {
+	tests := []struct {
+		field Field
+		want  Column
+	}{
+		{
+			field: &IntegerField{ColumnName: "id", PrimaryKey: true, Serial: true},
+			want:  Column{Name: "id", Type: "INTEGER", PrimaryKey: true, Serial: true, ServerDefault: true},
+		},
+		{
+			field: &BigIntegerField{ColumnName: "id", Identity: Identity{Generation: IdentityAlways, Start: 1, Increment: 2}},
+			want:  Column{Name: "id", Type: "BIGINT", ServerDefault: true, Identity: Identity{Generation: IdentityAlways, Increment: 2}},
+		},
+		{
+			field: &SmallIntegerField{ColumnName: "rank", Nullable: true, Default: 3},
+			want:  Column{Name: "rank", Type: "SMALLINT", Nullable: true, Default: "3"},
+		},
+		{
+			field: &PositiveIntegerField{ColumnName: "id", PrimaryKey: true},
+			want:  Column{Name: "id", Type: "INTEGER", PrimaryKey: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field.Name(), func(t *testing.T) {
+			if got := ColumnOf(tt.field); got != tt.want {
+				t.Errorf("ColumnOf() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntegerFields_GoType(t *testing.T) {
+	tests := []struct {
+		field Field
+		want  string
+	}{
+		{&IntegerField{ColumnName: "a"}, "int"},
+		{&BigIntegerField{ColumnName: "a"}, "int64"},
+		{&BigIntegerField{ColumnName: "a", Nullable: true}, "*int64"},
+		{&SmallIntegerField{ColumnName: "a"}, "int16"},
+		{&SmallIntegerField{ColumnName: "a", Nullable: true}, "*int16"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.field.GoType(); got != tt.want {
+			t.Errorf("%T.GoType() = %v, want %v", tt.field, got, tt.want)
+		}
+	}
+}
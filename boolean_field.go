@@ -24,7 +24,7 @@ func (f *BooleanField) Definition() string {
 	if f.CustomType != "" {
 		typ = f.CustomType
 	}
-	def := fmt.Sprintf(`"%s" %s`, f.ColumnName, typ)
+	def := fmt.Sprintf("%s %s", QuoteIdentifier(f.ColumnName), typ)
 	if !f.Nullable {
 		def += fmt.Sprintf(" %s", ConstraintNotNull.String())
 	}
@@ -52,7 +52,7 @@ func (f *BooleanField) CommentSQL(tableName string) string {
 	if f.Comment == "" {
 		return ""
 	}
-	return fmt.Sprintf(`COMMENT ON COLUMN "%s"."%s" IS '%s';`, tableName, f.ColumnName, f.Comment)
+	return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;", QuoteQualifiedName(tableName), QuoteIdentifier(f.ColumnName), QuoteLiteral(f.Comment))
 }
 
 // Validate checks if the field's configuration is valid.
@@ -77,6 +77,11 @@ func (f *BooleanField) IndexSQL(tableName string) string {
 	if !f.Index {
 		return ""
 	}
-	indexName := fmt.Sprintf("idx_%s_%s", tableName, f.ColumnName)
-	return fmt.Sprintf(`CREATE INDEX "%s" ON "%s" ("%s");`, indexName, tableName, f.ColumnName)
+	indexName := f.IndexName(tableName)
+	return fmt.Sprintf("CREATE INDEX %s ON %s (%s);", QuoteIdentifier(indexName), QuoteQualifiedName(tableName), QuoteIdentifier(f.ColumnName))
+}
+
+// IndexName returns the name of the index generated by IndexSQL.
+func (f *BooleanField) IndexName(tableName string) string {
+	return fmt.Sprintf("idx_%s_%s", unqualifiedName(tableName), f.ColumnName)
 }
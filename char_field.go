@@ -27,7 +27,7 @@ func (f *CharField) Definition() string {
 	if f.CustomType != "" {
 		typ = f.CustomType
 	}
-	def := fmt.Sprintf(`"%s" %s`, f.ColumnName, typ)
+	def := fmt.Sprintf("%s %s", QuoteIdentifier(f.ColumnName), typ)
 
 	if !f.Blank && !f.Nullable {
 		def += fmt.Sprintf(" %s", ConstraintNotNull.String())
@@ -38,7 +38,7 @@ func (f *CharField) Definition() string {
 	}
 
 	if f.Default != "" {
-		def += fmt.Sprintf(" DEFAULT '%s'", f.Default)
+		def += " DEFAULT " + QuoteLiteral(f.Default)
 	}
 
 	if len(f.Constraints) > 0 {
@@ -57,7 +57,7 @@ func (f *CharField) CommentSQL(tableName string) string {
 	if f.Comment == "" {
 		return ""
 	}
-	return fmt.Sprintf(`COMMENT ON COLUMN "%s"."%s" IS '%s';`, tableName, f.ColumnName, f.Comment)
+	return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;", QuoteQualifiedName(tableName), QuoteIdentifier(f.ColumnName), QuoteLiteral(f.Comment))
 }
 
 // Validate checks if the field's configuration is valid.
@@ -90,6 +90,11 @@ func (f *CharField) IndexSQL(tableName string) string {
 	if !f.Index {
 		return ""
 	}
-	indexName := fmt.Sprintf("%s_%s_idx", tableName, f.ColumnName)
-	return fmt.Sprintf(`CREATE INDEX "%s" ON "%s" ("%s");`, indexName, tableName, f.ColumnName)
+	indexName := f.IndexName(tableName)
+	return fmt.Sprintf("CREATE INDEX %s ON %s (%s);", QuoteIdentifier(indexName), QuoteQualifiedName(tableName), QuoteIdentifier(f.ColumnName))
+}
+
+// IndexName returns the name of the index generated by IndexSQL.
+func (f *CharField) IndexName(tableName string) string {
+	return fmt.Sprintf("%s_%s_idx", unqualifiedName(tableName), f.ColumnName)
 }
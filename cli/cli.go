@@ -0,0 +1,179 @@
+// Package cli implements the trenova-orm command-line tool.
+//
+// Models are loaded from a schema package that registers them with
+// trenovaorm.Register, usually from the registry file written by
+// "trenova-orm -schema <dir> generate registry". When -schema is given, the
+// tool re-runs itself through "go run" inside the caller's module with the
+// schema package linked in, so its models are available to every command.
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	trenovaorm "github.com/emoss08/trenova-orm"
+)
+
+// Exit codes returned by Main.
+const (
+	ExitOK    = 0
+	ExitError = 1
+	ExitUsage = 2
+)
+
+// loadedEnv is set when the tool has been re-run with the schema package linked in.
+const loadedEnv = "TRENOVAORM_SCHEMA_LOADED"
+
+const programName = "trenova-orm"
+
+// usageError marks errors caused by invalid command-line usage.
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func usagef(format string, args ...any) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// command is a single subcommand of the tool.
+type command struct {
+	name    string
+	summary string
+	run     func(r *runner, args []string) error
+}
+
+var commands = []command{
+	{"generate sql", "Write the CREATE TABLE, COMMENT and CREATE INDEX statements for the models", (*runner).generateSQL},
+	{"generate structs", "Write Go struct and enum type definitions for the models", (*runner).generateStructs},
+	{"generate repositories", "Write CRUD repositories and their tests for the models", (*runner).generateRepositories},
+	{"generate registry", "Write a registry file that registers every model in the schema package", (*runner).generateRegistry},
+	{"migrate diff", "Write a migration that brings the database in line with the models", (*runner).migrateDiff},
+	{"migrate up", "Apply pending migrations", (*runner).migrateUp},
+	{"migrate down", "Revert the most recently applied migrations", (*runner).migrateDown},
+	{"migrate status", "Show applied and pending migrations", (*runner).migrateStatus},
+	{"inspect", "Write the DDL of a live database schema", (*runner).inspect},
+	{"validate", "Validate the models", (*runner).validate},
+}
+
+// runner carries the global options and output streams of a single invocation.
+type runner struct {
+	schema string
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// Main runs the tool with the given arguments, excluding the program name, and
+// returns the process exit code.
+func Main(args []string, stdout, stderr io.Writer) int {
+	r := &runner{stdout: stdout, stderr: stderr}
+
+	global := flag.NewFlagSet(programName, flag.ContinueOnError)
+	global.SetOutput(io.Discard)
+	global.StringVar(&r.schema, "schema", "", "directory of the schema package that registers the models")
+	if err := global.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			r.usage(stdout)
+			return ExitOK
+		}
+		return r.fail(usagef("%v", err))
+	}
+
+	cmd, rest, ok := lookup(global.Args())
+	if !ok {
+		if len(global.Args()) == 0 {
+			r.usage(stderr)
+			return ExitUsage
+		}
+		return r.fail(usagef("unknown command %q", strings.Join(global.Args(), " ")))
+	}
+
+	if r.schema != "" && cmd.name != "generate registry" && os.Getenv(loadedEnv) == "" {
+		return r.runLoaded(args)
+	}
+
+	return r.fail(cmd.run(r, rest))
+}
+
+// lookup finds the command named by the leading arguments.
+func lookup(args []string) (command, []string, bool) {
+	for _, cmd := range commands {
+		words := strings.Fields(cmd.name)
+		if len(args) < len(words) {
+			continue
+		}
+		if strings.Join(args[:len(words)], " ") == cmd.name {
+			return cmd, args[len(words):], true
+		}
+	}
+	return command{}, nil, false
+}
+
+// fail reports err, if any, and returns the matching exit code.
+func (r *runner) fail(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	fmt.Fprintf(r.stderr, "%s: %v\n", programName, err)
+
+	var usage *usageError
+	if errors.As(err, &usage) {
+		fmt.Fprintf(r.stderr, "Run '%s' without arguments for usage.\n", programName)
+		return ExitUsage
+	}
+	return ExitError
+}
+
+// usage prints the list of commands.
+func (r *runner) usage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: %s [-schema <dir>] <command> [flags]\n\nCommands:\n", programName)
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-18s %s\n", cmd.name, cmd.summary)
+	}
+}
+
+// newFlagSet returns a silent flag set for the named command; parse reports its errors.
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+// parse parses command flags and rejects unexpected positional arguments.
+func parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return usagef("%s: %v", fs.Name(), err)
+	}
+	if fs.NArg() > 0 {
+		return usagef("%s: unexpected argument %q", fs.Name(), fs.Arg(0))
+	}
+	return nil
+}
+
+// compile compiles the registered models.
+func (r *runner) compile() (*trenovaorm.Schema, error) {
+	models := trenovaorm.Models()
+	if len(models) == 0 {
+		return nil, fmt.Errorf("no models registered; pass -schema <dir> and run '%s -schema <dir> generate registry' first", programName)
+	}
+	return trenovaorm.Compile(models...)
+}
+
+// output returns the writer for the -out flag value, or stdout when it is empty.
+func (r *runner) output(path string) (io.Writer, func() error, error) {
+	if path == "" || path == "-" {
+		return r.stdout, func() error { return nil }, nil
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, f.Close, nil
+}
@@ -0,0 +1,198 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	trenovaorm "github.com/emoss08/trenova-orm"
+)
+
+type user struct {
+	trenovaorm.BaseModel
+}
+
+func (user) TableName() string {
+	return "users"
+}
+
+func (user) Fields() []trenovaorm.Field {
+	return []trenovaorm.Field{
+		&trenovaorm.UUIDField{ColumnName: "id", PrimaryKey: true, Comment: "Identifier"},
+	}
+}
+
+type userIDs struct {
+	trenovaorm.BaseModel
+}
+
+func (userIDs) TableName() string {
+	return "user_ids"
+}
+
+func (userIDs) View() trenovaorm.View {
+	return trenovaorm.View{Query: trenovaorm.Select(&user{}, "id")}
+}
+
+func init() {
+	trenovaorm.Register(&user{})
+	trenovaorm.Register(&userIDs{})
+}
+
+func run(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := Main(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestMain_Commands(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantStdout string
+		wantStderr string
+	}{
+		{
+			name:       "No arguments prints usage",
+			args:       nil,
+			wantCode:   ExitUsage,
+			wantStderr: "Usage: trenova-orm",
+		},
+		{
+			name:       "Unknown command",
+			args:       []string{"generate", "nothing"},
+			wantCode:   ExitUsage,
+			wantStderr: `unknown command "generate nothing"`,
+		},
+		{
+			name:       "Unknown flag",
+			args:       []string{"validate", "-x"},
+			wantCode:   ExitUsage,
+			wantStderr: "flag provided but not defined: -x",
+		},
+		{
+			name:     "Generate SQL",
+			args:     []string{"generate", "sql"},
+			wantCode: ExitOK,
+			wantStdout: "CREATE TABLE IF NOT EXISTS \"users\" (\"id\" uuid NOT NULL PRIMARY KEY);\n" +
+				"COMMENT ON COLUMN \"users\".\"id\" IS 'Identifier';\n",
+		},
+		{
+			name:       "Generate repositories",
+			args:       []string{"generate", "repositories"},
+			wantCode:   ExitOK,
+			wantStdout: "func NewUsersRepository(db DBTX) *UsersRepository {",
+		},
+		{
+			name:       "Validate",
+			args:       []string{"validate"},
+			wantCode:   ExitOK,
+			wantStdout: "ok: 2 models",
+		},
+		{
+			name:       "Migrate diff requires a name",
+			args:       []string{"migrate", "diff"},
+			wantCode:   ExitUsage,
+			wantStderr: "-name is required",
+		},
+		{
+			name:       "Migrate status requires a database",
+			args:       []string{"migrate", "status", "-database", ""},
+			wantCode:   ExitUsage,
+			wantStderr: "database connection string is required",
+		},
+		{
+			name:       "Generate registry requires a schema",
+			args:       []string{"generate", "registry"},
+			wantCode:   ExitUsage,
+			wantStderr: "-schema is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := run(tt.args...)
+			if code != tt.wantCode {
+				t.Errorf("Main() = %d, want %d (stderr: %s)", code, tt.wantCode, stderr)
+			}
+			if !strings.Contains(stdout, tt.wantStdout) {
+				t.Errorf("Main() stdout = %q, want it to contain %q", stdout, tt.wantStdout)
+			}
+			if !strings.Contains(stderr, tt.wantStderr) {
+				t.Errorf("Main() stderr = %q, want it to contain %q", stderr, tt.wantStderr)
+			}
+		})
+	}
+}
+
+func TestMain_OutputFile(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "schema.sql")
+
+	code, _, stderr := run("generate", "sql", "-out", out)
+	if code != ExitOK {
+		t.Fatalf("Main() = %d, want %d (stderr: %s)", code, ExitOK, stderr)
+	}
+
+	content, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(content), `CREATE TABLE IF NOT EXISTS "users"`) {
+		t.Errorf("output file = %q, want the CREATE TABLE statement", content)
+	}
+}
+
+func TestWriteRegistry(t *testing.T) {
+	dir := t.TempDir()
+	src := `package models
+
+import trenovaorm "github.com/emoss08/trenova-orm"
+
+type User struct{ trenovaorm.BaseModel }
+
+func (User) TableName() string { return "users" }
+
+type Role struct{ trenovaorm.BaseModel }
+
+func (r *Role) TableName() string { return "roles" }
+
+type helper struct{}
+
+func (helper) TableName() string { return "" }
+`
+	if err := os.WriteFile(filepath.Join(dir, "models.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	path, count, err := writeRegistry(dir)
+	if err != nil {
+		t.Fatalf("writeRegistry() error = %v", err)
+	}
+	if count != 2 {
+		t.Errorf("writeRegistry() registered %d models, want 2", count)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `// Code generated by trenova-orm. DO NOT EDIT.
+
+package models
+
+import trenovaorm "github.com/emoss08/trenova-orm"
+
+func init() {
+	trenovaorm.Register(
+		&Role{},
+		&User{},
+	)
+}
+`
+	if string(got) != want {
+		t.Errorf("registry file =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,399 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/emoss08/trenova-orm/gen"
+	"github.com/emoss08/trenova-orm/introspect"
+	"github.com/emoss08/trenova-orm/migrate"
+
+	// Register the PostgreSQL driver used by the migrate and inspect commands.
+	_ "github.com/lib/pq"
+)
+
+const (
+	defaultMigrationsDir = "migrations"
+	databaseEnv          = "DATABASE_URL"
+)
+
+// generateSQL writes the DDL plan of the registered models.
+func (r *runner) generateSQL(args []string) error {
+	fs := newFlagSet("generate sql")
+	out := fs.String("out", "", "output file (default stdout)")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	schema, err := r.compile()
+	if err != nil {
+		return err
+	}
+	script, err := schema.SQL()
+	if err != nil {
+		return err
+	}
+
+	return r.write(*out, script)
+}
+
+// generateStructs writes one Go file per registered model and per enum type, or
+// prints them all when -dir is empty.
+func (r *runner) generateStructs(args []string) error {
+	fs := newFlagSet("generate structs")
+	dir := fs.String("dir", "", "output directory (default stdout)")
+	pkg := fs.String("package", "", "package name of the generated files (default the -dir base name)")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	pkgName, err := packageName("generate structs", *dir, *pkg)
+	if err != nil {
+		return err
+	}
+
+	schema, err := r.compile()
+	if err != nil {
+		return err
+	}
+
+	for _, enum := range schema.Enums {
+		src, err := gen.EnumFile(pkgName, enum)
+		if err != nil {
+			return err
+		}
+		if err := r.writeSource(*dir, gen.EnumFileName(enum), src); err != nil {
+			return err
+		}
+	}
+
+	for _, table := range schema.Tables {
+		src, err := gen.File(pkgName, table)
+		if err != nil {
+			return fmt.Errorf("table %s: %w", table.Name, err)
+		}
+		if err := r.writeSource(*dir, gen.FileName(table.Model), src); err != nil {
+			return err
+		}
+	}
+
+	for _, view := range schema.Views {
+		src, err := gen.ViewFile(pkgName, view)
+		if err != nil {
+			return fmt.Errorf("view %s: %w", view.Name, err)
+		}
+		if err := r.writeSource(*dir, gen.FileName(view.Model), src); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generateRepositories writes a repository and its test for every model, along
+// with the helpers they share.
+func (r *runner) generateRepositories(args []string) error {
+	fs := newFlagSet("generate repositories")
+	dir := fs.String("dir", "", "output directory (default stdout)")
+	pkg := fs.String("package", "", "package name of the generated files (default the -dir base name)")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	pkgName, err := packageName("generate repositories", *dir, *pkg)
+	if err != nil {
+		return err
+	}
+
+	schema, err := r.compile()
+	if err != nil {
+		return err
+	}
+
+	src, err := gen.DBFile(pkgName)
+	if err != nil {
+		return err
+	}
+	if err := r.writeSource(*dir, gen.DBFileName, src); err != nil {
+		return err
+	}
+
+	for _, table := range schema.Tables {
+		src, err := gen.Repository(pkgName, table)
+		if err != nil {
+			return fmt.Errorf("table %s: %w", table.Name, err)
+		}
+		if err := r.writeSource(*dir, gen.RepositoryFileName(table.Model), src); err != nil {
+			return err
+		}
+
+		test, err := gen.RepositoryTest(pkgName, table)
+		if err != nil {
+			return fmt.Errorf("table %s: %w", table.Name, err)
+		}
+		if err := r.writeSource(*dir, gen.RepositoryTestFileName(table.Model), test); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// packageName returns the package name of generated files: the -package flag,
+// the base name of the output directory, or "models".
+func packageName(command, dir, pkg string) (string, error) {
+	name := pkg
+	if name == "" {
+		name = "models"
+		if dir != "" {
+			name = filepath.Base(dir)
+		}
+	}
+	if !token.IsIdentifier(name) {
+		return "", usagef("%s: invalid package name %q", command, name)
+	}
+	return name, nil
+}
+
+// writeSource writes a generated file into dir, or prints it to stdout when dir is empty.
+func (r *runner) writeSource(dir, name string, src []byte) error {
+	if dir == "" {
+		fmt.Fprintf(r.stdout, "// %s\n%s\n", name, src)
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, src, 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintf(r.stdout, "wrote %s\n", path)
+	return nil
+}
+
+// generateRegistry writes the registry file into the schema package.
+func (r *runner) generateRegistry(args []string) error {
+	fs := newFlagSet("generate registry")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if r.schema == "" {
+		return usagef("generate registry: -schema is required")
+	}
+
+	path, models, err := writeRegistry(r.schema)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(r.stdout, "wrote %s (%d models)\n", path, models)
+	return nil
+}
+
+// migrateDiff writes a migration from the live database schemas to the registered models.
+func (r *runner) migrateDiff(args []string) error {
+	fs := newFlagSet("migrate diff")
+	dir := fs.String("dir", defaultMigrationsDir, "migrations directory")
+	name := fs.String("name", "", "migration name")
+	dsn := fs.String("database", os.Getenv(databaseEnv), "PostgreSQL connection string (default $"+databaseEnv+")")
+	pgSchema := fs.String("pg-schema", introspect.DefaultSchema, "PostgreSQL schema to compare against, besides those of the models")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if *name == "" {
+		return usagef("migrate diff: -name is required")
+	}
+
+	to, err := r.compile()
+	if err != nil {
+		return err
+	}
+
+	db, err := openDatabase(*dsn)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// Every schema the models use is compared, so that no model is left out of
+	// the migration.
+	schemas := append([]string{*pgSchema}, introspect.Schemas(to)...)
+	from, err := introspect.Compile(context.Background(), db, schemas...)
+	if err != nil {
+		return err
+	}
+
+	migrations, err := migrate.Generate(*dir, *name, introspect.WithViews(from, to), to)
+	if errors.Is(err, migrate.ErrNoChanges) {
+		fmt.Fprintln(r.stdout, "no changes")
+		return nil
+	}
+	for _, mig := range migrations {
+		fmt.Fprintf(r.stdout, "wrote migration %06d_%s\n", mig.Version, mig.Name)
+	}
+	return err
+}
+
+// migrateUp applies pending migrations.
+func (r *runner) migrateUp(args []string) error {
+	fs := newFlagSet("migrate up")
+	dir := fs.String("dir", defaultMigrationsDir, "migrations directory")
+	dsn := fs.String("database", os.Getenv(databaseEnv), "PostgreSQL connection string (default $"+databaseEnv+")")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	return r.withMigrator(*dir, *dsn, func(m *migrate.Migrator) error {
+		applied, err := m.Up(context.Background())
+		for _, mig := range applied {
+			fmt.Fprintf(r.stdout, "applied %06d_%s\n", mig.Version, mig.Name)
+		}
+		return err
+	})
+}
+
+// migrateDown reverts the most recently applied migrations.
+func (r *runner) migrateDown(args []string) error {
+	fs := newFlagSet("migrate down")
+	dir := fs.String("dir", defaultMigrationsDir, "migrations directory")
+	dsn := fs.String("database", os.Getenv(databaseEnv), "PostgreSQL connection string (default $"+databaseEnv+")")
+	steps := fs.Int("steps", 1, "number of migrations to revert")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if *steps <= 0 {
+		return usagef("migrate down: -steps must be positive")
+	}
+
+	return r.withMigrator(*dir, *dsn, func(m *migrate.Migrator) error {
+		reverted, err := m.Down(context.Background(), *steps)
+		for _, mig := range reverted {
+			fmt.Fprintf(r.stdout, "reverted %06d_%s\n", mig.Version, mig.Name)
+		}
+		return err
+	})
+}
+
+// migrateStatus prints the status of every migration.
+func (r *runner) migrateStatus(args []string) error {
+	fs := newFlagSet("migrate status")
+	dir := fs.String("dir", defaultMigrationsDir, "migrations directory")
+	dsn := fs.String("database", os.Getenv(databaseEnv), "PostgreSQL connection string (default $"+databaseEnv+")")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	return r.withMigrator(*dir, *dsn, func(m *migrate.Migrator) error {
+		statuses, err := m.Status(context.Background())
+		if err != nil {
+			return err
+		}
+
+		tw := tabwriter.NewWriter(r.stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, s := range statuses {
+			state, appliedAt := "pending", "-"
+			if s.Applied {
+				state, appliedAt = "applied", s.AppliedAt.UTC().Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(tw, "%06d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+		}
+		return tw.Flush()
+	})
+}
+
+// inspect writes the DDL of a live database schema.
+func (r *runner) inspect(args []string) error {
+	fs := newFlagSet("inspect")
+	dsn := fs.String("database", os.Getenv(databaseEnv), "PostgreSQL connection string (default $"+databaseEnv+")")
+	pgSchema := fs.String("pg-schema", introspect.DefaultSchema, "PostgreSQL schema to inspect")
+	out := fs.String("out", "", "output file (default stdout)")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	db, err := openDatabase(*dsn)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	schema, err := introspect.Compile(context.Background(), db, *pgSchema)
+	if err != nil {
+		return err
+	}
+	script, err := schema.SQL()
+	if err != nil {
+		return err
+	}
+
+	return r.write(*out, script)
+}
+
+// validate compiles the registered models and reports the first error found.
+func (r *runner) validate(args []string) error {
+	fs := newFlagSet("validate")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	schema, err := r.compile()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(r.stdout, "ok: %d models\n", len(schema.Tables)+len(schema.Views))
+	return nil
+}
+
+// write writes content to the output selected by the -out flag.
+func (r *runner) write(path, content string) error {
+	w, closeFn, err := r.output(path)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprint(w, content); err != nil {
+		_ = closeFn()
+		return err
+	}
+	return closeFn()
+}
+
+// withMigrator loads the migrations in dir and runs fn with a Migrator connected to dsn.
+func (r *runner) withMigrator(dir, dsn string, fn func(m *migrate.Migrator) error) error {
+	db, err := openDatabase(dsn)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrations, err := migrate.Load(dir)
+	if err != nil {
+		return err
+	}
+
+	m, err := migrate.New(db, migrations)
+	if err != nil {
+		return err
+	}
+	return fn(m)
+}
+
+// openDatabase opens and pings a PostgreSQL connection.
+func openDatabase(dsn string) (*sql.DB, error) {
+	if dsn == "" {
+		return nil, usagef("a database connection string is required; pass -database or set $%s", databaseEnv)
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.PingContext(context.Background()); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("connect to database: %w", err)
+	}
+	return db, nil
+}
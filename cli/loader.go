@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+var loaderTemplate = template.Must(template.New("loader").Parse(`// Code generated by trenova-orm. DO NOT EDIT.
+
+package main
+
+import (
+	"os"
+
+	"github.com/emoss08/trenova-orm/cli"
+
+	_ "{{ . }}"
+)
+
+func main() {
+	os.Exit(cli.Main(os.Args[1:], os.Stdout, os.Stderr))
+}
+`))
+
+// runLoaded re-runs the tool through "go run" with the schema package linked in,
+// from the current directory so the caller's module resolves the import.
+func (r *runner) runLoaded(args []string) int {
+	importPath, err := resolveImportPath(r.schema)
+	if err != nil {
+		return r.fail(err)
+	}
+
+	var src bytes.Buffer
+	if err := loaderTemplate.Execute(&src, importPath); err != nil {
+		return r.fail(err)
+	}
+
+	dir, err := os.MkdirTemp("", "trenova-orm-")
+	if err != nil {
+		return r.fail(err)
+	}
+	defer os.RemoveAll(dir)
+
+	mainFile := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(mainFile, src.Bytes(), 0o600); err != nil {
+		return r.fail(err)
+	}
+
+	cmd := exec.Command("go", append([]string{"run", mainFile}, args...)...)
+	cmd.Env = append(os.Environ(), loadedEnv+"=1")
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = r.stdout
+	cmd.Stderr = r.stderr
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode()
+		}
+		return r.fail(fmt.Errorf("load schema package %s: %w", r.schema, err))
+	}
+	return ExitOK
+}
+
+// resolveImportPath returns the import path of the package in dir.
+func resolveImportPath(dir string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("go", "list", "-f", "{{.ImportPath}}", dir)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("resolve schema package %s: %s", dir, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// registryFile is the name of the generated registry file in the schema package.
+const registryFile = "registry_gen.go"
+
+// writeRegistry scans the package in dir for model types, recognized by a
+// TableName method, and writes a file that registers them in an init function.
+// It returns the written path and the number of models registered.
+func writeRegistry(dir string) (string, int, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go") && fi.Name() != registryFile
+	}, 0)
+	if err != nil {
+		return "", 0, err
+	}
+	if len(pkgs) != 1 {
+		return "", 0, fmt.Errorf("expected exactly one package in %s, found %d", dir, len(pkgs))
+	}
+
+	var pkgName string
+	var models []string
+	for name, pkg := range pkgs {
+		pkgName = name
+		models = modelTypes(pkg)
+	}
+	if len(models) == 0 {
+		return "", 0, fmt.Errorf("no models found in %s; models must declare a TableName method", dir)
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by trenova-orm. DO NOT EDIT.\n\npackage %s\n\n", pkgName)
+	fmt.Fprintf(&src, "import trenovaorm \"github.com/emoss08/trenova-orm\"\n\n")
+	fmt.Fprintf(&src, "func init() {\n\ttrenovaorm.Register(\n")
+	for _, model := range models {
+		fmt.Fprintf(&src, "\t\t&%s{},\n", model)
+	}
+	fmt.Fprintf(&src, "\t)\n}\n")
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return "", 0, err
+	}
+
+	path := filepath.Join(dir, registryFile)
+	if err := os.WriteFile(path, formatted, 0o644); err != nil {
+		return "", 0, err
+	}
+	return path, len(models), nil
+}
+
+// modelTypes returns the sorted names of the package's struct types that declare a TableName method.
+func modelTypes(pkg *ast.Package) []string {
+	structs := make(map[string]bool)
+	withTableName := make(map[string]bool)
+
+	for _, file := range pkg.Files {
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					if ts, ok := spec.(*ast.TypeSpec); ok && ts.TypeParams == nil {
+						if _, ok := ts.Type.(*ast.StructType); ok {
+							structs[ts.Name.Name] = true
+						}
+					}
+				}
+			case *ast.FuncDecl:
+				if d.Recv == nil || len(d.Recv.List) != 1 || d.Name.Name != "TableName" {
+					continue
+				}
+				recv := d.Recv.List[0].Type
+				if star, ok := recv.(*ast.StarExpr); ok {
+					recv = star.X
+				}
+				if ident, ok := recv.(*ast.Ident); ok {
+					withTableName[ident.Name] = true
+				}
+			}
+		}
+	}
+
+	var names []string
+	for name := range withTableName {
+		if structs[name] && ast.IsExported(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,338 @@
+package trenovaorm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Column is a normalized description of a table column, used to compare schemas.
+type Column struct {
+	Name     string
+	Type     string // SQL type of the column
+	Nullable bool
+	Default  string // SQL default expression, empty when the column has no default
+	Unique   bool
+	Comment  string
+
+	// PrimaryKey reports whether the column is part of the table's primary key.
+	PrimaryKey bool
+
+	// Serial reports whether the column is serial. Type then holds the
+	// underlying integer type, as serial is a sequence default rather than a
+	// type.
+	Serial bool
+
+	// ServerDefault reports whether the database generates the value, either
+	// through a function Default such as uuid_generate_v4() rather than a
+	// literal value, or because the column is serial or an identity.
+	ServerDefault bool
+
+	// Identity is the identity of the column, with options equal to the
+	// sequence defaults cleared.
+	Identity Identity
+
+	// Generated is the expression of a stored generated column, empty for
+	// other columns.
+	Generated string
+
+	// Constraints holds the raw column constraints other than PRIMARY KEY,
+	// which is reported by PrimaryKey, as they are rendered.
+	Constraints string
+}
+
+// ColumnOf describes the column produced by the given field.
+func ColumnOf(field Field) Column {
+	switch f := field.(type) {
+	case *CharField:
+		col := Column{
+			Name:        f.ColumnName,
+			Type:        columnType(f.CustomType, fmt.Sprintf("VARCHAR(%d)", f.MaxLength)),
+			Nullable:    f.Nullable || f.Blank,
+			Unique:      f.Unique,
+			Comment:     f.Comment,
+			PrimaryKey:  hasConstraint(f.Constraints, ConstraintPrimaryKey),
+			Constraints: rawConstraints(f.Constraints),
+		}
+		if f.Default != "" {
+			col.Default = QuoteLiteral(f.Default)
+		}
+		return col
+	case *TextField:
+		col := Column{
+			Name:        f.ColumnName,
+			Type:        columnType(f.CustomType, "TEXT"),
+			Nullable:    f.Nullable || f.Blank,
+			Unique:      f.Unique,
+			Comment:     f.Comment,
+			PrimaryKey:  hasConstraint(f.Constraints, ConstraintPrimaryKey),
+			Constraints: rawConstraints(f.Constraints),
+		}
+		if f.Default != "" {
+			col.Default = QuoteLiteral(f.Default)
+		}
+		return col
+	case *JSONField:
+		col := Column{
+			Name:        f.ColumnName,
+			Type:        columnType(f.CustomType, "JSONB"),
+			Nullable:    f.Nullable,
+			Unique:      f.Unique,
+			Comment:     f.Comment,
+			PrimaryKey:  hasConstraint(f.Constraints, ConstraintPrimaryKey),
+			Constraints: rawConstraints(f.Constraints),
+		}
+		if f.Default != "" {
+			col.Default = QuoteLiteral(f.Default)
+		}
+		return col
+	case *BooleanField:
+		col := Column{
+			Name:        f.ColumnName,
+			Type:        columnType(f.CustomType, "BOOLEAN"),
+			Nullable:    f.Nullable,
+			Unique:      f.Unique,
+			Comment:     f.Comment,
+			Default:     "FALSE",
+			PrimaryKey:  hasConstraint(f.Constraints, ConstraintPrimaryKey),
+			Constraints: rawConstraints(f.Constraints),
+		}
+		if f.Default {
+			col.Default = "TRUE"
+		}
+		return col
+	case *IntegerField:
+		col := Column{
+			Name:          f.ColumnName,
+			Type:          columnType(f.CustomType, "INTEGER"),
+			Serial:        f.Serial && f.CustomType == "",
+			Nullable:      f.Nullable,
+			Unique:        f.Unique,
+			Comment:       f.Comment,
+			Identity:      f.Identity.normalized(),
+			ServerDefault: f.Serial || !f.Identity.IsZero(),
+			PrimaryKey:    f.PrimaryKey || hasConstraint(f.Constraints, ConstraintPrimaryKey),
+			Constraints:   rawConstraints(f.Constraints),
+		}
+		if f.Default != 0 {
+			col.Default = strconv.Itoa(f.Default)
+		}
+		return col
+	case *BigIntegerField:
+		col := Column{
+			Name:          f.ColumnName,
+			Type:          columnType(f.CustomType, "BIGINT"),
+			Serial:        f.Serial && f.CustomType == "",
+			Nullable:      f.Nullable,
+			Unique:        f.Unique,
+			Comment:       f.Comment,
+			Identity:      f.Identity.normalized(),
+			ServerDefault: f.Serial || !f.Identity.IsZero(),
+			PrimaryKey:    f.PrimaryKey || hasConstraint(f.Constraints, ConstraintPrimaryKey),
+			Constraints:   rawConstraints(f.Constraints),
+		}
+		if f.Default != 0 {
+			col.Default = strconv.FormatInt(f.Default, 10)
+		}
+		return col
+	case *SmallIntegerField:
+		col := Column{
+			Name:          f.ColumnName,
+			Type:          columnType(f.CustomType, "SMALLINT"),
+			Serial:        f.Serial && f.CustomType == "",
+			Nullable:      f.Nullable,
+			Unique:        f.Unique,
+			Comment:       f.Comment,
+			Identity:      f.Identity.normalized(),
+			ServerDefault: f.Serial || !f.Identity.IsZero(),
+			PrimaryKey:    f.PrimaryKey || hasConstraint(f.Constraints, ConstraintPrimaryKey),
+			Constraints:   rawConstraints(f.Constraints),
+		}
+		if f.Default != 0 {
+			col.Default = strconv.Itoa(int(f.Default))
+		}
+		return col
+	case *PositiveIntegerField:
+		col := Column{
+			Name:        f.ColumnName,
+			Type:        columnType(f.CustomType, "INTEGER"),
+			Nullable:    f.Nullable,
+			Unique:      f.Unique,
+			Comment:     f.Comment,
+			PrimaryKey:  f.PrimaryKey || hasConstraint(f.Constraints, ConstraintPrimaryKey),
+			Constraints: rawConstraints(f.Constraints),
+		}
+		if f.Default != 0 {
+			col.Default = strconv.Itoa(f.Default)
+		}
+		return col
+	case *NumericField:
+		col := Column{
+			Name:        f.ColumnName,
+			Type:        columnType(f.CustomType, fmt.Sprintf("NUMERIC(%d, %d)", f.Precision, f.Scale)),
+			Nullable:    f.Nullable,
+			Unique:      f.Unique,
+			Comment:     f.Comment,
+			PrimaryKey:  hasConstraint(f.Constraints, ConstraintPrimaryKey),
+			Constraints: rawConstraints(f.Constraints),
+		}
+		if f.Default != 0 {
+			col.Default = fmt.Sprintf("%.*f", f.Scale, f.Default)
+		}
+		return col
+	case *DateField:
+		return Column{
+			Name:          f.ColumnName,
+			Type:          columnType(f.CustomType, "DATE"),
+			Nullable:      f.Nullable,
+			Unique:        f.Unique,
+			Comment:       f.Comment,
+			Default:       f.Default.String(),
+			ServerDefault: f.Default != "",
+			PrimaryKey:    hasConstraint(f.Constraints, ConstraintPrimaryKey),
+			Constraints:   rawConstraints(f.Constraints),
+		}
+	case *TimestampField:
+		return Column{
+			Name:          f.ColumnName,
+			Type:          f.sqlType(),
+			Nullable:      f.Nullable,
+			Unique:        f.Unique,
+			Comment:       f.Comment,
+			Default:       f.Default.String(),
+			ServerDefault: f.Default != "",
+			PrimaryKey:    hasConstraint(f.Constraints, ConstraintPrimaryKey),
+			Constraints:   rawConstraints(f.Constraints),
+		}
+	case *IntervalField:
+		col := Column{
+			Name:        f.ColumnName,
+			Type:        f.sqlType(),
+			Nullable:    f.Nullable,
+			Unique:      f.Unique,
+			Comment:     f.Comment,
+			PrimaryKey:  hasConstraint(f.Constraints, ConstraintPrimaryKey),
+			Constraints: rawConstraints(f.Constraints),
+		}
+		if f.Default != 0 {
+			col.Default = QuoteLiteral(formatInterval(f.Default))
+		}
+		return col
+	case *TimeField:
+		return Column{
+			Name:          f.ColumnName,
+			Type:          columnType(f.CustomType, "TIME"),
+			Nullable:      f.Nullable,
+			Unique:        f.Unique,
+			Comment:       f.Comment,
+			Default:       f.Default.String(),
+			ServerDefault: f.Default != "",
+			PrimaryKey:    hasConstraint(f.Constraints, ConstraintPrimaryKey),
+			Constraints:   rawConstraints(f.Constraints),
+		}
+	case *UUIDField:
+		return Column{
+			Name:          f.ColumnName,
+			Type:          columnType(f.CustomType, "uuid"),
+			Nullable:      f.Nullable,
+			Unique:        f.Unique,
+			Comment:       f.Comment,
+			Default:       f.Default.String(),
+			ServerDefault: f.Default != "",
+			PrimaryKey:    f.PrimaryKey || hasConstraint(f.Constraints, ConstraintPrimaryKey),
+			Constraints:   rawConstraints(f.Constraints),
+		}
+	case *EnumField:
+		col := Column{
+			Name:        f.ColumnName,
+			Type:        QuoteQualifiedName(f.Enum().QualifiedName()),
+			Nullable:    f.Nullable,
+			Unique:      f.Unique,
+			Comment:     f.Comment,
+			PrimaryKey:  hasConstraint(f.Constraints, ConstraintPrimaryKey),
+			Constraints: rawConstraints(f.Constraints),
+		}
+		if f.Default != "" {
+			col.Default = QuoteLiteral(f.Default)
+		}
+		return col
+	case *ArrayField:
+		col := Column{
+			Name:        f.ColumnName,
+			Type:        f.sqlType(),
+			Nullable:    f.Nullable,
+			Comment:     f.Comment,
+			PrimaryKey:  hasConstraint(f.Constraints, ConstraintPrimaryKey),
+			Constraints: rawConstraints(f.Constraints),
+		}
+		if f.Default != nil {
+			col.Default = arrayLiteral(f.Default)
+		}
+		return col
+	case *GeneratedField:
+		if f.Field == nil || f.Expression == nil {
+			return Column{Name: field.Name()}
+		}
+		col := ColumnOf(f.Field)
+		col.Generated = f.Expression.Expression()
+		col.Constraints = "" // Not rendered for generated columns
+		col.Default = ""     // Drops the implicit FALSE of a BooleanField
+		return col
+	case *ViewField:
+		if f.Source == nil {
+			return Column{Name: field.Name()}
+		}
+		src := ColumnOf(f.Source)
+		return Column{
+			Name:     f.ColumnName,
+			Type:     src.Type,
+			Nullable: src.Nullable || f.Nullable,
+			Comment:  f.Comment,
+		}
+	case *ForeignKeyField:
+		col := Column{
+			Name:        f.ColumnName,
+			Type:        columnType(f.CustomType, "INTEGER"),
+			Nullable:    f.Nullable,
+			Unique:      f.Unique,
+			Comment:     f.Comment,
+			PrimaryKey:  hasConstraint(f.Constraints, ConstraintPrimaryKey),
+			Constraints: rawConstraints(f.Constraints),
+		}
+		if f.Default != "" {
+			col.Default = QuoteLiteral(f.Default)
+		}
+		return col
+	default:
+		return Column{Name: field.Name()}
+	}
+}
+
+// hasConstraint reports whether the raw column constraints include the given constraint.
+func hasConstraint(constraints []string, constraint Constraint) bool {
+	for _, c := range constraints {
+		if strings.EqualFold(strings.TrimSpace(c), constraint.String()) {
+			return true
+		}
+	}
+	return false
+}
+
+// rawConstraints joins the raw column constraints other than PRIMARY KEY.
+func rawConstraints(constraints []string) string {
+	var raw []string
+	for _, c := range constraints {
+		if !strings.EqualFold(strings.TrimSpace(c), ConstraintPrimaryKey.String()) {
+			raw = append(raw, strings.TrimSpace(c))
+		}
+	}
+	return strings.Join(raw, " ")
+}
+
+// columnType returns the custom type if one is set, otherwise the field's default type.
+func columnType(customType, defaultType string) string {
+	if customType != "" {
+		return customType
+	}
+	return defaultType
+}
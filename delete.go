@@ -0,0 +1,47 @@
+package trenovaorm
+
+import "fmt"
+
+// DeleteQuery builds a parameterized DELETE statement for the table of a model.
+type DeleteQuery struct {
+	model     Model
+	scope     queryScope
+	where     []Predicate
+	returning []string
+}
+
+// Delete starts a DELETE statement for the table of model.
+func Delete(model Model) *DeleteQuery {
+	return &DeleteQuery{
+		model: model,
+		scope: queryScope{tables: []queryTable{newQueryTable(model)}},
+	}
+}
+
+// Where adds predicates to the WHERE clause. All predicates must hold.
+func (q *DeleteQuery) Where(preds ...Predicate) *DeleteQuery {
+	q.where = append(q.where, preds...)
+	return q
+}
+
+// Returning sets the columns returned for each deleted row.
+func (q *DeleteQuery) Returning(columns ...string) *DeleteQuery {
+	q.returning = columns
+	return q
+}
+
+// Build returns the DELETE statement with $n placeholders and its arguments,
+// or an error if the query references unknown columns.
+func (q *DeleteQuery) Build() (string, []any, error) {
+	if IsView(q.model) {
+		return "", nil, fmt.Errorf("view %q is read-only", q.model.TableName())
+	}
+	b := &queryBuilder{scope: &q.scope}
+	b.write(fmt.Sprintf("DELETE FROM %s", QuoteQualifiedName(QualifiedTableName(q.model))))
+	if len(q.where) > 0 {
+		b.write(" WHERE ")
+		b.predicates("AND", q.where)
+	}
+	b.returning(q.returning)
+	return b.build()
+}
@@ -0,0 +1,25 @@
+package trenovaorm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDeleteQuery_Build(t *testing.T) {
+	sql, args, err := Delete(queryUsers).Where(Lt("age", 18)).Returning("id").Build()
+	if err != nil {
+		t.Fatalf("DeleteQuery.Build() error = %v", err)
+	}
+
+	wantSQL := `DELETE FROM "users" WHERE "age" < $1 RETURNING "id"`
+	if sql != wantSQL {
+		t.Errorf("DeleteQuery.Build() sql = %v, want %v", sql, wantSQL)
+	}
+	if !reflect.DeepEqual(args, []any{18}) {
+		t.Errorf("DeleteQuery.Build() args = %v, want [18]", args)
+	}
+
+	if _, _, err := Delete(queryUsers).Returning("name").Build(); err == nil {
+		t.Error("DeleteQuery.Build() error = nil, want unknown column error")
+	}
+}
@@ -0,0 +1,99 @@
+package trenovaorm
+
+import "fmt"
+
+// Visit states of createOrder and viewOrder.
+const (
+	unvisited = iota
+	visiting
+	visited
+)
+
+// createOrder orders tables so that each follows the tables its foreign keys
+// reference, keeping declaration order where there is no dependency.
+// References to tables outside the list and self-references impose no order.
+//
+// A foreign key that closes a cycle cannot be declared in CREATE TABLE, since
+// one of the tables it connects does not exist yet. Such keys are returned
+// separately, by table name, to be added once every table exists.
+func createOrder(tables []*Table) ([]*Table, map[string][]ForeignKey) {
+	byName := make(map[string]*Table, len(tables))
+	for _, table := range tables {
+		byName[table.Name] = table
+	}
+
+	state := make(map[string]int, len(tables))
+	ordered := make([]*Table, 0, len(tables))
+	deferred := make(map[string][]ForeignKey)
+
+	var visit func(table *Table)
+	visit = func(table *Table) {
+		state[table.Name] = visiting
+		for _, fk := range table.ForeignKeys {
+			ref, ok := byName[fk.ReferenceTable]
+			if !ok || ref == table {
+				continue
+			}
+			switch state[ref.Name] {
+			case unvisited:
+				visit(ref)
+			case visiting:
+				deferred[table.Name] = append(deferred[table.Name], fk)
+			}
+		}
+		state[table.Name] = visited
+		ordered = append(ordered, table)
+	}
+
+	for _, table := range tables {
+		if state[table.Name] == unvisited {
+			visit(table)
+		}
+	}
+	return ordered, deferred
+}
+
+// viewOrder orders views so that each follows the views it reads, keeping
+// declaration order where there is no dependency. Reads of tables and of
+// views outside the list impose no order. Unlike tables, views cannot form a
+// cycle, so one is reported as an error.
+func viewOrder(views []*CompiledView) ([]*CompiledView, error) {
+	byName := make(map[string]*CompiledView, len(views))
+	for _, view := range views {
+		byName[view.Name] = view
+	}
+
+	state := make(map[string]int, len(views))
+	ordered := make([]*CompiledView, 0, len(views))
+
+	var visit func(view *CompiledView) error
+	visit = func(view *CompiledView) error {
+		state[view.Name] = visiting
+		for _, name := range view.Reads {
+			ref, ok := byName[name]
+			if !ok {
+				continue
+			}
+			switch state[ref.Name] {
+			case unvisited:
+				if err := visit(ref); err != nil {
+					return err
+				}
+			case visiting:
+				return fmt.Errorf("view %s depends on itself through %s", ref.Name, view.Name)
+			}
+		}
+		state[view.Name] = visited
+		ordered = append(ordered, view)
+		return nil
+	}
+
+	for _, view := range views {
+		if state[view.Name] == unvisited {
+			if err := visit(view); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return ordered, nil
+}
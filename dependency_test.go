@@ -0,0 +1,91 @@
+package trenovaorm
+
+import (
+	"slices"
+	"testing"
+)
+
+// departments and employees reference each other, and employees also
+// references itself.
+func departmentModels() []Model {
+	return []Model{
+		&testModel{name: "departments", fields: []Field{
+			&IntegerField{ColumnName: "id", PrimaryKey: true},
+			&ForeignKeyField{ColumnName: "head_id", ReferenceTable: "employees", ReferenceField: "id", Nullable: true},
+		}},
+		&testModel{name: "employees", fields: []Field{
+			&IntegerField{ColumnName: "id", PrimaryKey: true},
+			&ForeignKeyField{ColumnName: "department_id", ReferenceTable: "departments", ReferenceField: "id"},
+			&ForeignKeyField{ColumnName: "manager_id", ReferenceTable: "employees", ReferenceField: "id", Nullable: true},
+		}},
+	}
+}
+
+func TestCreateOrder(t *testing.T) {
+	schema := mustCompile(t,
+		&testModel{name: "shipments", fields: []Field{
+			&IntegerField{ColumnName: "id", PrimaryKey: true},
+			&ForeignKeyField{ColumnName: "customer_id", ReferenceTable: "customers", ReferenceField: "id"},
+			&ForeignKeyField{ColumnName: "route_id", ReferenceTable: "routes", ReferenceField: "id"},
+		}},
+		&testModel{name: "customers", fields: []Field{
+			&IntegerField{ColumnName: "id", PrimaryKey: true},
+			&ForeignKeyField{ColumnName: "region_id", ReferenceTable: "regions", ReferenceField: "id"},
+		}},
+		&testModel{name: "regions", fields: []Field{&IntegerField{ColumnName: "id", PrimaryKey: true}}},
+		&testModel{name: "routes", fields: []Field{&IntegerField{ColumnName: "id", PrimaryKey: true}}},
+	)
+
+	ordered, deferred := createOrder(schema.Tables)
+	var got []string
+	for _, table := range ordered {
+		got = append(got, table.Name)
+	}
+	if want := []string{"regions", "customers", "routes", "shipments"}; !slices.Equal(got, want) {
+		t.Errorf("createOrder() = %v, want %v", got, want)
+	}
+	if len(deferred) != 0 {
+		t.Errorf("createOrder() deferred = %v, want none", deferred)
+	}
+}
+
+func TestSchema_StatementsCycle(t *testing.T) {
+	got, err := mustCompile(t, departmentModels()...).Statements()
+	if err != nil {
+		t.Fatalf("Schema.Statements() error = %v", err)
+	}
+	assertStatements(t, got, []string{
+		`CREATE TABLE IF NOT EXISTS "employees" ("id" INTEGER NOT NULL PRIMARY KEY, "department_id" INTEGER NOT NULL, "manager_id" INTEGER, ` +
+			`CONSTRAINT "employees_manager_id_fkey" FOREIGN KEY ("manager_id") REFERENCES "employees"("id"));`,
+		`CREATE TABLE IF NOT EXISTS "departments" ("id" INTEGER NOT NULL PRIMARY KEY, "head_id" INTEGER, ` +
+			`CONSTRAINT "departments_head_id_fkey" FOREIGN KEY ("head_id") REFERENCES "employees"("id"));`,
+		`ALTER TABLE "employees" ADD CONSTRAINT "employees_department_id_fkey" FOREIGN KEY ("department_id") REFERENCES "departments"("id");`,
+	})
+}
+
+func TestDiff_Cycle(t *testing.T) {
+	empty := mustCompile(t)
+	schema := mustCompile(t, departmentModels()...)
+
+	created, err := Diff(empty, schema)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	statements, err := schema.Statements()
+	if err != nil {
+		t.Fatalf("Schema.Statements() error = %v", err)
+	}
+	if got, want := statementSQL(created), statementSQL(statements); !slices.Equal(got, want) {
+		t.Errorf("Diff() = %v, want %v", got, want)
+	}
+
+	dropped, err := Diff(schema, empty)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	assertStatements(t, dropped, []string{
+		`ALTER TABLE "employees" DROP CONSTRAINT IF EXISTS "employees_department_id_fkey";`,
+		`DROP TABLE IF EXISTS "departments";`,
+		`DROP TABLE IF EXISTS "employees";`,
+	})
+}
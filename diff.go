@@ -0,0 +1,736 @@
+package trenovaorm
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// migration accumulates the statements of a diff, grouped by phase so that the
+// final plan drops dependent objects before the objects they depend on and
+// creates them afterwards.
+type migration struct {
+	createExtensions []Statement
+	createSchemas    []Statement
+	dropViews        []Statement
+	dropForeignKeys  []Statement
+	dropConstraints  []Statement
+	dropIndexes      []Statement
+	createTypes      []Statement
+	createTables     []Statement
+	alterColumns     []Statement
+	createPartitions []Statement
+	dropTables       []Statement
+	dropTypes        []Statement
+	addConstraints   []Statement
+	addForeignKeys   []Statement
+	comments         []Statement
+	createIndexes    []Statement
+	createViews      []Statement
+}
+
+func (m *migration) statements() []Statement {
+	var stmts []Statement
+	stmts = append(stmts, m.createExtensions...)
+	stmts = append(stmts, m.createSchemas...)
+	stmts = append(stmts, m.dropViews...)
+	stmts = append(stmts, m.dropForeignKeys...)
+	stmts = append(stmts, m.dropConstraints...)
+	stmts = append(stmts, m.dropIndexes...)
+	stmts = append(stmts, m.createTypes...)
+	stmts = append(stmts, m.createTables...)
+	stmts = append(stmts, m.alterColumns...)
+	stmts = append(stmts, m.createPartitions...)
+	stmts = append(stmts, m.dropTables...)
+	stmts = append(stmts, m.dropTypes...)
+	stmts = append(stmts, m.addConstraints...)
+	stmts = append(stmts, m.addForeignKeys...)
+	stmts = append(stmts, m.comments...)
+	stmts = append(stmts, m.createIndexes...)
+	stmts = append(stmts, m.createViews...)
+	return stmts
+}
+
+// Diff compares two compiled schemas and returns the ordered statements that
+// migrate a database from the from schema to the to schema. Tables and columns
+// are visited in declaration order so the output is deterministic.
+//
+// Values added to an enum type cannot be used in the transaction that adds
+// them, so a migration that adds a value should not also use it, e.g. as a default.
+// The raw Constraints of a column are rendered verbatim, so changes to them
+// cannot be migrated and are reported as an error.
+// Schemas and extensions that are no longer used are not dropped, since they
+// may hold objects the models do not describe.
+//
+// PostgreSQL refuses to drop or change the type of a column a view reads, so
+// views whose definition changes or that read a changed table are dropped
+// before the tables are altered and created again afterwards.
+//
+// Indexes declared Concurrently are built and dropped concurrently on tables
+// that already exist. Those statements are marked Concurrent and must not run
+// in the transaction of the other statements; migrate.Generate writes them as
+// migrations of their own.
+func Diff(from, to *Schema) ([]Statement, error) {
+	m := &migration{}
+
+	for _, ext := range to.Extensions() {
+		if !slices.Contains(from.Extensions(), ext) {
+			m.createExtensions = append(m.createExtensions, Statement{Kind: StatementCreateExtension, SQL: ext.CreateSQL()})
+		}
+	}
+
+	for _, schema := range to.SchemaNames() {
+		if !slices.Contains(from.SchemaNames(), schema) {
+			m.createSchemas = append(m.createSchemas, Statement{Kind: StatementCreateSchema, SQL: createSchemaSQL(schema)})
+		}
+	}
+
+	if err := m.diffEnums(from, to); err != nil {
+		return nil, err
+	}
+
+	var created []*Table
+	for _, newTable := range to.Tables {
+		oldTable := from.Table(newTable.Name)
+		if oldTable == nil {
+			created = append(created, newTable)
+			continue
+		}
+		if err := m.alterTable(oldTable, newTable); err != nil {
+			return nil, err
+		}
+	}
+	if err := m.createNewTables(created); err != nil {
+		return nil, err
+	}
+
+	var dropped []*Table
+	for _, oldTable := range from.Tables {
+		if to.Table(oldTable.Name) == nil {
+			dropped = append(dropped, oldTable)
+		}
+	}
+	m.dropOldTables(dropped)
+
+	if err := m.diffViews(from, to); err != nil {
+		return nil, err
+	}
+
+	return m.statements(), nil
+}
+
+// diffViews drops the views that no longer exist, in reverse dependency
+// order, and creates the new ones in dependency order. Views that change are
+// dropped and created again, along with the views that read them, since
+// CREATE OR REPLACE VIEW cannot remove or retype columns.
+func (m *migration) diffViews(from, to *Schema) error {
+	oldViews, err := viewOrder(from.Views)
+	if err != nil {
+		return err
+	}
+	newViews, err := viewOrder(to.Views)
+	if err != nil {
+		return err
+	}
+
+	recreated := make(map[string]bool)
+	for _, view := range newViews {
+		old := from.View(view.Name)
+		if old == nil {
+			continue
+		}
+		changed := old.CreateSQL() != view.CreateSQL()
+		for _, name := range view.Reads {
+			changed = changed || recreated[name] || columnsChanged(from.Table(name), to.Table(name))
+		}
+		recreated[view.Name] = changed
+	}
+
+	for i := len(oldViews) - 1; i >= 0; i-- {
+		view := oldViews[i]
+		if to.View(view.Name) == nil || recreated[view.Name] {
+			m.dropViews = append(m.dropViews, Statement{Kind: StatementDropView, Table: view.Name, SQL: view.DropSQL()})
+		}
+	}
+
+	for _, view := range newViews {
+		old := from.View(view.Name)
+		if old == nil || recreated[view.Name] {
+			if err := m.createView(view); err != nil {
+				return err
+			}
+			continue
+		}
+		if old.Comment != view.Comment {
+			m.comments = append(m.comments, Statement{Kind: StatementComment, Table: view.Name, SQL: view.commentSQL(view.Comment)})
+		}
+		if err := m.diffIndexes(old.table(), view.table()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createView adds the statements creating a view with its comment and indexes.
+func (m *migration) createView(view *CompiledView) error {
+	m.createViews = append(m.createViews, Statement{Kind: StatementCreateView, Table: view.Name, SQL: view.CreateSQL()})
+	for _, sql := range view.CommentSQL() {
+		m.createViews = append(m.createViews, Statement{Kind: StatementComment, Table: view.Name, SQL: sql})
+	}
+	indexes, err := newIndexStatements(view.table())
+	if err != nil {
+		return err
+	}
+	m.createViews = append(m.createViews, indexes...)
+	return nil
+}
+
+// columnsChanged reports whether a column of the old version of a table was
+// dropped or changed its type, or whether the table was dropped. Either is
+// refused by PostgreSQL while a view reads the column. A name that is not a
+// table in the old schema, such as a view, has no columns that can change.
+func columnsChanged(oldTable, newTable *Table) bool {
+	if oldTable == nil {
+		return false
+	}
+	if newTable == nil {
+		return true
+	}
+	for _, field := range oldTable.Fields {
+		newField := newTable.Field(field.Name())
+		if newField == nil || ColumnOf(newField).Type != ColumnOf(field).Type {
+			return true
+		}
+	}
+	return false
+}
+
+// createNewTables creates the tables that do not exist in the old schema in
+// dependency order, adding the foreign keys that close a cycle afterwards.
+func (m *migration) createNewTables(tables []*Table) error {
+	ordered, deferred := createOrder(tables)
+	for _, t := range ordered {
+		if err := m.createTable(t, deferred[t.Name]); err != nil {
+			return err
+		}
+		for _, fk := range deferred[t.Name] {
+			m.addForeignKeys = append(m.addForeignKeys, Statement{Kind: StatementAlterTable, Table: t.Name, SQL: t.addForeignKeySQL(fk)})
+		}
+	}
+	return nil
+}
+
+// dropOldTables drops the tables that no longer exist in the new schema, each
+// before the tables it references. The foreign keys that close a cycle are
+// dropped first.
+func (m *migration) dropOldTables(tables []*Table) {
+	ordered, deferred := createOrder(tables)
+	for i := len(ordered) - 1; i >= 0; i-- {
+		t := ordered[i]
+		for _, fk := range deferred[t.Name] {
+			m.dropForeignKeys = append(m.dropForeignKeys, Statement{
+				Kind:  StatementAlterTable,
+				Table: t.Name,
+				SQL:   fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT IF EXISTS %s;", QuoteQualifiedName(t.Name), QuoteIdentifier(fk.ConstraintName(t.Name))),
+			})
+		}
+		m.dropTables = append(m.dropTables, Statement{
+			Kind:  StatementDropTable,
+			Table: t.Name,
+			SQL:   fmt.Sprintf("DROP TABLE IF EXISTS %s;", QuoteQualifiedName(t.Name)),
+		})
+	}
+}
+
+// diffEnums creates new enum types, adds appended values to existing ones and
+// drops the types no longer used.
+func (m *migration) diffEnums(from, to *Schema) error {
+	for _, newEnum := range to.Enums {
+		oldEnum, ok := from.Enum(newEnum.QualifiedName())
+		if !ok {
+			m.createTypes = append(m.createTypes, Statement{Kind: StatementCreateType, SQL: newEnum.CreateSQL()})
+			continue
+		}
+		stmts, err := oldEnum.AlterSQL(newEnum)
+		if err != nil {
+			return err
+		}
+		for _, sql := range stmts {
+			m.createTypes = append(m.createTypes, Statement{Kind: StatementAlterType, SQL: sql})
+		}
+	}
+
+	for _, oldEnum := range from.Enums {
+		if _, ok := to.Enum(oldEnum.QualifiedName()); !ok {
+			m.dropTypes = append(m.dropTypes, Statement{Kind: StatementDropType, SQL: oldEnum.DropSQL()})
+		}
+	}
+	return nil
+}
+
+// createTable adds the full DDL of a table that does not exist in the old schema.
+func (m *migration) createTable(t *Table, deferred []ForeignKey) error {
+	m.createTables = append(m.createTables, Statement{Kind: StatementCreateTable, Table: t.Name, SQL: t.createSQL(deferred)})
+	for _, sql := range t.PartitionSQL() {
+		m.createTables = append(m.createTables, Statement{Kind: StatementCreateTable, Table: t.Name, SQL: sql})
+	}
+	for _, sql := range t.CommentSQL() {
+		m.comments = append(m.comments, Statement{Kind: StatementComment, Table: t.Name, SQL: sql})
+	}
+	indexes, err := newIndexStatements(t)
+	if err != nil {
+		return err
+	}
+	m.createIndexes = append(m.createIndexes, indexes...)
+	return nil
+}
+
+// newIndexStatements returns the statements creating the indexes of a table
+// or materialized view created by the same migration. None is built
+// concurrently: the relation is new, so there are no writes to let through,
+// and the statements run in the transaction that creates it.
+func newIndexStatements(t *Table) ([]Statement, error) {
+	created := *t
+	created.Indexes = slices.Clone(t.Indexes)
+	for i := range created.Indexes {
+		created.Indexes[i].Concurrently = false
+	}
+	indexes, err := created.indexDefinitions()
+	if err != nil {
+		return nil, err
+	}
+	stmts := make([]Statement, len(indexes))
+	for i, idx := range indexes {
+		stmts[i] = idx.createStatement(t.Name)
+	}
+	return stmts, nil
+}
+
+// alterTable compares two versions of the same table.
+func (m *migration) alterTable(oldTable, newTable *Table) error {
+	if err := m.diffPartitions(oldTable, newTable); err != nil {
+		return err
+	}
+
+	for _, newField := range newTable.Fields {
+		newCol := ColumnOf(newField)
+		oldField := oldTable.Field(newCol.Name)
+		if oldField == nil {
+			m.alter(newTable.Name, fmt.Sprintf("ADD COLUMN %s", newField.Definition()))
+			if sql := newField.CommentSQL(newTable.Name); sql != "" {
+				m.comments = append(m.comments, Statement{Kind: StatementComment, Table: newTable.Name, SQL: sql})
+			}
+			continue
+		}
+		oldCol := ColumnOf(oldField)
+		if oldCol.Generated == "" && newCol.Generated != "" {
+			return fmt.Errorf("table %s: column %s cannot become a generated column; drop it and add it back in separate migrations", newTable.Name, newCol.Name)
+		}
+		if oldCol.Constraints != newCol.Constraints {
+			return fmt.Errorf("table %s: column %s: raw Constraints changed from %q to %q; write the migration by hand or declare them as table constraints",
+				newTable.Name, newCol.Name, oldCol.Constraints, newCol.Constraints)
+		}
+		m.alterColumn(newTable.Name, oldCol, newCol)
+	}
+	m.diffPrimaryKey(oldTable, newTable)
+
+	for _, oldField := range oldTable.Fields {
+		if newTable.Field(oldField.Name()) == nil {
+			m.alter(oldTable.Name, fmt.Sprintf("DROP COLUMN %s", QuoteIdentifier(oldField.Name())))
+		}
+	}
+
+	m.diffOptions(oldTable, newTable)
+	m.diffConstraints(oldTable, newTable)
+	m.diffForeignKeys(oldTable, newTable)
+	return m.diffIndexes(oldTable, newTable)
+}
+
+// diffPrimaryKey drops and adds the primary key declared by the columns of a
+// table when its columns change. PostgreSQL names that key <table>_pkey. A
+// key on an added column is declared by its ADD COLUMN.
+func (m *migration) diffPrimaryKey(oldTable, newTable *Table) {
+	oldKey, newKey := columnPrimaryKey(oldTable), columnPrimaryKey(newTable)
+	if slices.Equal(oldKey, newKey) {
+		return
+	}
+
+	if len(oldKey) > 0 {
+		m.dropConstraints = append(m.dropConstraints, Statement{
+			Kind:  StatementAlterTable,
+			Table: oldTable.Name,
+			SQL:   fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT IF EXISTS %s;", QuoteQualifiedName(oldTable.Name), QuoteIdentifier(unqualifiedName(oldTable.Name)+"_pkey")),
+		})
+	}
+	for _, col := range newKey {
+		if oldTable.Field(col) == nil {
+			return
+		}
+	}
+	if len(newKey) > 0 {
+		m.addConstraints = append(m.addConstraints, Statement{
+			Kind:  StatementAlterTable,
+			Table: newTable.Name,
+			SQL:   fmt.Sprintf("ALTER TABLE %s ADD PRIMARY KEY (%s);", QuoteQualifiedName(newTable.Name), joinColumns(newKey)),
+		})
+	}
+}
+
+// columnPrimaryKey returns the columns of a table declared as its primary key
+// by the fields, rather than by a PrimaryKey constraint.
+func columnPrimaryKey(t *Table) []string {
+	var columns []string
+	for _, field := range t.Fields {
+		if ColumnOf(field).PrimaryKey {
+			columns = append(columns, field.Name())
+		}
+	}
+	return columns
+}
+
+// diffPartitions creates the partitions added to a partitioned table and
+// detaches the removed ones, keeping their rows as standalone tables. Removed
+// partitions are detached before new ones are created, so a partition can be
+// replaced by one with an overlapping range.
+// Partitions are matched by name; the bounds of an existing partition are not
+// compared. A table cannot be partitioned or have its key changed in place.
+func (m *migration) diffPartitions(oldTable, newTable *Table) error {
+	oldPart, newPart := oldTable.Partitioning, newTable.Partitioning
+	if oldPart.Strategy != newPart.Strategy || oldPart.Column != newPart.Column {
+		return fmt.Errorf("table %s: partitioning cannot be changed in place; create a new table and move the rows", newTable.Name)
+	}
+
+	oldNames := make(map[string]bool, len(oldPart.Partitions))
+	for _, part := range oldPart.Partitions {
+		oldNames[part.Name] = true
+	}
+	newNames := make(map[string]bool, len(newPart.Partitions))
+	for _, part := range newPart.Partitions {
+		newNames[part.Name] = true
+		if !oldNames[part.Name] {
+			m.createPartitions = append(m.createPartitions, Statement{Kind: StatementCreateTable, Table: newTable.Name, SQL: part.SQL(newTable.Name)})
+		}
+	}
+	for _, part := range oldPart.Partitions {
+		if !newNames[part.Name] {
+			m.alter(newTable.Name, fmt.Sprintf("DETACH PARTITION %s", QuoteQualifiedName(QualifyName(schemaOf(newTable.Name), part.Name))))
+		}
+	}
+	return nil
+}
+
+// diffOptions emits the statements that change the comment, storage
+// parameters, tablespace and persistence of a table.
+func (m *migration) diffOptions(oldTable, newTable *Table) {
+	oldOpts, newOpts := oldTable.Options, newTable.Options
+	name := newTable.Name
+
+	if oldOpts.Unlogged != newOpts.Unlogged {
+		if newOpts.Unlogged {
+			m.alter(name, "SET UNLOGGED")
+		} else {
+			m.alter(name, "SET LOGGED")
+		}
+	}
+
+	changed := make(map[string]string)
+	for param, value := range newOpts.Storage {
+		if old, ok := oldOpts.Storage[param]; !ok || old != value {
+			changed[param] = value
+		}
+	}
+	if len(changed) > 0 {
+		m.alter(name, fmt.Sprintf("SET (%s)", storageList(changed)))
+	}
+	var reset []string
+	for _, param := range storageNames(oldOpts.Storage) {
+		if _, ok := newOpts.Storage[param]; !ok {
+			reset = append(reset, param)
+		}
+	}
+	if len(reset) > 0 {
+		m.alter(name, fmt.Sprintf("RESET (%s)", strings.Join(reset, ", ")))
+	}
+
+	if oldOpts.Tablespace != newOpts.Tablespace {
+		tablespace := "pg_default"
+		if newOpts.Tablespace != "" {
+			tablespace = QuoteIdentifier(newOpts.Tablespace)
+		}
+		// Moving a table rewrites it under an ACCESS EXCLUSIVE lock.
+		m.alter(name, fmt.Sprintf("SET TABLESPACE %s", tablespace))
+	}
+
+	if oldOpts.Comment != newOpts.Comment {
+		m.comments = append(m.comments, Statement{Kind: StatementComment, Table: name, SQL: tableCommentSQL(name, newOpts.Comment)})
+	}
+}
+
+// alterColumn emits the ALTER COLUMN statements needed to turn oldCol into newCol.
+// A column that stops being serial loses its default and sequence before any
+// identity is added; one that becomes serial gets its sequence after any
+// identity is dropped.
+func (m *migration) alterColumn(table string, oldCol, newCol Column) {
+	name := newCol.Name
+
+	if oldCol.Generated != newCol.Generated {
+		if newCol.Generated == "" {
+			m.alter(table, fmt.Sprintf("ALTER COLUMN %s DROP EXPRESSION", QuoteIdentifier(name)))
+		} else {
+			// SET EXPRESSION requires PostgreSQL 17.
+			m.alter(table, fmt.Sprintf("ALTER COLUMN %s SET EXPRESSION AS (%s)", QuoteIdentifier(name), newCol.Generated))
+		}
+	}
+
+	sequence := QuoteQualifiedName(serialSequence(table, name))
+	if oldCol.Serial && !newCol.Serial {
+		m.alter(table, fmt.Sprintf("ALTER COLUMN %s DROP DEFAULT", QuoteIdentifier(name)))
+		m.alterColumns = append(m.alterColumns, Statement{
+			Kind:  StatementDropSequence,
+			Table: table,
+			SQL:   fmt.Sprintf("DROP SEQUENCE IF EXISTS %s;", sequence),
+		})
+	}
+
+	if oldCol.Type != newCol.Type {
+		m.alter(table, fmt.Sprintf("ALTER COLUMN %s TYPE %s USING %s::%s", QuoteIdentifier(name), newCol.Type, QuoteIdentifier(name), newCol.Type))
+		if oldCol.Serial && newCol.Serial {
+			m.alterColumns = append(m.alterColumns, Statement{
+				Kind:  StatementAlterSequence,
+				Table: table,
+				SQL:   fmt.Sprintf("ALTER SEQUENCE %s AS %s;", sequence, newCol.Type),
+			})
+		}
+	}
+
+	if oldCol.Nullable != newCol.Nullable {
+		if newCol.Nullable {
+			m.alter(table, fmt.Sprintf("ALTER COLUMN %s DROP NOT NULL", QuoteIdentifier(name)))
+		} else {
+			m.alter(table, fmt.Sprintf("ALTER COLUMN %s SET NOT NULL", QuoteIdentifier(name)))
+		}
+	}
+
+	if oldCol.Default != newCol.Default {
+		if newCol.Default == "" {
+			m.alter(table, fmt.Sprintf("ALTER COLUMN %s DROP DEFAULT", QuoteIdentifier(name)))
+		} else {
+			m.alter(table, fmt.Sprintf("ALTER COLUMN %s SET DEFAULT %s", QuoteIdentifier(name), newCol.Default))
+		}
+	}
+
+	if oldCol.Identity != newCol.Identity {
+		m.alter(table, fmt.Sprintf("ALTER COLUMN %s %s", QuoteIdentifier(name), alterIdentity(oldCol.Identity, newCol.Identity)))
+	}
+
+	if newCol.Serial && !oldCol.Serial {
+		// The sequence starts after the values already in the column.
+		m.alterColumns = append(m.alterColumns,
+			Statement{
+				Kind:  StatementCreateSequence,
+				Table: table,
+				SQL: fmt.Sprintf("CREATE SEQUENCE IF NOT EXISTS %s AS %s OWNED BY %s.%s;",
+					sequence, newCol.Type, QuoteQualifiedName(table), QuoteIdentifier(name)),
+			},
+			Statement{
+				Kind:  StatementCreateSequence,
+				Table: table,
+				SQL: fmt.Sprintf("SELECT setval(%s, COALESCE(MAX(%s), 0) + 1, false) FROM %s;",
+					QuoteLiteral(sequence), QuoteIdentifier(name), QuoteQualifiedName(table)),
+			},
+		)
+		m.alter(table, fmt.Sprintf("ALTER COLUMN %s SET DEFAULT nextval(%s::regclass)", QuoteIdentifier(name), QuoteLiteral(sequence)))
+	}
+
+	if oldCol.Unique != newCol.Unique {
+		constraint := fmt.Sprintf("%s_%s_key", unqualifiedName(table), name)
+		if newCol.Unique {
+			m.alter(table, fmt.Sprintf("ADD CONSTRAINT %s UNIQUE (%s)", QuoteIdentifier(constraint), QuoteIdentifier(name)))
+		} else {
+			m.alter(table, fmt.Sprintf("DROP CONSTRAINT IF EXISTS %s", QuoteIdentifier(constraint)))
+		}
+	}
+
+	if oldCol.Comment != newCol.Comment {
+		comment := "NULL"
+		if newCol.Comment != "" {
+			comment = QuoteLiteral(newCol.Comment)
+		}
+		m.comments = append(m.comments, Statement{
+			Kind:  StatementComment,
+			Table: table,
+			SQL:   fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;", QuoteQualifiedName(table), QuoteIdentifier(name), comment),
+		})
+	}
+}
+
+// serialSequence returns the name PostgreSQL gives the sequence of a serial
+// column, in the schema of its table.
+func serialSequence(table, column string) string {
+	return QualifyName(schemaOf(table), fmt.Sprintf("%s_%s_seq", unqualifiedName(table), column))
+}
+
+// alterIdentity returns the ALTER COLUMN action that turns identity from into to.
+func alterIdentity(from, to Identity) string {
+	switch {
+	case to.IsZero():
+		return "DROP IDENTITY IF EXISTS"
+	case from.IsZero():
+		return "ADD " + to.SQL()
+	}
+
+	var actions []string
+	if from.Generation != to.Generation {
+		actions = append(actions, fmt.Sprintf("SET GENERATED %s", to.Generation))
+	}
+	if from.Start != to.Start {
+		actions = append(actions, fmt.Sprintf("SET START WITH %d", sequenceOption(to.Start)))
+	}
+	if from.Increment != to.Increment {
+		actions = append(actions, fmt.Sprintf("SET INCREMENT BY %d", sequenceOption(to.Increment)))
+	}
+	return strings.Join(actions, " ")
+}
+
+// sequenceOption returns an identity sequence option, where 0 stands for the default of 1.
+func sequenceOption(v int64) int64 {
+	if v == 0 {
+		return 1
+	}
+	return v
+}
+
+// alter records an ALTER TABLE statement with the given action.
+func (m *migration) alter(table, action string) {
+	m.alterColumns = append(m.alterColumns, Statement{
+		Kind:  StatementAlterTable,
+		Table: table,
+		SQL:   fmt.Sprintf("ALTER TABLE %s %s;", QuoteQualifiedName(table), action),
+	})
+}
+
+// diffForeignKeys drops removed or changed foreign keys and adds new or changed ones.
+func (m *migration) diffForeignKeys(oldTable, newTable *Table) {
+	oldKeys := make(map[string]string, len(oldTable.ForeignKeys))
+	for _, fk := range oldTable.ForeignKeys {
+		oldKeys[fk.ConstraintName(oldTable.Name)] = fk.Definition()
+	}
+	newKeys := make(map[string]string, len(newTable.ForeignKeys))
+	for _, fk := range newTable.ForeignKeys {
+		newKeys[fk.ConstraintName(newTable.Name)] = fk.Definition()
+	}
+
+	for _, fk := range oldTable.ForeignKeys {
+		name := fk.ConstraintName(oldTable.Name)
+		if def, ok := newKeys[name]; ok && def == fk.Definition() {
+			continue
+		}
+		m.dropForeignKeys = append(m.dropForeignKeys, Statement{
+			Kind:  StatementAlterTable,
+			Table: oldTable.Name,
+			SQL:   fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT IF EXISTS %s;", QuoteQualifiedName(oldTable.Name), QuoteIdentifier(name)),
+		})
+	}
+
+	for _, fk := range newTable.ForeignKeys {
+		name := fk.ConstraintName(newTable.Name)
+		if def, ok := oldKeys[name]; ok && def == fk.Definition() {
+			continue
+		}
+		m.addForeignKeys = append(m.addForeignKeys, Statement{
+			Kind:  StatementAlterTable,
+			Table: newTable.Name,
+			SQL:   fmt.Sprintf("ALTER TABLE %s ADD %s;", QuoteQualifiedName(newTable.Name), constraintClause(newTable.Name, fk)),
+		})
+	}
+}
+
+// diffConstraints drops removed or changed table constraints and adds new or changed ones.
+func (m *migration) diffConstraints(oldTable, newTable *Table) {
+	oldConstraints := make(map[string]string, len(oldTable.Constraints))
+	for _, c := range oldTable.Constraints {
+		oldConstraints[c.ConstraintName(oldTable.Name)] = c.Definition()
+	}
+	newConstraints := make(map[string]string, len(newTable.Constraints))
+	for _, c := range newTable.Constraints {
+		newConstraints[c.ConstraintName(newTable.Name)] = c.Definition()
+	}
+
+	for _, c := range oldTable.Constraints {
+		name := c.ConstraintName(oldTable.Name)
+		if def, ok := newConstraints[name]; ok && def == c.Definition() {
+			continue
+		}
+		m.dropConstraints = append(m.dropConstraints, Statement{
+			Kind:  StatementAlterTable,
+			Table: oldTable.Name,
+			SQL:   fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT IF EXISTS %s;", QuoteQualifiedName(oldTable.Name), QuoteIdentifier(name)),
+		})
+	}
+
+	for _, c := range newTable.Constraints {
+		name := c.ConstraintName(newTable.Name)
+		if def, ok := oldConstraints[name]; ok && def == c.Definition() {
+			continue
+		}
+		m.addConstraints = append(m.addConstraints, Statement{
+			Kind:  StatementAlterTable,
+			Table: newTable.Name,
+			SQL:   fmt.Sprintf("ALTER TABLE %s ADD %s;", QuoteQualifiedName(newTable.Name), constraintClause(newTable.Name, c)),
+		})
+	}
+}
+
+// sameIndex reports whether two CREATE INDEX statements build the same index.
+// Whether the index is built concurrently does not change the index itself.
+func sameIndex(a, b string) bool {
+	return strings.Replace(a, " INDEX CONCURRENTLY ", " INDEX ", 1) == strings.Replace(b, " INDEX CONCURRENTLY ", " INDEX ", 1)
+}
+
+// diffIndexes drops removed or changed indexes and creates new or changed ones.
+func (m *migration) diffIndexes(oldTable, newTable *Table) error {
+	oldIndexes, err := oldTable.indexDefinitions()
+	if err != nil {
+		return err
+	}
+	newIndexes, err := newTable.indexDefinitions()
+	if err != nil {
+		return err
+	}
+
+	oldByName := make(map[string]string, len(oldIndexes))
+	for _, idx := range oldIndexes {
+		oldByName[idx.Name] = idx.SQL
+	}
+	newByName := make(map[string]string, len(newIndexes))
+	for _, idx := range newIndexes {
+		newByName[idx.Name] = idx.SQL
+	}
+
+	for _, idx := range oldIndexes {
+		if sql, ok := newByName[idx.Name]; ok && sameIndex(sql, idx.SQL) {
+			continue
+		}
+		concurrently := ""
+		if idx.Concurrent {
+			concurrently = "CONCURRENTLY "
+		}
+		name := QualifyName(idx.Schema, idx.Name)
+		m.dropIndexes = append(m.dropIndexes, Statement{
+			Kind:       StatementDropIndex,
+			Table:      oldTable.Name,
+			SQL:        fmt.Sprintf("DROP INDEX %sIF EXISTS %s;", concurrently, QuoteQualifiedName(name)),
+			Index:      name,
+			Concurrent: idx.Concurrent,
+		})
+	}
+
+	for _, idx := range newIndexes {
+		if sql, ok := oldByName[idx.Name]; ok && sameIndex(sql, idx.SQL) {
+			continue
+		}
+		m.createIndexes = append(m.createIndexes, idx.createStatement(newTable.Name))
+	}
+
+	return nil
+}
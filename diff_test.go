@@ -0,0 +1,282 @@
+package trenovaorm
+
+import "testing"
+
+func mustCompile(t *testing.T, models ...Model) *Schema {
+	t.Helper()
+	schema, err := Compile(models...)
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	return schema
+}
+
+func statementSQL(stmts []Statement) []string {
+	sqls := make([]string, len(stmts))
+	for i, stmt := range stmts {
+		sqls[i] = stmt.SQL
+	}
+	return sqls
+}
+
+func assertStatements(t *testing.T, got []Statement, want []string) {
+	t.Helper()
+	sqls := statementSQL(got)
+	if len(sqls) != len(want) {
+		t.Fatalf("got %d statements, want %d:\n%v", len(sqls), len(want), sqls)
+	}
+	for i := range want {
+		if sqls[i] != want[i] {
+			t.Errorf("statement %d = %v, want %v", i, sqls[i], want[i])
+		}
+	}
+}
+
+// diffRoles is the table referenced by the foreign keys of the diff tests.
+var diffRoles = &testModel{name: "roles", fields: []Field{&IntegerField{ColumnName: "id", PrimaryKey: true}}}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		from []Model
+		to   []Model
+		want []string
+	}{
+		{
+			name: "No changes",
+			from: []Model{&testModel{name: "users", fields: []Field{&UUIDField{ColumnName: "id"}}}},
+			to:   []Model{&testModel{name: "users", fields: []Field{&UUIDField{ColumnName: "id"}}}},
+			want: nil,
+		},
+		{
+			name: "Add and drop table",
+			from: []Model{&testModel{name: "roles", fields: []Field{&UUIDField{ColumnName: "id"}}}},
+			to:   []Model{&testModel{name: "users", fields: []Field{&UUIDField{ColumnName: "id", Comment: "Identifier"}}}},
+			want: []string{
+				`CREATE TABLE IF NOT EXISTS "users" ("id" uuid NOT NULL);`,
+				`DROP TABLE IF EXISTS "roles";`,
+				`COMMENT ON COLUMN "users"."id" IS 'Identifier';`,
+			},
+		},
+		{
+			name: "Add and drop column",
+			from: []Model{&testModel{name: "users", fields: []Field{
+				&UUIDField{ColumnName: "id"},
+				&TextField{ColumnName: "bio", Nullable: true},
+			}}},
+			to: []Model{&testModel{name: "users", fields: []Field{
+				&UUIDField{ColumnName: "id"},
+				&CharField{ColumnName: "name", MaxLength: 100, Nullable: true},
+			}}},
+			want: []string{
+				`ALTER TABLE "users" ADD COLUMN "name" VARCHAR(100);`,
+				`ALTER TABLE "users" DROP COLUMN "bio";`,
+			},
+		},
+		{
+			name: "Alter column type, nullability, default and uniqueness",
+			from: []Model{&testModel{name: "users", fields: []Field{
+				&NumericField{ColumnName: "rating", Precision: 10, Scale: 2, Nullable: true},
+			}}},
+			to: []Model{&testModel{name: "users", fields: []Field{
+				&NumericField{ColumnName: "rating", Precision: 19, Scale: 4, Default: 1, Unique: true, Comment: "Rating"},
+			}}},
+			want: []string{
+				`ALTER TABLE "users" ALTER COLUMN "rating" TYPE NUMERIC(19, 4) USING "rating"::NUMERIC(19, 4);`,
+				`ALTER TABLE "users" ALTER COLUMN "rating" SET NOT NULL;`,
+				`ALTER TABLE "users" ALTER COLUMN "rating" SET DEFAULT 1.0000;`,
+				`ALTER TABLE "users" ADD CONSTRAINT "users_rating_key" UNIQUE ("rating");`,
+				`COMMENT ON COLUMN "users"."rating" IS 'Rating';`,
+			},
+		},
+		{
+			name: "Change foreign key and indexes",
+			from: []Model{&testModel{
+				name: "users",
+				fields: []Field{
+					&ForeignKeyField{ColumnName: "role_id", ReferenceTable: "roles", ReferenceField: "id"},
+					&CharField{ColumnName: "email", MaxLength: 255},
+				},
+				indexes: []Index{{Columns: []string{"email"}}},
+			}, diffRoles},
+			to: []Model{diffRoles, &testModel{
+				name: "users",
+				fields: []Field{
+					&ForeignKeyField{
+						ColumnName:     "role_id",
+						ReferenceTable: "roles",
+						ReferenceField: "id",
+						Annotations:    Annotation{OnDelete: OnDeleteCascade},
+					},
+					&CharField{ColumnName: "email", MaxLength: 255},
+				},
+				indexes: []Index{{Columns: []string{"email"}, Unique: true}},
+			}},
+			want: []string{
+				`ALTER TABLE "users" DROP CONSTRAINT IF EXISTS "users_role_id_fkey";`,
+				`DROP INDEX IF EXISTS "users_email_idx";`,
+				`ALTER TABLE "users" ADD CONSTRAINT "users_role_id_fkey" FOREIGN KEY ("role_id") REFERENCES "roles"("id") ON DELETE CASCADE;`,
+				`CREATE UNIQUE INDEX IF NOT EXISTS "users_email_idx" ON "users" ("email");`,
+			},
+		},
+		{
+			name: "Create enum type with its table",
+			from: nil,
+			to: []Model{&testModel{name: "shipments", fields: []Field{
+				&EnumField{ColumnName: "status", TypeName: "shipment_status", Values: []string{"new", "delivered"}, Default: "new"},
+			}}},
+			want: []string{
+				`CREATE TYPE "shipment_status" AS ENUM ('new', 'delivered');`,
+				`CREATE TABLE IF NOT EXISTS "shipments" ("status" "shipment_status" NOT NULL DEFAULT 'new');`,
+			},
+		},
+		{
+			name: "Add enum values",
+			from: []Model{&testModel{name: "shipments", fields: []Field{
+				&EnumField{ColumnName: "status", TypeName: "shipment_status", Values: []string{"new", "delivered"}},
+			}}},
+			to: []Model{&testModel{name: "shipments", fields: []Field{
+				&EnumField{ColumnName: "status", TypeName: "shipment_status", Values: []string{"new", "in_transit", "delivered", "cancelled"}},
+			}}},
+			want: []string{
+				`ALTER TYPE "shipment_status" ADD VALUE IF NOT EXISTS 'in_transit' AFTER 'new';`,
+				`ALTER TYPE "shipment_status" ADD VALUE IF NOT EXISTS 'cancelled' AFTER 'delivered';`,
+			},
+		},
+		{
+			name: "Add identity",
+			from: []Model{&testModel{name: "events", fields: []Field{&BigIntegerField{ColumnName: "id"}}}},
+			to: []Model{&testModel{name: "events", fields: []Field{
+				&BigIntegerField{ColumnName: "id", Identity: Identity{Generation: IdentityAlways, Start: 100}},
+			}}},
+			want: []string{`ALTER TABLE "events" ALTER COLUMN "id" ADD GENERATED ALWAYS AS IDENTITY (START WITH 100);`},
+		},
+		{
+			name: "Change identity",
+			from: []Model{&testModel{name: "events", fields: []Field{
+				&BigIntegerField{ColumnName: "id", Identity: Identity{Generation: IdentityAlways, Start: 100}},
+			}}},
+			to: []Model{&testModel{name: "events", fields: []Field{
+				&BigIntegerField{ColumnName: "id", Identity: Identity{Generation: IdentityByDefault, Increment: 5}},
+			}}},
+			want: []string{`ALTER TABLE "events" ALTER COLUMN "id" SET GENERATED BY DEFAULT SET START WITH 1 SET INCREMENT BY 5;`},
+		},
+		{
+			name: "Drop identity",
+			from: []Model{&testModel{name: "events", fields: []Field{
+				&BigIntegerField{ColumnName: "id", Identity: Identity{Generation: IdentityAlways}},
+			}}},
+			to:   []Model{&testModel{name: "events", fields: []Field{&BigIntegerField{ColumnName: "id"}}}},
+			want: []string{`ALTER TABLE "events" ALTER COLUMN "id" DROP IDENTITY IF EXISTS;`},
+		},
+		{
+			name: "Drop serial",
+			from: []Model{&testModel{name: "events", fields: []Field{&IntegerField{ColumnName: "id", Serial: true}}}},
+			to:   []Model{&testModel{name: "events", fields: []Field{&IntegerField{ColumnName: "id"}}}},
+			want: []string{
+				`ALTER TABLE "events" ALTER COLUMN "id" DROP DEFAULT;`,
+				`DROP SEQUENCE IF EXISTS "events_id_seq";`,
+			},
+		},
+		{
+			name: "Serial to identity",
+			from: []Model{&testModel{name: "events", fields: []Field{&BigIntegerField{ColumnName: "id", Serial: true}}}},
+			to: []Model{&testModel{name: "events", fields: []Field{
+				&BigIntegerField{ColumnName: "id", Identity: Identity{Generation: IdentityAlways}},
+			}}},
+			want: []string{
+				`ALTER TABLE "events" ALTER COLUMN "id" DROP DEFAULT;`,
+				`DROP SEQUENCE IF EXISTS "events_id_seq";`,
+				`ALTER TABLE "events" ALTER COLUMN "id" ADD GENERATED ALWAYS AS IDENTITY;`,
+			},
+		},
+		{
+			name: "Identity to serial",
+			from: []Model{&testModel{name: "events", fields: []Field{
+				&SmallIntegerField{ColumnName: "id", Identity: Identity{Generation: IdentityByDefault}},
+			}}},
+			to: []Model{&testModel{name: "events", fields: []Field{&SmallIntegerField{ColumnName: "id", Serial: true}}}},
+			want: []string{
+				`ALTER TABLE "events" ALTER COLUMN "id" DROP IDENTITY IF EXISTS;`,
+				`CREATE SEQUENCE IF NOT EXISTS "events_id_seq" AS SMALLINT OWNED BY "events"."id";`,
+				`SELECT setval('"events_id_seq"', COALESCE(MAX("id"), 0) + 1, false) FROM "events";`,
+				`ALTER TABLE "events" ALTER COLUMN "id" SET DEFAULT nextval('"events_id_seq"'::regclass);`,
+			},
+		},
+		{
+			name: "Widen serial",
+			from: []Model{&testModel{name: "events", fields: []Field{&IntegerField{ColumnName: "id", Serial: true}}}},
+			to:   []Model{&testModel{name: "events", fields: []Field{&BigIntegerField{ColumnName: "id", Serial: true}}}},
+			want: []string{
+				`ALTER TABLE "events" ALTER COLUMN "id" TYPE BIGINT USING "id"::BIGINT;`,
+				`ALTER SEQUENCE "events_id_seq" AS BIGINT;`,
+			},
+		},
+		{
+			name: "Add column primary key",
+			from: []Model{&testModel{name: "users", fields: []Field{&UUIDField{ColumnName: "id"}}}},
+			to:   []Model{&testModel{name: "users", fields: []Field{&UUIDField{ColumnName: "id", PrimaryKey: true}}}},
+			want: []string{`ALTER TABLE "users" ADD PRIMARY KEY ("id");`},
+		},
+		{
+			name: "Move column primary key",
+			from: []Model{&testModel{name: "users", fields: []Field{
+				&UUIDField{ColumnName: "id", PrimaryKey: true},
+				&CharField{ColumnName: "email", MaxLength: 255},
+			}}},
+			to: []Model{&testModel{name: "users", fields: []Field{
+				&UUIDField{ColumnName: "id"},
+				&CharField{ColumnName: "email", MaxLength: 255, Constraints: []string{"PRIMARY KEY"}},
+			}}},
+			want: []string{
+				`ALTER TABLE "users" DROP CONSTRAINT IF EXISTS "users_pkey";`,
+				`ALTER TABLE "users" ADD PRIMARY KEY ("email");`,
+			},
+		},
+		{
+			name: "Drop enum type after its table",
+			from: []Model{&testModel{name: "shipments", fields: []Field{
+				&EnumField{ColumnName: "status", TypeName: "shipment_status", Values: []string{"new"}},
+			}}},
+			to: nil,
+			want: []string{
+				`DROP TABLE IF EXISTS "shipments";`,
+				`DROP TYPE IF EXISTS "shipment_status";`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Diff(mustCompile(t, tt.from...), mustCompile(t, tt.to...))
+			if err != nil {
+				t.Fatalf("Diff() error = %v", err)
+			}
+			assertStatements(t, got, tt.want)
+		})
+	}
+}
+
+func TestDiff_RawConstraints(t *testing.T) {
+	from := mustCompile(t, &testModel{name: "countries", fields: []Field{
+		&CharField{ColumnName: "code", MaxLength: 3, Constraints: []string{"CHECK (char_length(code) = 3)"}},
+	}})
+	to := mustCompile(t, &testModel{name: "countries", fields: []Field{
+		&CharField{ColumnName: "code", MaxLength: 3, Constraints: []string{"CHECK (char_length(code) = 2)"}},
+	}})
+	if _, err := Diff(from, to); err == nil {
+		t.Error("Diff() error = nil, want an error for changed raw constraints")
+	}
+}
+
+func TestDiff_RemovedEnumValue(t *testing.T) {
+	from := mustCompile(t, &testModel{name: "shipments", fields: []Field{
+		&EnumField{ColumnName: "status", TypeName: "shipment_status", Values: []string{"new", "delivered"}},
+	}})
+	to := mustCompile(t, &testModel{name: "shipments", fields: []Field{
+		&EnumField{ColumnName: "status", TypeName: "shipment_status", Values: []string{"delivered"}},
+	}})
+	if _, err := Diff(from, to); err == nil {
+		t.Error("Diff() error = nil, want an error for a removed enum value")
+	}
+}
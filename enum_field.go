@@ -0,0 +1,194 @@
+package trenovaorm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// EnumField represents a column of a named PostgreSQL enum type.
+type EnumField struct {
+	ColumnName string
+	TypeName   string // Name of the PostgreSQL enum type
+	// TypeSchema is the PostgreSQL schema of the enum type. It defaults to
+	// the schema of the table when the table is compiled.
+	TypeSchema  string
+	Values      []string // Enum labels, in sort order
+	Nullable    bool
+	Unique      bool
+	Default     string
+	Index       bool
+	Comment     string
+	Constraints []string
+	StructTag   string
+}
+
+// Definition generates the SQL definition for the EnumField.
+func (f *EnumField) Definition() string {
+	def := fmt.Sprintf("%s %s", QuoteIdentifier(f.ColumnName), QuoteQualifiedName(f.Enum().QualifiedName()))
+
+	if !f.Nullable {
+		def += fmt.Sprintf(" %s", ConstraintNotNull.String())
+	}
+
+	if f.Unique {
+		def += fmt.Sprintf(" %s", ConstraintUnqiue.String())
+	}
+
+	if f.Default != "" {
+		def += " DEFAULT " + QuoteLiteral(f.Default)
+	}
+
+	if len(f.Constraints) > 0 {
+		def += " " + strings.Join(f.Constraints, " ")
+	}
+	return def
+}
+
+// Enum returns the enum type of the column.
+func (f *EnumField) Enum() EnumType {
+	return EnumType{Name: f.TypeName, Schema: f.TypeSchema, Values: f.Values}
+}
+
+// Name returns the column name for the EnumField.
+func (f *EnumField) Name() string {
+	return f.ColumnName
+}
+
+// CommentSQL generates the SQL statement for adding a comment to the EnumField.
+func (f *EnumField) CommentSQL(tableName string) string {
+	if f.Comment == "" {
+		return ""
+	}
+	return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;", QuoteQualifiedName(tableName), QuoteIdentifier(f.ColumnName), QuoteLiteral(f.Comment))
+}
+
+// Validate checks if the field's configuration is valid.
+func (f *EnumField) Validate() error {
+	if f.ColumnName == "" {
+		return fmt.Errorf("column name cannot be empty")
+	}
+	if err := f.Enum().Validate(); err != nil {
+		return err
+	}
+	if f.Default != "" && !f.Enum().Has(f.Default) {
+		return fmt.Errorf("EnumField %s default %q is not a value of %s", f.ColumnName, f.Default, f.TypeName)
+	}
+	return nil
+}
+
+// GoType returns the underlying Go type for the EnumField. Generators declare
+// a named string type for the enum instead.
+func (f *EnumField) GoType() string {
+	if f.Nullable {
+		return "*string"
+	}
+	return "string"
+}
+
+// IndexSQL generates the SQL statement for creating an index if Index is true.
+func (f *EnumField) IndexSQL(tableName string) string {
+	if !f.Index {
+		return ""
+	}
+	indexName := f.IndexName(tableName)
+	return fmt.Sprintf("CREATE INDEX %s ON %s (%s);", QuoteIdentifier(indexName), QuoteQualifiedName(tableName), QuoteIdentifier(f.ColumnName))
+}
+
+// IndexName returns the name of the index generated by IndexSQL.
+func (f *EnumField) IndexName(tableName string) string {
+	return fmt.Sprintf("%s_%s_idx", unqualifiedName(tableName), f.ColumnName)
+}
+
+// EnumType is a PostgreSQL enum type, shared by every EnumField with its name.
+type EnumType struct {
+	Name   string
+	Schema string // PostgreSQL schema of the type, empty for the search path
+	Values []string
+}
+
+// QualifiedName returns the name of the type, qualified with its schema if it
+// has one.
+func (e EnumType) QualifiedName() string {
+	return QualifyName(e.Schema, e.Name)
+}
+
+// Validate checks that the type is named and has distinct, non-empty values.
+func (e EnumType) Validate() error {
+	if e.Name == "" {
+		return errors.New("enum type name cannot be empty")
+	}
+	if len(e.Values) == 0 {
+		return fmt.Errorf("enum type %s has no values", e.Name)
+	}
+	seen := make(map[string]bool, len(e.Values))
+	for _, v := range e.Values {
+		if v == "" {
+			return fmt.Errorf("enum type %s has an empty value", e.Name)
+		}
+		if seen[v] {
+			return fmt.Errorf("enum type %s has duplicate value %q", e.Name, v)
+		}
+		seen[v] = true
+	}
+	return nil
+}
+
+// Has reports whether value is one of the values of the type.
+func (e EnumType) Has(value string) bool {
+	for _, v := range e.Values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateSQL generates the CREATE TYPE statement for the enum.
+func (e EnumType) CreateSQL() string {
+	values := make([]string, len(e.Values))
+	for i, v := range e.Values {
+		values[i] = QuoteLiteral(v)
+	}
+	return fmt.Sprintf("CREATE TYPE %s AS ENUM (%s);", QuoteQualifiedName(e.QualifiedName()), strings.Join(values, ", "))
+}
+
+// AlterSQL generates the ALTER TYPE ... ADD VALUE statements that turn the
+// values of e into those of to. PostgreSQL cannot remove or reorder enum
+// values, so every value of e must appear in to in the same order.
+func (e EnumType) AlterSQL(to EnumType) ([]string, error) {
+	pos := 0
+	for _, v := range e.Values {
+		for pos < len(to.Values) && to.Values[pos] != v {
+			pos++
+		}
+		if pos == len(to.Values) {
+			return nil, fmt.Errorf("enum type %s: value %q cannot be removed or reordered", e.QualifiedName(), v)
+		}
+		pos++
+	}
+
+	var stmts []string
+	for i, v := range to.Values {
+		if e.Has(v) {
+			continue
+		}
+		stmt := fmt.Sprintf("ALTER TYPE %s ADD VALUE IF NOT EXISTS %s", QuoteQualifiedName(e.QualifiedName()), QuoteLiteral(v))
+		switch {
+		case i > 0:
+			stmt += " AFTER " + QuoteLiteral(to.Values[i-1])
+		case len(e.Values) > 0:
+			// Without a position the value would be appended, so the first
+			// value goes before the first existing one. Values that follow
+			// it are then added after it.
+			stmt += " BEFORE " + QuoteLiteral(e.Values[0])
+		}
+		stmts = append(stmts, stmt+";")
+	}
+	return stmts, nil
+}
+
+// DropSQL generates the DROP TYPE statement for the enum.
+func (e EnumType) DropSQL() string {
+	return fmt.Sprintf("DROP TYPE IF EXISTS %s;", QuoteQualifiedName(e.QualifiedName()))
+}
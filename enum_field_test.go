@@ -0,0 +1,136 @@
+package trenovaorm
+
+import "testing"
+
+func TestEnumField_Definition(t *testing.T) {
+	tests := []struct {
+		name     string
+		field    EnumField
+		expected string
+	}{
+		{
+			name:     "Basic EnumField",
+			field:    EnumField{ColumnName: "status", TypeName: "shipment_status", Values: []string{"new", "delivered"}},
+			expected: `"status" "shipment_status" NOT NULL`,
+		},
+		{
+			name: "EnumField with Default",
+			field: EnumField{
+				ColumnName: "status",
+				TypeName:   "shipment_status",
+				Values:     []string{"new", "delivered"},
+				Nullable:   true,
+				Default:    "new",
+			},
+			expected: `"status" "shipment_status" DEFAULT 'new'`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.field.Definition(); got != tt.expected {
+				t.Errorf("EnumField.Definition() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEnumField_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   EnumField
+		wantErr bool
+	}{
+		{
+			name:    "Valid",
+			field:   EnumField{ColumnName: "status", TypeName: "status", Values: []string{"a", "b"}, Default: "a"},
+			wantErr: false,
+		},
+		{
+			name:    "Missing type name",
+			field:   EnumField{ColumnName: "status", Values: []string{"a"}},
+			wantErr: true,
+		},
+		{
+			name:    "No values",
+			field:   EnumField{ColumnName: "status", TypeName: "status"},
+			wantErr: true,
+		},
+		{
+			name:    "Duplicate values",
+			field:   EnumField{ColumnName: "status", TypeName: "status", Values: []string{"a", "b", "a"}},
+			wantErr: true,
+		},
+		{
+			name:    "Default not in values",
+			field:   EnumField{ColumnName: "status", TypeName: "status", Values: []string{"a", "b"}, Default: "c"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.field.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("EnumField.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEnumType_CreateSQL(t *testing.T) {
+	enum := EnumType{Name: "equipment_class", Values: []string{"reefer", "dry van", "o'flat"}}
+	expected := `CREATE TYPE "equipment_class" AS ENUM ('reefer', 'dry van', 'o''flat');`
+	if got := enum.CreateSQL(); got != expected {
+		t.Errorf("EnumType.CreateSQL() = %v, want %v", got, expected)
+	}
+}
+
+func TestEnumType_AlterSQL(t *testing.T) {
+	enum := EnumType{Name: "status", Values: []string{"a", "b"}}
+
+	tests := []struct {
+		name     string
+		to       []string
+		expected []string
+		wantErr  bool
+	}{
+		{name: "Unchanged", to: []string{"a", "b"}},
+		{
+			name:     "Prepended value",
+			to:       []string{"z", "a", "b"},
+			expected: []string{`ALTER TYPE "status" ADD VALUE IF NOT EXISTS 'z' BEFORE 'a';`},
+		},
+		{
+			name: "Prepended values",
+			to:   []string{"y", "z", "a", "b"},
+			expected: []string{
+				`ALTER TYPE "status" ADD VALUE IF NOT EXISTS 'y' BEFORE 'a';`,
+				`ALTER TYPE "status" ADD VALUE IF NOT EXISTS 'z' AFTER 'y';`,
+			},
+		},
+		{
+			name:     "Appended value",
+			to:       []string{"a", "b", "c"},
+			expected: []string{`ALTER TYPE "status" ADD VALUE IF NOT EXISTS 'c' AFTER 'b';`},
+		},
+		{name: "Removed value", to: []string{"a"}, wantErr: true},
+		{name: "Reordered values", to: []string{"b", "a"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := enum.AlterSQL(EnumType{Name: "status", Values: tt.to})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EnumType.AlterSQL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.expected) {
+				t.Fatalf("EnumType.AlterSQL() = %v, want %v", got, tt.expected)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Errorf("EnumType.AlterSQL()[%d] = %v, want %v", i, got[i], tt.expected[i])
+				}
+			}
+		})
+	}
+}
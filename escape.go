@@ -0,0 +1,38 @@
+package trenovaorm
+
+import (
+	"strconv"
+	"strings"
+)
+
+// QuoteIdentifier quotes a PostgreSQL identifier such as a table, column or
+// index name, doubling any embedded double quotes.
+func QuoteIdentifier(identifier string) string {
+	return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+}
+
+// QuoteQualifiedName quotes a name that may be qualified with a schema, such
+// as billing.invoices, quoting the schema and the name separately.
+func QuoteQualifiedName(name string) string {
+	schema, name := splitQualifiedName(name)
+	if schema == "" {
+		return QuoteIdentifier(name)
+	}
+	return QuoteIdentifier(schema) + "." + QuoteIdentifier(name)
+}
+
+// QuoteLiteral quotes a string as a PostgreSQL literal. Values are written as
+// standard-conforming strings with embedded single quotes doubled. Values with
+// backslashes, which older servers with standard_conforming_strings off would
+// treat as escapes, are dollar-quoted instead with a tag that does not occur
+// in the value.
+func QuoteLiteral(value string) string {
+	if !strings.Contains(value, `\`) {
+		return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	}
+	tag := "$$"
+	for i := 0; strings.Index(value+tag, tag) != len(value); i++ {
+		tag = "$q" + strconv.Itoa(i) + "$"
+	}
+	return tag + value + tag
+}
@@ -0,0 +1,258 @@
+package trenovaorm
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestQuoteIdentifier(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"users", `"users"`},
+		{"User Name", `"User Name"`},
+		{`weird"name`, `"weird""name"`},
+		{`";DROP TABLE users;--`, `""";DROP TABLE users;--"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := QuoteIdentifier(tt.in); got != tt.want {
+				t.Errorf("QuoteIdentifier() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuoteQualifiedName(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"users", `"users"`},
+		{"billing.invoices", `"billing"."invoices"`},
+		{`my"schema.my"table`, `"my""schema"."my""table"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := QuoteQualifiedName(tt.in); got != tt.want {
+				t.Errorf("QuoteQualifiedName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuoteLiteral(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", `''`},
+		{"plain", `'plain'`},
+		{"User's name", `'User''s name'`},
+		{"'); DROP TABLE users; --", `'''); DROP TABLE users; --'`},
+		{`C:\path`, `$$C:\path$$`},
+		{`a\$$b`, `$q0$a\$$b$q0$`},
+		{`ends with \$`, `$q0$ends with \$$q0$`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got := QuoteLiteral(tt.in)
+			if got != tt.want {
+				t.Errorf("QuoteLiteral() = %v, want %v", got, tt.want)
+			}
+			if value, err := unquoteLiteral(got); err != nil || value != tt.in {
+				t.Errorf("QuoteLiteral() = %v, which reads back as %q (%v)", got, value, err)
+			}
+		})
+	}
+}
+
+func TestField_Escaping(t *testing.T) {
+	field := &CharField{ColumnName: "name", MaxLength: 50, Default: "O'Brien", Comment: "User's name"}
+
+	if got, want := field.Definition(), `"name" VARCHAR(50) NOT NULL DEFAULT 'O''Brien'`; got != want {
+		t.Errorf("CharField.Definition() = %v, want %v", got, want)
+	}
+	if got, want := field.CommentSQL("users"), `COMMENT ON COLUMN "users"."name" IS 'User''s name';`; got != want {
+		t.Errorf("CharField.CommentSQL() = %v, want %v", got, want)
+	}
+}
+
+func FuzzFieldSQL(f *testing.F) {
+	f.Add("users", "name", "O'Brien", "User's name")
+	f.Add(`us"ers`, "na;me", `\'; DROP TABLE users; --`, "$$ $q0$ \\")
+	f.Add("t", "c", "/* open comment", "-- line comment")
+
+	f.Fuzz(func(t *testing.T, table, column, def, comment string) {
+		if strings.ContainsRune(table+column+def+comment, 0) {
+			t.Skip("PostgreSQL text cannot contain NUL bytes")
+		}
+		fields := []interface {
+			Field
+			IndexSQL(string) string
+		}{
+			&CharField{ColumnName: column, MaxLength: 10, Default: def, Comment: comment, Index: true},
+			&TextField{ColumnName: column, Default: def, Comment: comment, Index: true},
+			&JSONField{ColumnName: column, Default: def, Comment: comment, Index: true},
+			&ForeignKeyField{ColumnName: column, ReferenceTable: table, ReferenceField: column, Default: def, Comment: comment, Index: true},
+		}
+		for _, field := range fields {
+			create := "CREATE TABLE " + QuoteIdentifier(table) + " (" + field.Definition() + ");"
+			for _, sql := range []string{create, field.CommentSQL(table), field.IndexSQL(table)} {
+				if sql != "" {
+					assertOneStatement(t, sql)
+				}
+			}
+		}
+
+		if comment != "" {
+			sql := fields[0].CommentSQL(table)
+			literal := strings.TrimSuffix(sql[strings.LastIndex(sql, " IS ")+len(" IS "):], ";")
+			if got, err := unquoteLiteral(literal); err != nil || got != comment {
+				t.Errorf("CommentSQL() = %v, which reads back as %q (%v), want %q", sql, got, err, comment)
+			}
+		}
+
+		idx := Index{Name: column, Columns: []string{column}, Expressions: []Expression{Lower{Column: column}}}
+		sql, err := idx.SQL(table)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertOneStatement(t, sql)
+	})
+}
+
+// assertOneStatement fails unless sql is exactly one statement.
+func assertOneStatement(t *testing.T, sql string) {
+	t.Helper()
+	stmts, err := splitStatements(sql)
+	if err != nil {
+		t.Fatalf("%q: %v", sql, err)
+	}
+	if len(stmts) != 1 {
+		t.Fatalf("%q contains %d statements, want 1", sql, len(stmts))
+	}
+}
+
+// splitStatements splits SQL on top-level semicolons following the PostgreSQL
+// lexical rules for quoted identifiers, standard-conforming string literals,
+// dollar-quoted strings and comments.
+func splitStatements(sql string) ([]string, error) {
+	var stmts []string
+	start := 0
+	for i := 0; i < len(sql); i++ {
+		switch {
+		case sql[i] == '\'' || sql[i] == '"':
+			end, err := quotedEnd(sql, i)
+			if err != nil {
+				return nil, err
+			}
+			i = end
+		case sql[i] == '$':
+			tag, ok := dollarTag(sql[i:])
+			if !ok {
+				continue
+			}
+			end := strings.Index(sql[i+len(tag):], tag)
+			if end < 0 {
+				return nil, errors.New("unterminated dollar-quoted string")
+			}
+			i += len(tag) + end + len(tag) - 1
+		case strings.HasPrefix(sql[i:], "--"):
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				i = len(sql)
+				continue
+			}
+			i += end
+		case strings.HasPrefix(sql[i:], "/*"):
+			end, err := commentEnd(sql, i)
+			if err != nil {
+				return nil, err
+			}
+			i = end
+		case sql[i] == ';':
+			if stmt := strings.TrimSpace(sql[start:i]); stmt != "" {
+				stmts = append(stmts, stmt)
+			}
+			start = i + 1
+		}
+	}
+	if stmt := strings.TrimSpace(sql[start:]); stmt != "" {
+		stmts = append(stmts, stmt)
+	}
+	return stmts, nil
+}
+
+// quotedEnd returns the index of the quote closing the string or identifier at i.
+func quotedEnd(sql string, i int) (int, error) {
+	quote := sql[i]
+	for j := i + 1; j < len(sql); j++ {
+		if sql[j] != quote {
+			continue
+		}
+		if j+1 < len(sql) && sql[j+1] == quote {
+			j++
+			continue
+		}
+		return j, nil
+	}
+	return 0, errors.New("unterminated quoted string")
+}
+
+// commentEnd returns the index of the last character of the nested block comment at i.
+func commentEnd(sql string, i int) (int, error) {
+	depth := 0
+	for j := i; j+1 < len(sql); j++ {
+		switch sql[j : j+2] {
+		case "/*":
+			depth++
+			j++
+		case "*/":
+			depth--
+			j++
+			if depth == 0 {
+				return j, nil
+			}
+		}
+	}
+	return 0, errors.New("unterminated block comment")
+}
+
+// dollarTag returns the $tag$ opening a dollar-quoted string at the start of s.
+func dollarTag(s string) (string, bool) {
+	for j := 1; j < len(s); j++ {
+		c := s[j]
+		if c == '$' {
+			return s[:j+1], true
+		}
+		isLetter := c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+		if !isLetter && (j == 1 || c < '0' || c > '9') {
+			return "", false
+		}
+	}
+	return "", false
+}
+
+// unquoteLiteral returns the value of a literal produced by QuoteLiteral.
+func unquoteLiteral(literal string) (string, error) {
+	if tag, ok := dollarTag(literal); ok && strings.HasSuffix(literal, tag) && len(literal) >= 2*len(tag) {
+		value := literal[len(tag) : len(literal)-len(tag)]
+		if strings.Contains(value+tag[:len(tag)-1], tag) {
+			return "", errors.New("dollar-quoted value contains its tag")
+		}
+		return value, nil
+	}
+	if len(literal) < 2 || literal[0] != '\'' || literal[len(literal)-1] != '\'' {
+		return "", errors.New("not a quoted literal")
+	}
+	if end, err := quotedEnd(literal, 0); err != nil || end != len(literal)-1 {
+		return "", errors.New("literal ends early")
+	}
+	return strings.ReplaceAll(literal[1:len(literal)-1], "''", "'"), nil
+}
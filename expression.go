@@ -1,14 +1,24 @@
 package trenovaorm
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 )
 
-// Expression defines a PostgreSQL expression.
+// Expression defines a PostgreSQL expression. Expressions compose into trees,
+// e.g. Binary{Left: Ref{Column: "price"}, Operator: "*", Right: Literal{Value: 2}},
+// and are used in indexes, check constraints and generated columns.
 type Expression interface {
 	Expression() string
-	ColumnName() string // To be used for naming the index
+	// ColumnNames returns the columns the expression references, used to name
+	// indexes and to check that the columns exist.
+	ColumnNames() []string
 }
 
 // Lower defines the LOWER expression in PostgreSQL.
@@ -17,11 +27,11 @@ type Lower struct {
 }
 
 func (l Lower) Expression() string {
-	return fmt.Sprintf("LOWER(%s)", quoteIdentifier(l.Column))
+	return fmt.Sprintf("LOWER(%s)", QuoteIdentifier(l.Column))
 }
 
-func (l Lower) ColumnName() string {
-	return l.Column
+func (l Lower) ColumnNames() []string {
+	return []string{l.Column}
 }
 
 // Upper defines the UPPER expression in PostgreSQL.
@@ -30,11 +40,11 @@ type Upper struct {
 }
 
 func (u Upper) Expression() string {
-	return fmt.Sprintf("UPPER(%s)", quoteIdentifier(u.Column))
+	return fmt.Sprintf("UPPER(%s)", QuoteIdentifier(u.Column))
 }
 
-func (u Upper) ColumnName() string {
-	return u.Column
+func (u Upper) ColumnNames() []string {
+	return []string{u.Column}
 }
 
 // Concat defines a CONCAT expression in PostgreSQL for string concatenation.
@@ -46,72 +56,476 @@ func (c Concat) Expression() string {
 	return fmt.Sprintf("CONCAT(%s)", joinColumns(c.Columns))
 }
 
-func (c Concat) ColumnName() string {
-	return strings.Join(c.Columns, "_")
+func (c Concat) ColumnNames() []string {
+	return c.Columns
 }
 
-// Gist defines a GIST index in PostgreSQL.
+// Gist indexes Column with the gist access method.
+//
+// Deprecated: set Index.Method to IndexGist and list the column in Columns.
 type Gist struct {
 	Column string
 }
 
 func (g Gist) Expression() string {
-	return fmt.Sprintf("USING GIST (%s)", quoteIdentifier(g.Column))
+	return QuoteIdentifier(g.Column)
 }
 
-func (g Gist) ColumnName() string {
-	return g.Column
+func (g Gist) ColumnNames() []string {
+	return []string{g.Column}
 }
 
-// Gin defines a GIN index in PostgreSQL.
+// Gin indexes Column with the gin access method.
+//
+// Deprecated: set Index.Method to IndexGin and list the column in Columns.
 type Gin struct {
 	Column string
 }
 
 func (g Gin) Expression() string {
-	return fmt.Sprintf("USING GIN (%s)", quoteIdentifier(g.Column))
+	return QuoteIdentifier(g.Column)
 }
 
-func (g Gin) ColumnName() string {
-	return g.Column
+func (g Gin) ColumnNames() []string {
+	return []string{g.Column}
 }
 
-// Btree defines a BTREE index in PostgreSQL.
+// Btree indexes Column with the btree access method.
+//
+// Deprecated: set Index.Method to IndexBtree and list the column in Columns.
 type Btree struct {
 	Column string
 }
 
 func (b Btree) Expression() string {
-	return fmt.Sprintf("USING BTREE (%s)", quoteIdentifier(b.Column))
+	return QuoteIdentifier(b.Column)
 }
 
-func (b Btree) ColumnName() string {
-	return b.Column
+func (b Btree) ColumnNames() []string {
+	return []string{b.Column}
 }
 
-// Hash defines a HASH index in PostgreSQL.
+// Hash indexes Column with the hash access method.
+//
+// Deprecated: set Index.Method to IndexHash and list the column in Columns.
 type Hash struct {
 	Column string
 }
 
 func (h Hash) Expression() string {
-	return fmt.Sprintf("USING HASH (%s)", quoteIdentifier(h.Column))
+	return QuoteIdentifier(h.Column)
 }
 
-func (h Hash) ColumnName() string {
-	return h.Column
+func (h Hash) ColumnNames() []string {
+	return []string{h.Column}
 }
 
 // ToTsVector defines a Tsvector expression for full-text search in PostgreSQL.
 type ToTSVector struct {
-	Config string
+	Config string // Text search configuration, e.g. english
 	Column string
 }
 
 func (t ToTSVector) Expression() string {
-	return fmt.Sprintf("to_tsvector(%s, %s)", quoteIdentifier(t.Config), quoteIdentifier(t.Column))
+	return fmt.Sprintf("to_tsvector(%s, %s)", QuoteLiteral(t.Config), QuoteIdentifier(t.Column))
 }
 
-func (t ToTSVector) ColumnName() string {
-	return t.Column
+func (t ToTSVector) ColumnNames() []string {
+	return []string{t.Column}
+}
+
+// Raw is an SQL expression used verbatim, for expressions the other types
+// cannot build. Columns lists the columns it references.
+type Raw struct {
+	SQL     string
+	Columns []string
+}
+
+func (r Raw) Expression() string {
+	return r.SQL
+}
+
+func (r Raw) ColumnNames() []string {
+	return r.Columns
+}
+
+var (
+	qualifiedName = regexp.MustCompile(`^[a-z_][a-z0-9_]*(\.[a-z_][a-z0-9_]*)?$`)
+	typeName      = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*( [A-Za-z_][A-Za-z0-9_]*)*(\([0-9]+(, ?[0-9]+)?\))?(\[\])?$`)
+	dateFields    = []string{"microseconds", "milliseconds", "second", "minute", "hour", "day", "week", "month", "quarter", "year", "decade", "century", "millennium"}
+	wordOperators = []string{"AND", "OR", "LIKE", "ILIKE", "IS DISTINCT FROM", "IS NOT DISTINCT FROM"}
+)
+
+// validateExpression checks an expression and, for the types that compose
+// other expressions, everything below it.
+func validateExpression(expr Expression) error {
+	if expr == nil {
+		return errors.New("expression cannot be nil")
+	}
+	if v, ok := expr.(interface{ Validate() error }); ok {
+		return v.Validate()
+	}
+	return nil
+}
+
+// validateExpressions checks each of exprs.
+func validateExpressions(exprs []Expression) error {
+	for _, expr := range exprs {
+		if err := validateExpression(expr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// columnsOf returns the distinct columns referenced by exprs, in order.
+func columnsOf(exprs ...Expression) []string {
+	var columns []string
+	for _, expr := range exprs {
+		if expr == nil {
+			continue
+		}
+		for _, col := range expr.ColumnNames() {
+			if !slices.Contains(columns, col) {
+				columns = append(columns, col)
+			}
+		}
+	}
+	return columns
+}
+
+// joinExpressions renders exprs as a comma-separated list.
+func joinExpressions(exprs []Expression) string {
+	parts := make([]string, len(exprs))
+	for i, expr := range exprs {
+		parts[i] = expr.Expression()
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Ref references a column of the table.
+type Ref struct {
+	Column string
+}
+
+func (r Ref) Expression() string {
+	return QuoteIdentifier(r.Column)
+}
+
+func (r Ref) ColumnNames() []string {
+	return []string{r.Column}
+}
+
+// Validate checks that the column is named.
+func (r Ref) Validate() error {
+	if r.Column == "" {
+		return errors.New("column reference cannot be empty")
+	}
+	return nil
+}
+
+// Literal is a constant value: a string, bool, integer, float, time.Time or
+// nil for NULL. Strings and times are quoted as SQL literals. Type, when set,
+// casts the value, e.g. {Value: "1 day", Type: "interval"}.
+type Literal struct {
+	Value any
+	Type  string
+}
+
+// literalSQL renders a literal value, or returns false if its type is not supported.
+func literalSQL(value any) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return "NULL", true
+	case string:
+		return QuoteLiteral(v), true
+	case bool:
+		if v {
+			return "TRUE", true
+		}
+		return "FALSE", true
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v), true
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32), !math.IsInf(float64(v), 0) && !math.IsNaN(float64(v))
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), !math.IsInf(v, 0) && !math.IsNaN(v)
+	case time.Time:
+		return QuoteLiteral(v.Format(time.RFC3339Nano)), true
+	}
+	return "", false
+}
+
+func (l Literal) Expression() string {
+	sql, _ := literalSQL(l.Value)
+	if l.Type != "" {
+		sql += "::" + l.Type
+	}
+	return sql
+}
+
+func (l Literal) ColumnNames() []string {
+	return nil
+}
+
+// Validate checks the value and type.
+func (l Literal) Validate() error {
+	if _, ok := literalSQL(l.Value); !ok {
+		return fmt.Errorf("unsupported literal %v of type %T", l.Value, l.Value)
+	}
+	if l.Type != "" && !typeName.MatchString(l.Type) {
+		return fmt.Errorf("invalid type name %q", l.Type)
+	}
+	return nil
+}
+
+// Func calls a function, e.g. {Name: "abs", Args: []Expression{Ref{Column: "delta"}}}.
+type Func struct {
+	Name string
+	Args []Expression
+}
+
+func (f Func) Expression() string {
+	return fmt.Sprintf("%s(%s)", f.Name, joinExpressions(f.Args))
+}
+
+func (f Func) ColumnNames() []string {
+	return columnsOf(f.Args...)
+}
+
+// Validate checks the function name and arguments.
+func (f Func) Validate() error {
+	if !qualifiedName.MatchString(f.Name) {
+		return fmt.Errorf("invalid function name %q", f.Name)
+	}
+	return validateExpressions(f.Args)
+}
+
+// Binary applies an infix operator, such as an arithmetic, comparison or
+// string operator, or AND and OR. It is parenthesized, so that nested
+// operators keep the order of the tree.
+type Binary struct {
+	Left     Expression
+	Operator string
+	Right    Expression
+}
+
+func (b Binary) Expression() string {
+	return fmt.Sprintf("(%s %s %s)", b.Left.Expression(), b.Operator, b.Right.Expression())
+}
+
+func (b Binary) ColumnNames() []string {
+	return columnsOf(b.Left, b.Right)
+}
+
+// Validate checks the operator and operands.
+func (b Binary) Validate() error {
+	if !exclusionOperator.MatchString(b.Operator) && !slices.Contains(wordOperators, b.Operator) {
+		return fmt.Errorf("invalid operator %q", b.Operator)
+	}
+	return validateExpressions([]Expression{b.Left, b.Right})
+}
+
+// AllOf is the conjunction of its operands.
+type AllOf struct {
+	Operands []Expression
+}
+
+func (a AllOf) Expression() string {
+	return logicalExpression("AND", a.Operands)
+}
+
+func (a AllOf) ColumnNames() []string {
+	return columnsOf(a.Operands...)
+}
+
+// Validate checks the operands.
+func (a AllOf) Validate() error {
+	return validateOperands(a.Operands)
+}
+
+// AnyOf is the disjunction of its operands.
+type AnyOf struct {
+	Operands []Expression
+}
+
+func (a AnyOf) Expression() string {
+	return logicalExpression("OR", a.Operands)
+}
+
+func (a AnyOf) ColumnNames() []string {
+	return columnsOf(a.Operands...)
+}
+
+// Validate checks the operands.
+func (a AnyOf) Validate() error {
+	return validateOperands(a.Operands)
+}
+
+// logicalExpression joins operands with AND or OR.
+func logicalExpression(op string, operands []Expression) string {
+	parts := make([]string, len(operands))
+	for i, operand := range operands {
+		parts[i] = operand.Expression()
+	}
+	return "(" + strings.Join(parts, " "+op+" ") + ")"
+}
+
+// validateOperands checks that there is at least one operand and each is valid.
+func validateOperands(operands []Expression) error {
+	if len(operands) == 0 {
+		return errors.New("at least one operand must be specified")
+	}
+	return validateExpressions(operands)
+}
+
+// Negate is the logical negation of its operand.
+type Negate struct {
+	Operand Expression
+}
+
+func (n Negate) Expression() string {
+	return fmt.Sprintf("(NOT %s)", n.Operand.Expression())
+}
+
+func (n Negate) ColumnNames() []string {
+	return columnsOf(n.Operand)
+}
+
+// Validate checks the operand.
+func (n Negate) Validate() error {
+	return validateExpression(n.Operand)
+}
+
+// Cast converts its operand to Type, e.g. "date" or "numeric(10, 2)".
+type Cast struct {
+	Operand Expression
+	Type    string
+}
+
+func (c Cast) Expression() string {
+	return fmt.Sprintf("CAST(%s AS %s)", c.Operand.Expression(), c.Type)
+}
+
+func (c Cast) ColumnNames() []string {
+	return columnsOf(c.Operand)
+}
+
+// Validate checks the operand and type.
+func (c Cast) Validate() error {
+	if !typeName.MatchString(c.Type) {
+		return fmt.Errorf("invalid type name %q", c.Type)
+	}
+	return validateExpression(c.Operand)
+}
+
+// Coalesce returns the first of its arguments that is not NULL.
+type Coalesce struct {
+	Args []Expression
+}
+
+func (c Coalesce) Expression() string {
+	return fmt.Sprintf("COALESCE(%s)", joinExpressions(c.Args))
+}
+
+func (c Coalesce) ColumnNames() []string {
+	return columnsOf(c.Args...)
+}
+
+// Validate checks the arguments.
+func (c Coalesce) Validate() error {
+	return validateOperands(c.Args)
+}
+
+// JSONGet returns the field or array element Key of a jsonb operand as jsonb,
+// using ->. Key is a string for objects and an integer for arrays.
+type JSONGet struct {
+	Operand Expression
+	Key     any
+}
+
+func (j JSONGet) Expression() string {
+	return Binary{Left: j.Operand, Operator: "->", Right: Literal{Value: j.Key}}.Expression()
+}
+
+func (j JSONGet) ColumnNames() []string {
+	return columnsOf(j.Operand)
+}
+
+// Validate checks the operand and key.
+func (j JSONGet) Validate() error {
+	return validateJSONKey(j.Operand, j.Key)
+}
+
+// JSONGetText returns the field or array element Key of a jsonb operand as
+// text, using ->>.
+type JSONGetText struct {
+	Operand Expression
+	Key     any
+}
+
+func (j JSONGetText) Expression() string {
+	return Binary{Left: j.Operand, Operator: "->>", Right: Literal{Value: j.Key}}.Expression()
+}
+
+func (j JSONGetText) ColumnNames() []string {
+	return columnsOf(j.Operand)
+}
+
+// Validate checks the operand and key.
+func (j JSONGetText) Validate() error {
+	return validateJSONKey(j.Operand, j.Key)
+}
+
+// validateJSONKey checks the operand of a JSON accessor and that the key is a
+// string or an integer.
+func validateJSONKey(operand Expression, key any) error {
+	switch key.(type) {
+	case string, int, int32, int64:
+	default:
+		return fmt.Errorf("JSON key must be a string or an integer, got %T", key)
+	}
+	return validateExpression(operand)
+}
+
+// JSONContains reports whether the jsonb operand contains Value, using @>.
+type JSONContains struct {
+	Operand Expression
+	Value   Expression // e.g. Literal{Value: `{"active": true}`, Type: "jsonb"}
+}
+
+func (j JSONContains) Expression() string {
+	return Binary{Left: j.Operand, Operator: "@>", Right: j.Value}.Expression()
+}
+
+func (j JSONContains) ColumnNames() []string {
+	return columnsOf(j.Operand, j.Value)
+}
+
+// Validate checks the operand and value.
+func (j JSONContains) Validate() error {
+	return validateExpressions([]Expression{j.Operand, j.Value})
+}
+
+// DateTrunc truncates a timestamp or interval to the precision of Field, such
+// as day or month.
+type DateTrunc struct {
+	Field   string
+	Operand Expression
+}
+
+func (d DateTrunc) Expression() string {
+	return fmt.Sprintf("date_trunc(%s, %s)", QuoteLiteral(d.Field), d.Operand.Expression())
+}
+
+func (d DateTrunc) ColumnNames() []string {
+	return columnsOf(d.Operand)
+}
+
+// Validate checks the field and operand.
+func (d DateTrunc) Validate() error {
+	if !slices.Contains(dateFields, d.Field) {
+		return fmt.Errorf("invalid date_trunc field %q", d.Field)
+	}
+	return validateExpression(d.Operand)
 }
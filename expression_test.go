@@ -1,6 +1,10 @@
 package trenovaorm
 
-import "testing"
+import (
+	"math"
+	"slices"
+	"testing"
+)
 
 func TestLower_Expression(t *testing.T) {
 	expr := Lower{Column: "col1"}
@@ -10,11 +14,11 @@ func TestLower_Expression(t *testing.T) {
 	}
 }
 
-func TestLower_ColumnName(t *testing.T) {
+func TestLower_ColumnNames(t *testing.T) {
 	expr := Lower{Column: "col1"}
-	expected := "col1"
-	if expr.ColumnName() != expected {
-		t.Errorf("Lower.ColumnName() = %v, want %v", expr.ColumnName(), expected)
+	expected := []string{"col1"}
+	if !slices.Equal(expr.ColumnNames(), expected) {
+		t.Errorf("Lower.ColumnNames() = %v, want %v", expr.ColumnNames(), expected)
 	}
 }
 
@@ -26,11 +30,11 @@ func TestUpper_Expression(t *testing.T) {
 	}
 }
 
-func TestUpper_ColumnName(t *testing.T) {
+func TestUpper_ColumnNames(t *testing.T) {
 	expr := Upper{Column: "col1"}
-	expected := "col1"
-	if expr.ColumnName() != expected {
-		t.Errorf("Upper.ColumnName() = %v, want %v", expr.ColumnName(), expected)
+	expected := []string{"col1"}
+	if !slices.Equal(expr.ColumnNames(), expected) {
+		t.Errorf("Upper.ColumnNames() = %v, want %v", expr.ColumnNames(), expected)
 	}
 }
 
@@ -42,90 +46,224 @@ func TestConcat_Expression(t *testing.T) {
 	}
 }
 
-func TestConcat_ColumnName(t *testing.T) {
+func TestConcat_ColumnNames(t *testing.T) {
 	expr := Concat{Columns: []string{"col1", "col2"}}
-	expected := "col1_col2"
-	if expr.ColumnName() != expected {
-		t.Errorf("Concat.ColumnName() = %v, want %v", expr.ColumnName(), expected)
+	expected := []string{"col1", "col2"}
+	if !slices.Equal(expr.ColumnNames(), expected) {
+		t.Errorf("Concat.ColumnNames() = %v, want %v", expr.ColumnNames(), expected)
 	}
 }
 
 func TestGist_Expression(t *testing.T) {
 	expr := Gist{Column: "col1"}
-	expected := `USING GIST ("col1")`
+	expected := `"col1"`
 	if expr.Expression() != expected {
 		t.Errorf("Gist.Expression() = %v, want %v", expr.Expression(), expected)
 	}
 }
 
-func TestGist_ColumnName(t *testing.T) {
+func TestGist_ColumnNames(t *testing.T) {
 	expr := Gist{Column: "col1"}
-	expected := "col1"
-	if expr.ColumnName() != expected {
-		t.Errorf("Gist.ColumnName() = %v, want %v", expr.ColumnName(), expected)
+	expected := []string{"col1"}
+	if !slices.Equal(expr.ColumnNames(), expected) {
+		t.Errorf("Gist.ColumnNames() = %v, want %v", expr.ColumnNames(), expected)
 	}
 }
 
 func TestGin_Expression(t *testing.T) {
 	expr := Gin{Column: "col1"}
-	expected := `USING GIN ("col1")`
+	expected := `"col1"`
 	if expr.Expression() != expected {
 		t.Errorf("Gin.Expression() = %v, want %v", expr.Expression(), expected)
 	}
 }
 
-func TestGin_ColumnName(t *testing.T) {
+func TestGin_ColumnNames(t *testing.T) {
 	expr := Gin{Column: "col1"}
-	expected := "col1"
-	if expr.ColumnName() != expected {
-		t.Errorf("Gin.ColumnName() = %v, want %v", expr.ColumnName(), expected)
+	expected := []string{"col1"}
+	if !slices.Equal(expr.ColumnNames(), expected) {
+		t.Errorf("Gin.ColumnNames() = %v, want %v", expr.ColumnNames(), expected)
 	}
 }
 
 func TestBtree_Expression(t *testing.T) {
 	expr := Btree{Column: "col1"}
-	expected := `USING BTREE ("col1")`
+	expected := `"col1"`
 	if expr.Expression() != expected {
 		t.Errorf("Btree.Expression() = %v, want %v", expr.Expression(), expected)
 	}
 }
 
-func TestBtree_ColumnName(t *testing.T) {
+func TestBtree_ColumnNames(t *testing.T) {
 	expr := Btree{Column: "col1"}
-	expected := "col1"
-	if expr.ColumnName() != expected {
-		t.Errorf("Btree.ColumnName() = %v, want %v", expr.ColumnName(), expected)
+	expected := []string{"col1"}
+	if !slices.Equal(expr.ColumnNames(), expected) {
+		t.Errorf("Btree.ColumnNames() = %v, want %v", expr.ColumnNames(), expected)
 	}
 }
 
 func TestHash_Expression(t *testing.T) {
 	expr := Hash{Column: "col1"}
-	expected := `USING HASH ("col1")`
+	expected := `"col1"`
 	if expr.Expression() != expected {
 		t.Errorf("Hash.Expression() = %v, want %v", expr.Expression(), expected)
 	}
 }
 
-func TestHash_ColumnName(t *testing.T) {
+func TestHash_ColumnNames(t *testing.T) {
 	expr := Hash{Column: "col1"}
-	expected := "col1"
-	if expr.ColumnName() != expected {
-		t.Errorf("Hash.ColumnName() = %v, want %v", expr.ColumnName(), expected)
+	expected := []string{"col1"}
+	if !slices.Equal(expr.ColumnNames(), expected) {
+		t.Errorf("Hash.ColumnNames() = %v, want %v", expr.ColumnNames(), expected)
 	}
 }
 
 func TestToTsVector_Expression(t *testing.T) {
 	expr := ToTSVector{Config: "english", Column: "col1"}
-	expected := `to_tsvector("english", "col1")`
+	expected := `to_tsvector('english', "col1")`
 	if expr.Expression() != expected {
 		t.Errorf("ToTsVector.Expression() = %v, want %v", expr.Expression(), expected)
 	}
 }
 
-func TestToTsVector_ColumnName(t *testing.T) {
+func TestToTsVector_ColumnNames(t *testing.T) {
 	expr := ToTSVector{Config: "english", Column: "col1"}
-	expected := "col1"
-	if expr.ColumnName() != expected {
-		t.Errorf("ToTsVector.ColumnName() = %v, want %v", expr.ColumnName(), expected)
+	expected := []string{"col1"}
+	if !slices.Equal(expr.ColumnNames(), expected) {
+		t.Errorf("ToTsVector.ColumnNames() = %v, want %v", expr.ColumnNames(), expected)
+	}
+}
+
+func TestRaw_Expression(t *testing.T) {
+	expr := Raw{SQL: `"first_name" || ' ' || "last_name"`, Columns: []string{"first_name", "last_name"}}
+	if got, want := expr.Expression(), `"first_name" || ' ' || "last_name"`; got != want {
+		t.Errorf("Raw.Expression() = %v, want %v", got, want)
+	}
+	if got, want := expr.ColumnNames(), []string{"first_name", "last_name"}; !slices.Equal(got, want) {
+		t.Errorf("Raw.ColumnNames() = %v, want %v", got, want)
+	}
+}
+
+func TestExpression_Tree(t *testing.T) {
+	tests := []struct {
+		name        string
+		expr        Expression
+		want        string
+		wantColumns []string
+	}{
+		{
+			name:        "Column reference",
+			expr:        Ref{Column: `odd"name`},
+			want:        `"odd""name"`,
+			wantColumns: []string{`odd"name`},
+		},
+		{
+			name: "Typed literals",
+			expr: Func{Name: "greatest", Args: []Expression{
+				Literal{Value: "it's"},
+				Literal{Value: 42},
+				Literal{Value: 1.5},
+				Literal{Value: true},
+				Literal{Value: nil},
+				Literal{Value: "1 day", Type: "interval"},
+			}},
+			want: `greatest('it''s', 42, 1.5, TRUE, NULL, '1 day'::interval)`,
+		},
+		{
+			name:        "Arithmetic",
+			expr:        Binary{Left: Binary{Left: Ref{Column: "quantity"}, Operator: "*", Right: Ref{Column: "unit_price"}}, Operator: "-", Right: Ref{Column: "discount"}},
+			want:        `(("quantity" * "unit_price") - "discount")`,
+			wantColumns: []string{"quantity", "unit_price", "discount"},
+		},
+		{
+			name: "Boolean logic",
+			expr: AllOf{Operands: []Expression{
+				Binary{Left: Ref{Column: "starts_at"}, Operator: "<", Right: Ref{Column: "ends_at"}},
+				Negate{Operand: AnyOf{Operands: []Expression{Ref{Column: "cancelled"}, Ref{Column: "archived"}}}},
+			}},
+			want:        `(("starts_at" < "ends_at") AND (NOT ("cancelled" OR "archived")))`,
+			wantColumns: []string{"starts_at", "ends_at", "cancelled", "archived"},
+		},
+		{
+			name:        "Cast and coalesce",
+			expr:        Coalesce{Args: []Expression{Cast{Operand: Ref{Column: "total"}, Type: "numeric(10, 2)"}, Literal{Value: 0}}},
+			want:        `COALESCE(CAST("total" AS numeric(10, 2)), 0)`,
+			wantColumns: []string{"total"},
+		},
+		{
+			name:        "JSON accessors",
+			expr:        JSONGetText{Operand: JSONGet{Operand: Ref{Column: "metadata"}, Key: "address"}, Key: "city"},
+			want:        `(("metadata" -> 'address') ->> 'city')`,
+			wantColumns: []string{"metadata"},
+		},
+		{
+			name:        "JSON array element",
+			expr:        JSONGet{Operand: Ref{Column: "tags"}, Key: 0},
+			want:        `("tags" -> 0)`,
+			wantColumns: []string{"tags"},
+		},
+		{
+			name:        "JSON containment",
+			expr:        JSONContains{Operand: Ref{Column: "metadata"}, Value: Literal{Value: `{"active": true}`, Type: "jsonb"}},
+			want:        `("metadata" @> '{"active": true}'::jsonb)`,
+			wantColumns: []string{"metadata"},
+		},
+		{
+			name:        "Date trunc",
+			expr:        DateTrunc{Field: "month", Operand: Ref{Column: "created_at"}},
+			want:        `date_trunc('month', "created_at")`,
+			wantColumns: []string{"created_at"},
+		},
+		{
+			name:        "Repeated columns",
+			expr:        Binary{Left: Ref{Column: "a"}, Operator: "+", Right: Ref{Column: "a"}},
+			want:        `("a" + "a")`,
+			wantColumns: []string{"a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateExpression(tt.expr); err != nil {
+				t.Fatalf("validateExpression() error = %v", err)
+			}
+			if got := tt.expr.Expression(); got != tt.want {
+				t.Errorf("Expression.Expression() = %v, want %v", got, tt.want)
+			}
+			if got := tt.expr.ColumnNames(); !slices.Equal(got, tt.wantColumns) {
+				t.Errorf("Expression.ColumnNames() = %v, want %v", got, tt.wantColumns)
+			}
+		})
+	}
+}
+
+func TestExpression_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		expr Expression
+	}{
+		{"Nil", nil},
+		{"Empty column reference", Ref{}},
+		{"Unsupported literal", Literal{Value: struct{}{}}},
+		{"Infinite literal", Literal{Value: math.Inf(1)}},
+		{"Invalid literal type", Literal{Value: "x", Type: "text; DROP"}},
+		{"Invalid function name", Func{Name: "now()--"}},
+		{"Invalid function argument", Func{Name: "abs", Args: []Expression{nil}}},
+		{"Invalid operator", Binary{Left: Ref{Column: "a"}, Operator: "; DROP", Right: Ref{Column: "b"}}},
+		{"Missing operand", Binary{Left: Ref{Column: "a"}, Operator: "+"}},
+		{"Empty conjunction", AllOf{}},
+		{"Nested invalid operand", AnyOf{Operands: []Expression{Negate{Operand: Ref{}}}}},
+		{"Invalid cast type", Cast{Operand: Ref{Column: "a"}, Type: "int)"}},
+		{"Empty coalesce", Coalesce{}},
+		{"Invalid JSON key", JSONGet{Operand: Ref{Column: "a"}, Key: 1.5}},
+		{"Invalid date_trunc field", DateTrunc{Field: "fortnight", Operand: Ref{Column: "a"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateExpression(tt.expr); err == nil {
+				t.Error("validateExpression() error = nil, want error")
+			}
+		})
 	}
 }
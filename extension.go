@@ -0,0 +1,238 @@
+package trenovaorm
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Extension is a PostgreSQL extension a schema depends on, such as uuid-ossp.
+type Extension string
+
+const (
+	ExtensionUUIDOSSP  Extension = "uuid-ossp" // uuid_generate_v1() to uuid_generate_v5()
+	ExtensionPgcrypto  Extension = "pgcrypto"  // crypt(), digest() and gen_random_bytes()
+	ExtensionPgTrgm    Extension = "pg_trgm"   // Trigram similarity and the gin_trgm_ops and gist_trgm_ops operator classes
+	ExtensionCitext    Extension = "citext"    // The case-insensitive citext type
+	ExtensionHstore    Extension = "hstore"
+	ExtensionLtree     Extension = "ltree"
+	ExtensionUnaccent  Extension = "unaccent"
+	ExtensionBtreeGist Extension = "btree_gist" // Scalar types in gist indexes and exclusion constraints
+	ExtensionBtreeGin  Extension = "btree_gin"
+	ExtensionBloom     Extension = "bloom"  // The bloom index access method
+	ExtensionVector    Extension = "vector" // pgvector's vector type and its hnsw and ivfflat indexes
+)
+
+// UseGenRandomUUID renders UUIDGenerateV4 as gen_random_uuid(), which is built
+// into PostgreSQL 13 and later, so that schemas do not require uuid-ossp. Like
+// DefaultSchemaName, set it before compiling any model.
+var UseGenRandomUUID bool
+
+// functionExtensions maps the functions of extensions to the extension.
+var functionExtensions = map[string]Extension{
+	"uuid_generate_v1":   ExtensionUUIDOSSP,
+	"uuid_generate_v1mc": ExtensionUUIDOSSP,
+	"uuid_generate_v3":   ExtensionUUIDOSSP,
+	"uuid_generate_v4":   ExtensionUUIDOSSP,
+	"uuid_generate_v5":   ExtensionUUIDOSSP,
+	"gen_random_bytes":   ExtensionPgcrypto,
+	"crypt":              ExtensionPgcrypto,
+	"gen_salt":           ExtensionPgcrypto,
+	"digest":             ExtensionPgcrypto,
+	"hmac":               ExtensionPgcrypto,
+	"similarity":         ExtensionPgTrgm,
+	"word_similarity":    ExtensionPgTrgm,
+	"unaccent":           ExtensionUnaccent,
+}
+
+// typeExtensions maps the types of extensions to the extension.
+var typeExtensions = map[string]Extension{
+	"citext": ExtensionCitext,
+	"hstore": ExtensionHstore,
+	"ltree":  ExtensionLtree,
+	"vector": ExtensionVector,
+}
+
+// operatorClassExtensions maps the operator classes of extensions to the extension.
+var operatorClassExtensions = map[string]Extension{
+	"gin_trgm_ops":    ExtensionPgTrgm,
+	"gist_trgm_ops":   ExtensionPgTrgm,
+	"gist_ltree_ops":  ExtensionLtree,
+	"gist_hstore_ops": ExtensionHstore,
+	"gin_hstore_ops":  ExtensionHstore,
+}
+
+// methodExtensions maps the index access methods of extensions to the extension.
+var methodExtensions = map[IndexMethod]Extension{
+	"bloom":   ExtensionBloom,
+	"hnsw":    ExtensionVector,
+	"ivfflat": ExtensionVector,
+}
+
+// btreeGistTypes are the scalar types that have no gist operator class
+// without btree_gist.
+var btreeGistTypes = map[string]bool{
+	"smallint": true, "integer": true, "bigint": true, "int2": true, "int4": true, "int8": true,
+	"smallserial": true, "serial": true, "bigserial": true,
+	"numeric": true, "decimal": true, "real": true, "double precision": true, "float4": true, "float8": true, "money": true,
+	"text": true, "varchar": true, "character varying": true, "char": true, "character": true, "bpchar": true,
+	"uuid": true, "boolean": true, "bool": true, "bytea": true, "bit": true, "varbit": true, "oid": true,
+	"date": true, "time": true, "timetz": true, "timestamp": true, "timestamptz": true, "interval": true,
+	"time with time zone": true, "time without time zone": true,
+	"timestamp with time zone": true, "timestamp without time zone": true,
+	"inet": true, "cidr": true, "macaddr": true, "macaddr8": true,
+}
+
+var (
+	// functionCall matches the name of each function called by an SQL expression.
+	functionCall = regexp.MustCompile(`([A-Za-z_][A-Za-z0-9_]*)\s*\(`)
+	// quotedLiteral matches the string literals of an SQL expression.
+	quotedLiteral = regexp.MustCompile(`'(?:[^']|'')*'`)
+	// typeModifier matches the modifiers of an SQL type, e.g. (19, 4).
+	typeModifier = regexp.MustCompile(`\s*\([^)]*\)`)
+)
+
+// CreateSQL generates the statement installing the extension.
+func (e Extension) CreateSQL() string {
+	return fmt.Sprintf("CREATE EXTENSION IF NOT EXISTS %s;", QuoteIdentifier(string(e)))
+}
+
+// Validate checks the extension name.
+func (e Extension) Validate() error {
+	if strings.TrimSpace(string(e)) == "" {
+		return errors.New("extension name cannot be empty")
+	}
+	return nil
+}
+
+// ExtensionModel is implemented by models that require extensions the
+// compiler does not detect, e.g. one providing a function used by a raw check
+// constraint. It is optional, like the table option interfaces.
+type ExtensionModel interface {
+	Extensions() []Extension
+}
+
+// ModelExtensions returns the extensions a model declares, or nil if it does
+// not implement ExtensionModel.
+func ModelExtensions(model Model) []Extension {
+	if m, ok := model.(ExtensionModel); ok {
+		return m.Extensions()
+	}
+	return nil
+}
+
+// expressionExtensions returns the extensions providing the functions an SQL
+// expression calls. Names inside string literals, such as a default of
+// 'digest(x)', are not calls.
+func expressionExtensions(sql string) []Extension {
+	var extensions []Extension
+	for _, match := range functionCall.FindAllStringSubmatch(quotedLiteral.ReplaceAllString(sql, "''"), -1) {
+		if ext, ok := functionExtensions[strings.ToLower(match[1])]; ok {
+			extensions = append(extensions, ext)
+		}
+	}
+	return extensions
+}
+
+// typeExtension returns the extension providing an SQL type, ignoring its
+// modifiers and array dimensions.
+func typeExtension(typ string) (Extension, bool) {
+	base := strings.ToLower(strings.TrimSpace(typ))
+	if i := strings.IndexAny(base, "(["); i >= 0 {
+		base = strings.TrimSpace(base[:i])
+	}
+	ext, ok := typeExtensions[base]
+	return ext, ok
+}
+
+// Extensions returns the extensions the table requires: those providing the
+// types, defaults and generation expressions of its columns, the functions its
+// constraints call, btree_gist for exclusion constraints comparing scalar
+// columns with gist, and the access methods, operator classes and expressions of
+// its indexes, followed by those its model declares. Each is listed once, in
+// order of first use.
+func (t *Table) Extensions() []Extension {
+	var extensions []Extension
+	for _, field := range t.Fields {
+		col := ColumnOf(field)
+		if ext, ok := typeExtension(col.Type); ok {
+			extensions = append(extensions, ext)
+		}
+		extensions = append(extensions, expressionExtensions(col.Default)...)
+		extensions = append(extensions, expressionExtensions(col.Generated)...)
+	}
+
+	for _, c := range t.Constraints {
+		extensions = append(extensions, expressionExtensions(c.Definition())...)
+		if exclude, ok := asExclude(c); ok && t.excludesScalarsWithGist(exclude) {
+			extensions = append(extensions, ExtensionBtreeGist)
+		}
+	}
+
+	extensions = append(extensions, indexExtensions(t.Indexes)...)
+	extensions = append(extensions, ModelExtensions(t.Model)...)
+	return uniqueExtensions(extensions)
+}
+
+// excludesScalarsWithGist reports whether an exclusion constraint of the table
+// uses gist on a scalar column, such as the room of a booking compared with =,
+// which requires the operator classes of btree_gist.
+func (t *Table) excludesScalarsWithGist(c Exclude) bool {
+	if c.Using != "" && !strings.EqualFold(c.Using, "gist") {
+		return false
+	}
+	for _, elem := range c.Elements {
+		if field := t.Field(elem.Column); field != nil && isBtreeGistType(ColumnOf(field).Type) {
+			return true
+		}
+	}
+	return false
+}
+
+// isBtreeGistType reports whether an SQL type is a scalar type that needs
+// btree_gist to be used in a gist index, ignoring its modifiers.
+func isBtreeGistType(typ string) bool {
+	base := strings.ToLower(typeModifier.ReplaceAllString(typ, ""))
+	return btreeGistTypes[strings.Join(strings.Fields(base), " ")]
+}
+
+// indexExtensions returns the extensions providing the access methods,
+// operator classes and the functions of the expressions and predicates of
+// indexes.
+func indexExtensions(indexes []Index) []Extension {
+	var extensions []Extension
+	for _, idx := range indexes {
+		if method, err := idx.method(); err == nil {
+			if ext, ok := methodExtensions[method]; ok {
+				extensions = append(extensions, ext)
+			}
+		}
+		for _, exp := range idx.Expressions {
+			extensions = append(extensions, expressionExtensions(exp.Expression())...)
+		}
+		for _, elem := range idx.Elements {
+			if elem.Expression != nil {
+				extensions = append(extensions, expressionExtensions(elem.Expression.Expression())...)
+			}
+			if ext, ok := operatorClassExtensions[strings.ToLower(elem.OperatorClass)]; ok {
+				extensions = append(extensions, ext)
+			}
+		}
+		extensions = append(extensions, expressionExtensions(idx.predicate())...)
+	}
+	return extensions
+}
+
+// uniqueExtensions removes repeated extensions, keeping the first of each.
+func uniqueExtensions(extensions []Extension) []Extension {
+	seen := make(map[Extension]bool, len(extensions))
+	unique := extensions[:0:0]
+	for _, ext := range extensions {
+		if !seen[ext] {
+			seen[ext] = true
+			unique = append(unique, ext)
+		}
+	}
+	return unique
+}
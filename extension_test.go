@@ -0,0 +1,212 @@
+package trenovaorm
+
+import "testing"
+
+type extensionModel struct {
+	testModel
+	extensions []Extension
+}
+
+func (m *extensionModel) Extensions() []Extension {
+	return m.extensions
+}
+
+func TestSchema_Extensions(t *testing.T) {
+	customers := &testModel{
+		name: "customers",
+		fields: []Field{
+			&UUIDField{ColumnName: "id", PrimaryKey: true, Default: UUIDGenerateV4},
+			&TextField{ColumnName: "email", CustomType: "citext"},
+			&TextField{ColumnName: "name"},
+		},
+		indexes: []Index{{
+			Method:   IndexGin,
+			Elements: []IndexElement{{Column: "name", OperatorClass: "gin_trgm_ops"}},
+		}},
+	}
+
+	got, err := mustCompile(t, customers).Statements()
+	if err != nil {
+		t.Fatalf("Schema.Statements() error = %v", err)
+	}
+	assertStatements(t, got, []string{
+		`CREATE EXTENSION IF NOT EXISTS "uuid-ossp";`,
+		`CREATE EXTENSION IF NOT EXISTS "citext";`,
+		`CREATE EXTENSION IF NOT EXISTS "pg_trgm";`,
+		`CREATE TABLE IF NOT EXISTS "customers" ("id" uuid NOT NULL PRIMARY KEY DEFAULT uuid_generate_v4(), "email" citext NOT NULL, "name" TEXT NOT NULL);`,
+		`CREATE INDEX IF NOT EXISTS "customers_name_idx" ON "customers" USING gin ("name" gin_trgm_ops);`,
+	})
+}
+
+func TestSchema_ViewIndexExtensions(t *testing.T) {
+	emails := &materializedViewModel{
+		testModel: testModel{name: "user_emails", indexes: []Index{{
+			Method:   IndexGin,
+			Elements: []IndexElement{{Column: "email", OperatorClass: "gin_trgm_ops"}},
+		}}},
+		view: MaterializedView{View: View{Query: Select(queryUsers, "id", "email")}},
+	}
+
+	got, err := mustCompile(t, queryOrganizations, queryUsers, emails).Statements()
+	if err != nil {
+		t.Fatalf("Schema.Statements() error = %v", err)
+	}
+	if got[0].SQL != `CREATE EXTENSION IF NOT EXISTS "pg_trgm";` {
+		t.Errorf("first statement = %v, want the pg_trgm extension", got[0].SQL)
+	}
+	last := got[len(got)-1].SQL
+	if want := `CREATE INDEX IF NOT EXISTS "user_emails_email_idx" ON "user_emails" USING gin ("email" gin_trgm_ops);`; last != want {
+		t.Errorf("last statement = %v, want %v", last, want)
+	}
+}
+
+func TestSchema_UseGenRandomUUID(t *testing.T) {
+	UseGenRandomUUID = true
+	t.Cleanup(func() { UseGenRandomUUID = false })
+
+	model := &testModel{name: "tokens", fields: []Field{&UUIDField{ColumnName: "id", PrimaryKey: true, Default: UUIDGenerateV4}}}
+	got, err := mustCompile(t, model).Statements()
+	if err != nil {
+		t.Fatalf("Schema.Statements() error = %v", err)
+	}
+	assertStatements(t, got, []string{
+		`CREATE TABLE IF NOT EXISTS "tokens" ("id" uuid NOT NULL PRIMARY KEY DEFAULT gen_random_uuid());`,
+	})
+}
+
+func TestPSQLFunction_Extension(t *testing.T) {
+	tests := []struct {
+		fn   PSQLFunction
+		want Extension
+	}{
+		{UUIDGenerateV4, ExtensionUUIDOSSP},
+		{PSQLFunction("uuid_generate_v1mc()"), ExtensionUUIDOSSP},
+		{PSQLFunction("gen_random_bytes(16)"), ExtensionPgcrypto},
+		{GenRandomUUID, ""},
+		{CurrentTimestamp, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fn.String(), func(t *testing.T) {
+			got, ok := tt.fn.Extension()
+			if got != tt.want || ok != (tt.want != "") {
+				t.Errorf("PSQLFunction.Extension() = %v, %v, want %v", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestTable_Extensions(t *testing.T) {
+	model := &extensionModel{
+		testModel: testModel{
+			name: "documents",
+			fields: []Field{
+				&UUIDField{ColumnName: "id", PrimaryKey: true},
+				&ArrayField{ColumnName: "tags", Element: &TextField{CustomType: "citext"}},
+				&GeneratedField{Field: &TextField{ColumnName: "plain"}, Expression: Func{Name: "unaccent", Args: []Expression{Ref{Column: "title"}}}},
+				&TextField{ColumnName: "title"},
+			},
+			indexes: []Index{{Method: "bloom", Columns: []string{"title"}}},
+		},
+		extensions: []Extension{ExtensionBtreeGist, ExtensionCitext},
+	}
+
+	table := mustCompile(t, model).Table("documents")
+	want := []Extension{ExtensionCitext, ExtensionUnaccent, ExtensionBloom, ExtensionBtreeGist}
+	got := table.Extensions()
+	if len(got) != len(want) {
+		t.Fatalf("Table.Extensions() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Table.Extensions() = %v, want %v", got, want)
+		}
+	}
+
+	model.extensions = []Extension{" "}
+	if _, err := Compile(model); err == nil {
+		t.Error("Compile() with a blank extension error = nil, want error")
+	}
+}
+
+func TestTable_InferredExtensions(t *testing.T) {
+	bookings := func(elements ...ExcludeElement) Model {
+		return &constrainedModel{
+			testModel: testModel{name: "bookings", fields: []Field{
+				&UUIDField{ColumnName: "room_id"},
+				&TextField{ColumnName: "during", CustomType: "tstzrange"},
+			}},
+			constraints: []TableConstraint{Exclude{Elements: elements}},
+		}
+	}
+	tests := []struct {
+		name  string
+		model Model
+		want  []Extension
+	}{
+		{
+			name:  "Function name in a string literal",
+			model: &testModel{name: "notes", fields: []Field{&CharField{ColumnName: "body", MaxLength: 50, Default: "digest(x)"}}},
+		},
+		{
+			name: "Function call in a check",
+			model: &constrainedModel{
+				testModel:   testModel{name: "notes", fields: []Field{&TextField{ColumnName: "body"}}},
+				constraints: []TableConstraint{Check{Expression: `digest("body", 'sha256') <> 'unaccent(x)'`}},
+			},
+			want: []Extension{ExtensionPgcrypto},
+		},
+		{
+			name:  "Exclusion on a scalar with gist",
+			model: bookings(ExcludeElement{Column: "room_id", Operator: "="}, ExcludeElement{Column: "during", Operator: "&&"}),
+			want:  []Extension{ExtensionBtreeGist},
+		},
+		{
+			name:  "Exclusion on a range",
+			model: bookings(ExcludeElement{Column: "during", Operator: "&&"}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := mustCompile(t, tt.model)
+			got := schema.Table(tt.model.TableName()).Extensions()
+			if len(got) != len(tt.want) {
+				t.Fatalf("Table.Extensions() = %v, want %v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("Table.Extensions() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestDiff_Extensions(t *testing.T) {
+	from := mustCompile(t, &testModel{name: "customers", fields: []Field{&UUIDField{ColumnName: "id", PrimaryKey: true, Default: UUIDGenerateV4}}})
+	to := mustCompile(t, &testModel{
+		name: "customers",
+		fields: []Field{
+			&UUIDField{ColumnName: "id", PrimaryKey: true, Default: UUIDGenerateV4},
+			&TextField{ColumnName: "email", CustomType: "citext"},
+			&TextField{ColumnName: "name"},
+		},
+		indexes: []Index{{
+			Method:   IndexGin,
+			Elements: []IndexElement{{Column: "name", OperatorClass: "gin_trgm_ops"}},
+		}},
+	})
+
+	got, err := Diff(from, to)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	assertStatements(t, got, []string{
+		`CREATE EXTENSION IF NOT EXISTS "citext";`,
+		`CREATE EXTENSION IF NOT EXISTS "pg_trgm";`,
+		`ALTER TABLE "customers" ADD COLUMN "email" citext NOT NULL;`,
+		`ALTER TABLE "customers" ADD COLUMN "name" TEXT NOT NULL;`,
+		`CREATE INDEX IF NOT EXISTS "customers_name_idx" ON "customers" USING gin ("name" gin_trgm_ops);`,
+	})
+}
@@ -1,6 +1,7 @@
 package trenovaorm
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 )
@@ -25,20 +26,121 @@ const (
 	OnUpdateNoAction OnUpdateOption = "NO ACTION"
 )
 
+// MatchOption defines how a multi-column foreign key treats NULL columns.
+type MatchOption string
+
+const (
+	// MatchSimple lets a row skip the check when any key column is NULL. It is
+	// the PostgreSQL default and is not rendered.
+	MatchSimple MatchOption = "SIMPLE"
+	// MatchFull requires the key columns to be either all NULL or all non-NULL.
+	MatchFull MatchOption = "FULL"
+)
+
 // Annotation represents the annotations for foreign key relationships.
 type Annotation struct {
 	OnDelete OnDeleteOption
 	OnUpdate OnUpdateOption
+	Match    MatchOption
+	// Deferrable allows the check to be postponed to the end of the
+	// transaction with SET CONSTRAINTS ... DEFERRED.
+	Deferrable bool
+	// InitiallyDeferred defers the check to the end of the transaction by
+	// default. It requires Deferrable.
+	InitiallyDeferred bool
 }
 
 func (a Annotation) String() string {
 	return fmt.Sprintf("ON DELETE %s ON UPDATE %s", a.OnDelete, a.OnUpdate)
 }
 
+// clause renders the options that follow the REFERENCES clause of a foreign key.
+func (a Annotation) clause() string {
+	var clause string
+	if a.Match == MatchFull {
+		clause += fmt.Sprintf(" MATCH %s", a.Match)
+	}
+	if a.OnDelete != "" {
+		clause += fmt.Sprintf(" ON DELETE %s", a.OnDelete)
+	}
+	if a.OnUpdate != "" {
+		clause += fmt.Sprintf(" ON UPDATE %s", a.OnUpdate)
+	}
+	if a.Deferrable {
+		clause += " DEFERRABLE"
+		if a.InitiallyDeferred {
+			clause += " INITIALLY DEFERRED"
+		}
+	}
+	return clause
+}
+
+// Validate checks the match option and the deferral options.
+func (a Annotation) Validate() error {
+	if a.Match != "" && a.Match != MatchSimple && a.Match != MatchFull {
+		return fmt.Errorf("unsupported match option %q", a.Match)
+	}
+	if a.InitiallyDeferred && !a.Deferrable {
+		return errors.New("an initially deferred foreign key must be deferrable")
+	}
+	return nil
+}
+
+// ForeignKey is a foreign key constraint over one or more columns, e.g. the
+// pair (organization_id, business_unit_id) referencing the composite primary
+// key of business_units. Single-column keys are usually declared with a
+// ForeignKeyField instead, which renders through a ForeignKey.
+type ForeignKey struct {
+	Name             string // Constraint name, defaults to <table>_<columns>_fkey
+	Columns          []string
+	ReferenceTable   string // Qualified with its schema, e.g. billing.invoices, if it is in another schema
+	ReferenceColumns []string
+	Annotations      Annotation
+}
+
+// ConstraintName returns the name of the constraint on the given table.
+func (c ForeignKey) ConstraintName(tableName string) string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return fmt.Sprintf("%s_%s_fkey", unqualifiedName(tableName), strings.Join(c.Columns, "_"))
+}
+
+// Definition returns the FOREIGN KEY clause.
+func (c ForeignKey) Definition() string {
+	return fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s(%s)%s",
+		joinColumns(c.Columns), QuoteQualifiedName(c.ReferenceTable), joinColumns(c.ReferenceColumns), c.Annotations.clause())
+}
+
+// ColumnNames returns the referencing columns.
+func (c ForeignKey) ColumnNames() []string {
+	return c.Columns
+}
+
+// Validate checks the columns, the referenced columns and the annotations.
+func (c ForeignKey) Validate() error {
+	if err := validateColumns(c.Columns); err != nil {
+		return fmt.Errorf("foreign key: %w", err)
+	}
+	if c.ReferenceTable == "" {
+		return errors.New("foreign key: reference table cannot be empty")
+	}
+	if err := validateColumns(c.ReferenceColumns); err != nil {
+		return fmt.Errorf("foreign key: referenced columns: %w", err)
+	}
+	if len(c.Columns) != len(c.ReferenceColumns) {
+		return fmt.Errorf("foreign key: %d columns reference %d columns", len(c.Columns), len(c.ReferenceColumns))
+	}
+	if err := c.Annotations.Validate(); err != nil {
+		return fmt.Errorf("foreign key: %w", err)
+	}
+	return nil
+}
+
 // ForeignKeyField represents a foreign key field in the database.
 type ForeignKeyField struct {
 	ColumnName     string
-	ReferenceTable string
+	ReferenceTable string // Qualified with its schema, e.g. billing.invoices, if it is in another schema
 	ReferenceField string
 	Annotations    Annotation
 	Nullable       bool
@@ -50,6 +152,7 @@ type ForeignKeyField struct {
 	Constraints    []string
 	StructTag      string
 	ReferencedType string // The Go type of the referenced field
+	ForeignKeyName string // Constraint name, defaults to <table>_<column>_fkey
 }
 
 // Definition generates the SQL definition for the ForeignKeyField.
@@ -58,7 +161,7 @@ func (f *ForeignKeyField) Definition() string {
 	if f.CustomType != "" {
 		typ = f.CustomType
 	}
-	def := fmt.Sprintf(`"%s" %s`, f.ColumnName, typ)
+	def := fmt.Sprintf("%s %s", QuoteIdentifier(f.ColumnName), typ)
 
 	if !f.Nullable {
 		def += " NOT NULL"
@@ -69,7 +172,7 @@ func (f *ForeignKeyField) Definition() string {
 	}
 
 	if f.Default != "" {
-		def += fmt.Sprintf(" DEFAULT '%s'", f.Default)
+		def += " DEFAULT " + QuoteLiteral(f.Default)
 	}
 
 	if len(f.Constraints) > 0 {
@@ -79,16 +182,25 @@ func (f *ForeignKeyField) Definition() string {
 	return def
 }
 
+// ForeignKey returns the table-level constraint declared by the field.
+func (f *ForeignKeyField) ForeignKey() ForeignKey {
+	return ForeignKey{
+		Name:             f.ForeignKeyName,
+		Columns:          []string{f.ColumnName},
+		ReferenceTable:   f.ReferenceTable,
+		ReferenceColumns: []string{f.ReferenceField},
+		Annotations:      f.Annotations,
+	}
+}
+
 // ForeignKeyConstraint generates the SQL for the foreign key constraint.
 func (f *ForeignKeyField) ForeignKeyConstraint(tableName string) string {
-	constraint := fmt.Sprintf(`FOREIGN KEY ("%s") REFERENCES "%s"("%s")`, f.ColumnName, f.ReferenceTable, f.ReferenceField)
-	if f.Annotations.OnDelete != "" {
-		constraint += fmt.Sprintf(" ON DELETE %s", f.Annotations.OnDelete)
-	}
-	if f.Annotations.OnUpdate != "" {
-		constraint += fmt.Sprintf(" ON UPDATE %s", f.Annotations.OnUpdate)
-	}
-	return constraint
+	return f.ForeignKey().Definition()
+}
+
+// ConstraintName returns the name of the foreign key constraint.
+func (f *ForeignKeyField) ConstraintName(tableName string) string {
+	return f.ForeignKey().ConstraintName(tableName)
 }
 
 // Name returns the column name for the ForeignKeyField.
@@ -101,7 +213,7 @@ func (f *ForeignKeyField) CommentSQL(tableName string) string {
 	if f.Comment == "" {
 		return ""
 	}
-	return fmt.Sprintf(`COMMENT ON COLUMN "%s"."%s" IS '%s';`, tableName, f.ColumnName, f.Comment)
+	return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;", QuoteQualifiedName(tableName), QuoteIdentifier(f.ColumnName), QuoteLiteral(f.Comment))
 }
 
 // Validate checks if the field's configuration is valid.
@@ -112,7 +224,7 @@ func (f *ForeignKeyField) Validate() error {
 	if f.ReferenceTable == "" || f.ReferenceField == "" {
 		return fmt.Errorf("references cannot be empty")
 	}
-	return nil
+	return f.Annotations.Validate()
 }
 
 // GoType returns the Go type for the ForeignKeyField.
@@ -132,6 +244,11 @@ func (f *ForeignKeyField) IndexSQL(tableName string) string {
 	if f.Unique {
 		indexType = "UNIQUE INDEX"
 	}
-	indexName := fmt.Sprintf("%s_%s_idx", tableName, f.ColumnName)
-	return fmt.Sprintf(`CREATE %s "%s" ON "%s" ("%s");`, indexType, indexName, tableName, f.ColumnName)
+	indexName := f.IndexName(tableName)
+	return fmt.Sprintf("CREATE %s %s ON %s (%s);", indexType, QuoteIdentifier(indexName), QuoteQualifiedName(tableName), QuoteIdentifier(f.ColumnName))
+}
+
+// IndexName returns the name of the index generated by IndexSQL.
+func (f *ForeignKeyField) IndexName(tableName string) string {
+	return fmt.Sprintf("%s_%s_idx", unqualifiedName(tableName), f.ColumnName)
 }
@@ -256,6 +256,22 @@ func TestForeignKeyField_ForeignKeyConstraint(t *testing.T) {
 			table:    "orders",
 			expected: `FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE ON UPDATE CASCADE`,
 		},
+		{
+			name: "Deferrable ForeignKeyField constraint",
+			field: ForeignKeyField{
+				ColumnName:     "parent_id",
+				ReferenceTable: "nodes",
+				ReferenceField: "id",
+				Annotations: Annotation{
+					Match:             MatchSimple,
+					OnDelete:          OnDeleteSetNull,
+					Deferrable:        true,
+					InitiallyDeferred: true,
+				},
+			},
+			table:    "nodes",
+			expected: `FOREIGN KEY ("parent_id") REFERENCES "nodes"("id") ON DELETE SET NULL DEFERRABLE INITIALLY DEFERRED`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -266,3 +282,59 @@ func TestForeignKeyField_ForeignKeyConstraint(t *testing.T) {
 		})
 	}
 }
+
+func TestForeignKeyField_ConstraintName(t *testing.T) {
+	field := ForeignKeyField{ColumnName: "user_id", ReferenceTable: "users", ReferenceField: "id"}
+	if got, want := field.ConstraintName("orders"), "orders_user_id_fkey"; got != want {
+		t.Errorf("ForeignKeyField.ConstraintName() = %v, want %v", got, want)
+	}
+
+	field.ForeignKeyName = "orders_customer_fkey"
+	if got, want := field.ConstraintName("orders"), "orders_customer_fkey"; got != want {
+		t.Errorf("ForeignKeyField.ConstraintName() = %v, want %v", got, want)
+	}
+}
+
+func TestForeignKey_Definition(t *testing.T) {
+	fk := ForeignKey{
+		Columns:          []string{"organization_id", "business_unit_id"},
+		ReferenceTable:   "business_units",
+		ReferenceColumns: []string{"organization_id", "id"},
+		Annotations:      Annotation{Match: MatchFull, OnDelete: OnDeleteCascade, Deferrable: true},
+	}
+
+	if got, want := fk.ConstraintName("shipments"), "shipments_organization_id_business_unit_id_fkey"; got != want {
+		t.Errorf("ForeignKey.ConstraintName() = %v, want %v", got, want)
+	}
+	want := `FOREIGN KEY ("organization_id", "business_unit_id") REFERENCES "business_units"("organization_id", "id") MATCH FULL ON DELETE CASCADE DEFERRABLE`
+	if got := fk.Definition(); got != want {
+		t.Errorf("ForeignKey.Definition() = %v, want %v", got, want)
+	}
+}
+
+func TestForeignKey_Validate(t *testing.T) {
+	valid := ForeignKey{Columns: []string{"a", "b"}, ReferenceTable: "t", ReferenceColumns: []string{"x", "y"}}
+
+	tests := []struct {
+		name    string
+		modify  func(fk *ForeignKey)
+		wantErr bool
+	}{
+		{name: "Valid", modify: func(*ForeignKey) {}, wantErr: false},
+		{name: "No columns", modify: func(fk *ForeignKey) { fk.Columns = nil }, wantErr: true},
+		{name: "No reference table", modify: func(fk *ForeignKey) { fk.ReferenceTable = "" }, wantErr: true},
+		{name: "Column count mismatch", modify: func(fk *ForeignKey) { fk.ReferenceColumns = []string{"x"} }, wantErr: true},
+		{name: "Unsupported match", modify: func(fk *ForeignKey) { fk.Annotations.Match = "PARTIAL" }, wantErr: true},
+		{name: "Initially deferred without deferrable", modify: func(fk *ForeignKey) { fk.Annotations.InitiallyDeferred = true }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fk := valid
+			tt.modify(&fk)
+			if err := fk.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("ForeignKey.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,15 +1,29 @@
 package trenovaorm
 
+import "strings"
+
 // Function represents a PostgreSQL function.
 type PSQLFunction string
 
 // Predefined PostgreSQL functions.
 const (
 	CurrentTimestamp = PSQLFunction("current_timestamp")
-	UUIDGenerateV4   = PSQLFunction("uuid_generate_v4()")
+	UUIDGenerateV4   = PSQLFunction("uuid_generate_v4()") // Requires uuid-ossp, unless UseGenRandomUUID is set
+	GenRandomUUID    = PSQLFunction("gen_random_uuid()")  // Built into PostgreSQL 13 and later
 )
 
 // Default returns the function as a default value for a field.
 func (f PSQLFunction) String() string {
+	if f == UUIDGenerateV4 && UseGenRandomUUID {
+		return string(GenRandomUUID)
+	}
 	return string(f)
 }
+
+// Extension returns the extension that provides the function, or false if it
+// is built into PostgreSQL.
+func (f PSQLFunction) Extension() (Extension, bool) {
+	name, _, _ := strings.Cut(f.String(), "(")
+	ext, ok := functionExtensions[strings.ToLower(strings.TrimSpace(name))]
+	return ext, ok
+}
@@ -0,0 +1,78 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+	"unicode"
+
+	trenovaorm "github.com/emoss08/trenova-orm"
+)
+
+// EnumFileName returns the name of the generated file for an enum type,
+// prefixed with its schema unless that is the default one.
+func EnumFileName(enum trenovaorm.EnumType) string {
+	return schemaPrefixed(enum.Schema, enum.Name) + "_enum.go"
+}
+
+// EnumTypeName returns the name of the Go type generated for an enum type,
+// prefixed with its schema unless that is the default one, e.g.
+// BillingPaymentStatus.
+func EnumTypeName(enum trenovaorm.EnumType) string {
+	return ExportedName(schemaPrefixed(enum.Schema, enum.Name))
+}
+
+// EnumConstName returns the name of the constant generated for an enum value:
+// the type name followed by the value in CamelCase, e.g. ShipmentStatusInTransit.
+func EnumConstName(enum trenovaorm.EnumType, value string) string {
+	words := strings.FieldsFunc(value, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return EnumTypeName(enum) + ExportedName(strings.ToLower(strings.Join(words, "_")))
+}
+
+// EnumFile generates a formatted Go source file in package pkg that declares a
+// string type for an enum, a constant per value and a Valid method.
+func EnumFile(pkg string, enum trenovaorm.EnumType) ([]byte, error) {
+	if err := enum.Validate(); err != nil {
+		return nil, err
+	}
+
+	name := EnumTypeName(enum)
+	consts := make([]string, len(enum.Values))
+	seen := make(map[string]string, len(enum.Values))
+	for i, v := range enum.Values {
+		c := EnumConstName(enum, v)
+		if other, dup := seen[c]; dup || c == name {
+			return nil, fmt.Errorf("enum type %s: values %q and %q map to the same constant %s", enum.Name, other, v, c)
+		}
+		seen[c] = v
+		consts[i] = c
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "%s\n\npackage %s\n\n", Header, pkg)
+	fmt.Fprintf(&src, "// %s is a value of the %q enum type.\n", name, enum.QualifiedName())
+	fmt.Fprintf(&src, "type %s string\n\n", name)
+
+	fmt.Fprintf(&src, "// Values of %s.\n", name)
+	src.WriteString("const (\n")
+	for i, v := range enum.Values {
+		fmt.Fprintf(&src, "\t%s %s = %q\n", consts[i], name, v)
+	}
+	src.WriteString(")\n\n")
+
+	fmt.Fprintf(&src, "// %sValues returns every value of %s in declaration order.\n", name, name)
+	fmt.Fprintf(&src, "func %sValues() []%s {\n\treturn []%s{%s}\n}\n\n", name, name, name, strings.Join(consts, ", "))
+
+	src.WriteString("// Valid reports whether the value is one of the values of the enum.\n")
+	fmt.Fprintf(&src, "func (e %s) Valid() bool {\n\tswitch e {\n\tcase %s:\n\t\treturn true\n\t}\n\treturn false\n}\n",
+		name, strings.Join(consts, ", "))
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format %s: %w", EnumFileName(enum), err)
+	}
+	return formatted, nil
+}
@@ -0,0 +1,68 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+
+	trenovaorm "github.com/emoss08/trenova-orm"
+)
+
+func TestEnumFile(t *testing.T) {
+	got, err := EnumFile("models", trenovaorm.EnumType{Name: "shipment_status", Values: []string{"new", "in-transit", "Delivered"}})
+	if err != nil {
+		t.Fatalf("EnumFile() error = %v", err)
+	}
+
+	want := `// Code generated by trenova-orm. DO NOT EDIT.
+
+package models
+
+// ShipmentStatus is a value of the "shipment_status" enum type.
+type ShipmentStatus string
+
+// Values of ShipmentStatus.
+const (
+	ShipmentStatusNew       ShipmentStatus = "new"
+	ShipmentStatusInTransit ShipmentStatus = "in-transit"
+	ShipmentStatusDelivered ShipmentStatus = "Delivered"
+)
+
+// ShipmentStatusValues returns every value of ShipmentStatus in declaration order.
+func ShipmentStatusValues() []ShipmentStatus {
+	return []ShipmentStatus{ShipmentStatusNew, ShipmentStatusInTransit, ShipmentStatusDelivered}
+}
+
+// Valid reports whether the value is one of the values of the enum.
+func (e ShipmentStatus) Valid() bool {
+	switch e {
+	case ShipmentStatusNew, ShipmentStatusInTransit, ShipmentStatusDelivered:
+		return true
+	}
+	return false
+}
+`
+	if string(got) != want {
+		t.Errorf("EnumFile() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestEnumFile_ConstantCollision(t *testing.T) {
+	enum := trenovaorm.EnumType{Name: "status", Values: []string{"in_transit", "in-transit"}}
+	if _, err := EnumFile("models", enum); err == nil {
+		t.Error("EnumFile() error = nil, want a constant collision error")
+	}
+}
+
+func TestFile_Enum(t *testing.T) {
+	model := &testModel{fields: []trenovaorm.Field{
+		&trenovaorm.EnumField{ColumnName: "status", TypeName: "role_status", Values: []string{"active"}, Nullable: true},
+	}}
+	got, err := File("models", mustCompile(t, model).Table("user_roles"))
+	if err != nil {
+		t.Fatalf("File() error = %v", err)
+	}
+	want := "\tStatus *RoleStatus `db:\"status\"`\n"
+	if !strings.Contains(string(got), want) {
+		t.Errorf("File() =\n%s\nwant a field %q", got, want)
+	}
+}
@@ -0,0 +1,607 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	trenovaorm "github.com/emoss08/trenova-orm"
+)
+
+// DBFileName is the name of the file holding the helpers shared by generated repositories.
+const DBFileName = "db.go"
+
+// RepositoryFileName returns the name of the generated repository file for a model.
+func RepositoryFileName(model trenovaorm.Model) string {
+	return baseName(model) + "_repository.go"
+}
+
+// RepositoryTestFileName returns the name of the generated repository test file for a model.
+func RepositoryTestFileName(model trenovaorm.Model) string {
+	return baseName(model) + "_repository_test.go"
+}
+
+// repoColumn describes one column of a generated repository.
+type repoColumn struct {
+	Name          string // column name
+	Field         string // struct field name
+	Param         string // parameter name used for primary key arguments
+	GoType        string // qualified Go type
+	JSON          bool   // values are encoded as JSON
+	PrimaryKey    bool
+	ServerDefault bool   // value is generated by the database
+	Generated     bool   // value is computed by the database and cannot be written
+	Sample        string // Go expression for a test value, empty when none is known
+}
+
+// Arg returns the expression passing the column of row as a query argument.
+func (c repoColumn) Arg() string {
+	if c.JSON {
+		return fmt.Sprintf("jsonColumn{&row.%s}", c.Field)
+	}
+	return "row." + c.Field
+}
+
+// Dest returns the expression scanning the column into row.
+func (c repoColumn) Dest() string {
+	if c.JSON {
+		return fmt.Sprintf("jsonColumn{&row.%s}", c.Field)
+	}
+	return "&row." + c.Field
+}
+
+// repository is the template data of a generated repository.
+type repository struct {
+	Package string
+	Imports imports
+	Struct  string
+	Table   string
+	Schema  string // PostgreSQL schema of the table, empty if unqualified
+	Prefix  string // unexported prefix of the generated SQL constants
+
+	Columns   []repoColumn
+	Inserted  []repoColumn // columns written by Insert
+	Returning []repoColumn // columns generated by the database on Insert
+	PK        []repoColumn
+	Updated   []repoColumn // non-key columns written by Update
+
+	InsertSQL, GetSQL, UpdateSQL, DeleteSQL, ListSQL string
+
+	// RequiredReference reports whether a non-nullable foreign key makes a
+	// standalone insert impossible in the generated test.
+	RequiredReference bool
+}
+
+func newRepository(pkg string, table *trenovaorm.Table) (*repository, error) {
+	model := table.Model
+	name := StructName(model)
+	repo := &repository{
+		Package: pkg,
+		Imports: make(imports),
+		Struct:  name,
+		Table:   model.TableName(),
+		Schema:  trenovaorm.ModelSchemaName(model),
+		Prefix:  unexportedName(name),
+	}
+
+	primaryKey := make(map[string]bool)
+	for _, col := range table.PrimaryKey() {
+		primaryKey[col] = true
+	}
+
+	nullable := make(map[string]bool)
+	for _, field := range table.Fields {
+		goType, err := fieldType(field, repo.Imports)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name(), err)
+		}
+		col := trenovaorm.ColumnOf(field)
+		c := repoColumn{
+			Name:          col.Name,
+			Field:         ExportedName(col.Name),
+			Param:         paramName(col.Name),
+			GoType:        goType,
+			PrimaryKey:    primaryKey[col.Name],
+			ServerDefault: col.ServerDefault || col.Generated != "",
+			Generated:     col.Generated != "",
+			Sample:        sampleValue(goType),
+		}
+		switch f := baseField(field).(type) {
+		case *trenovaorm.JSONField:
+			c.JSON = true
+		case *trenovaorm.EnumField:
+			if !f.Nullable {
+				c.Sample = EnumConstName(f.Enum(), f.Values[0])
+			}
+		}
+		nullable[col.Name] = col.Nullable
+		repo.Columns = append(repo.Columns, c)
+		if c.ServerDefault {
+			repo.Returning = append(repo.Returning, c)
+		} else {
+			repo.Inserted = append(repo.Inserted, c)
+		}
+		switch {
+		case c.PrimaryKey:
+			repo.PK = append(repo.PK, c)
+		case !c.Generated && col.Identity.Generation != trenovaorm.IdentityAlways:
+			repo.Updated = append(repo.Updated, c)
+		}
+	}
+	if len(repo.Columns) == 0 {
+		return nil, fmt.Errorf("table %s has no columns", repo.Table)
+	}
+
+	// A foreign key is required unless one of its columns is nullable, as
+	// PostgreSQL does not check a key holding a NULL.
+	for _, fk := range table.ForeignKeys {
+		if !slices.ContainsFunc(fk.Columns, func(name string) bool { return nullable[name] }) {
+			repo.RequiredReference = true
+		}
+	}
+
+	repo.buildSQL()
+	return repo, nil
+}
+
+// buildSQL renders the statements of the repository with $n placeholders.
+func (r *repository) buildSQL() {
+	table := trenovaorm.QuoteQualifiedName(trenovaorm.QualifyName(r.Schema, r.Table))
+	columns := quotedNames(r.Columns)
+
+	if len(r.Inserted) == 0 {
+		r.InsertSQL = fmt.Sprintf("INSERT INTO %s DEFAULT VALUES", table)
+	} else {
+		r.InsertSQL = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+			table, quotedNames(r.Inserted), placeholders(1, len(r.Inserted)))
+	}
+	if len(r.Returning) > 0 {
+		r.InsertSQL += " RETURNING " + quotedNames(r.Returning)
+	}
+
+	order := r.PK
+	if len(order) == 0 {
+		order = r.Columns[:1]
+	}
+	r.ListSQL = fmt.Sprintf("SELECT %s FROM %s ORDER BY %s LIMIT $1 OFFSET $2",
+		columns, table, quotedNames(order))
+
+	if len(r.PK) == 0 {
+		return
+	}
+	r.GetSQL = fmt.Sprintf("SELECT %s FROM %s WHERE %s", columns, table, conditions(r.PK, 1))
+	r.DeleteSQL = fmt.Sprintf("DELETE FROM %s WHERE %s", table, conditions(r.PK, 1))
+	if len(r.Updated) > 0 {
+		assignments := make([]string, len(r.Updated))
+		for i, c := range r.Updated {
+			assignments[i] = fmt.Sprintf("%s = $%d", trenovaorm.QuoteIdentifier(c.Name), i+1)
+		}
+		r.UpdateSQL = fmt.Sprintf("UPDATE %s SET %s WHERE %s",
+			table, strings.Join(assignments, ", "), conditions(r.PK, len(r.Updated)+1))
+	}
+}
+
+// Repository generates a formatted Go source file in package pkg that declares a
+// repository with Insert, GetByPK, Update, Delete and List methods for a
+// compiled table. GetByPK, Update and Delete are only generated for tables with
+// a primary key. The file depends on the helpers generated by DBFile and the
+// struct generated by File.
+func Repository(pkg string, table *trenovaorm.Table) ([]byte, error) {
+	repo, err := newRepository(pkg, table)
+	if err != nil {
+		return nil, err
+	}
+	// Only the primary key parameters name field types in the repository.
+	repo.Imports = imports{"context": ""}
+	for _, c := range repo.PK {
+		repo.Imports.addQualifiers(c.GoType)
+	}
+	return execute(repositoryTemplate, RepositoryFileName(table.Model), repo)
+}
+
+// RepositoryTest generates a test file exercising the repository of a compiled
+// table against the database in $DATABASE_URL. The test is skipped when the
+// variable is unset and runs inside a transaction that is rolled back.
+func RepositoryTest(pkg string, table *trenovaorm.Table) ([]byte, error) {
+	repo, err := newRepository(pkg, table)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the samples determine the imports of the test.
+	repo.Imports = imports{"testing": ""}
+	if !repo.RequiredReference {
+		for _, path := range []string{"context", "database/sql", "os", "github.com/lib/pq"} {
+			repo.Imports.add(path)
+		}
+		repo.Imports["github.com/lib/pq"] = "_"
+		if len(repo.PK) > 0 {
+			repo.Imports.add("errors")
+		}
+		for _, c := range repo.Inserted {
+			repo.Imports.addQualifiers(c.Sample)
+		}
+	}
+	return execute(repositoryTestTemplate, RepositoryTestFileName(table.Model), repo)
+}
+
+// DBFile generates the helpers shared by the repositories of package pkg.
+func DBFile(pkg string) ([]byte, error) {
+	return execute(dbTemplate, DBFileName, struct{ Package string }{pkg})
+}
+
+func execute(tmpl *template.Template, name string, data any) ([]byte, error) {
+	var src bytes.Buffer
+	if err := tmpl.Execute(&src, data); err != nil {
+		return nil, fmt.Errorf("generate %s: %w", name, err)
+	}
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format %s: %w", name, err)
+	}
+	return formatted, nil
+}
+
+// addQualifiers records the imports of the packages referenced by a Go expression.
+func (im imports) addQualifiers(expr string) {
+	for qualifier, path := range packageImports {
+		if strings.Contains(expr, qualifier+".") {
+			im.add(path)
+		}
+	}
+}
+
+func quotedNames(columns []repoColumn) string {
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = trenovaorm.QuoteIdentifier(c.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func placeholders(first, n int) string {
+	params := make([]string, n)
+	for i := range params {
+		params[i] = fmt.Sprintf("$%d", first+i)
+	}
+	return strings.Join(params, ", ")
+}
+
+func conditions(columns []repoColumn, first int) string {
+	conds := make([]string, len(columns))
+	for i, c := range columns {
+		conds[i] = fmt.Sprintf("%s = $%d", trenovaorm.QuoteIdentifier(c.Name), first+i)
+	}
+	return strings.Join(conds, " AND ")
+}
+
+// unexportedName lower-cases the leading word of an exported name, keeping
+// initialisms intact: User becomes user, ID becomes id and APIKey becomes apiKey.
+func unexportedName(name string) string {
+	runes := []rune(name)
+	n := 0
+	for n < len(runes) && unicode.IsUpper(runes[n]) {
+		n++
+	}
+	if n > 1 && n < len(runes) {
+		n-- // the last upper-case letter starts the next word
+	}
+	for i := 0; i < n; i++ {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// paramName returns the parameter name of a primary key column, avoiding the
+// identifiers used by the generated methods.
+func paramName(column string) string {
+	name := unexportedName(ExportedName(column))
+	switch name {
+	case "ctx", "r", "row", "res", "err", "":
+		return name + "Key"
+	}
+	if isKeyword(name) {
+		return name + "Key"
+	}
+	return name
+}
+
+func isKeyword(name string) bool {
+	switch name {
+	case "break", "case", "chan", "const", "continue", "default", "defer", "else",
+		"fallthrough", "for", "func", "go", "goto", "if", "import", "interface",
+		"map", "package", "range", "return", "select", "struct", "switch", "type", "var":
+		return true
+	}
+	return false
+}
+
+// sampleValue returns a Go expression of the given type used as test data, or
+// an empty string when the type has no known sample. Nullable columns are left
+// unset, so pointer types have no sample.
+func sampleValue(goType string) string {
+	switch goType {
+	case "string":
+		return `"x"`
+	case "int", "int16", "int32", "int64", "float64":
+		return "1"
+	case "bool":
+		return "true"
+	case "time.Time":
+		return "time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)"
+	case "trenovaorm.TimeOnly":
+		return "trenovaorm.TimeOnly{Time: time.Date(0, 1, 1, 12, 0, 0, 0, time.UTC)}"
+	case "trenovaorm.Interval":
+		return "trenovaorm.Interval{Duration: time.Hour}"
+	case "uuid.UUID":
+		return "uuid.New()"
+	case "map[string]any":
+		return `map[string]any{"key": "value"}`
+	default:
+		if strings.HasPrefix(goType, "trenovaorm.Array[") {
+			return goType + "{}"
+		}
+		return ""
+	}
+}
+
+var funcs = template.FuncMap{
+	"header": func() string { return Header },
+	// goString renders s as a raw string literal when possible, for readable SQL.
+	"goString": func(s string) string {
+		if strings.Contains(s, "`") {
+			return strconv.Quote(s)
+		}
+		return "`" + s + "`"
+	},
+	"imports": func(im imports) string {
+		var buf bytes.Buffer
+		im.write(&buf)
+		return buf.String()
+	},
+}
+
+var dbTemplate = template.Must(template.New("db").Funcs(funcs).Parse(`{{header}}
+
+package {{.Package}}
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// DBTX is the database handle used by the repositories. It is implemented by
+// *sql.DB, *sql.Conn and *sql.Tx.
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// expectRow returns sql.ErrNoRows when a statement affected no rows.
+func expectRow(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// jsonColumn stores the value ptr points to as JSON.
+type jsonColumn struct {
+	ptr any
+}
+
+// Value implements the driver Valuer interface.
+func (c jsonColumn) Value() (driver.Value, error) {
+	data, err := json.Marshal(c.ptr)
+	if err != nil {
+		return nil, err
+	}
+	if string(data) == "null" {
+		return nil, nil
+	}
+	return string(data), nil
+}
+
+// Scan implements the Scanner interface.
+func (c jsonColumn) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		return json.Unmarshal([]byte("null"), c.ptr)
+	case []byte:
+		return json.Unmarshal(v, c.ptr)
+	case string:
+		return json.Unmarshal([]byte(v), c.ptr)
+	default:
+		return fmt.Errorf("unsupported type %T, expected JSON", value)
+	}
+}
+`))
+
+var repositoryTemplate = template.Must(template.New("repository").Funcs(funcs).Parse(`{{header}}
+
+package {{.Package}}
+
+{{imports .Imports}}
+const (
+	{{.Prefix}}InsertSQL = {{goString .InsertSQL}}
+	{{- if .GetSQL}}
+	{{.Prefix}}GetSQL = {{goString .GetSQL}}
+	{{- end}}
+	{{- if .UpdateSQL}}
+	{{.Prefix}}UpdateSQL = {{goString .UpdateSQL}}
+	{{- end}}
+	{{- if .DeleteSQL}}
+	{{.Prefix}}DeleteSQL = {{goString .DeleteSQL}}
+	{{- end}}
+	{{.Prefix}}ListSQL = {{goString .ListSQL}}
+)
+
+// {{.Struct}}Repository reads and writes rows of the "{{.Table}}" table.
+type {{.Struct}}Repository struct {
+	db DBTX
+}
+
+// New{{.Struct}}Repository returns a {{.Struct}}Repository that runs its queries on db.
+func New{{.Struct}}Repository(db DBTX) *{{.Struct}}Repository {
+	return &{{.Struct}}Repository{db: db}
+}
+
+{{if .Returning -}}
+// Insert inserts row and stores the values generated by the database in it.
+func (r *{{.Struct}}Repository) Insert(ctx context.Context, row *{{.Struct}}) error {
+	return r.db.QueryRowContext(ctx, {{.Prefix}}InsertSQL{{range .Inserted}}, {{.Arg}}{{end}}).
+		Scan({{range $i, $c := .Returning}}{{if $i}}, {{end}}{{$c.Dest}}{{end}})
+}
+{{- else -}}
+// Insert inserts row.
+func (r *{{.Struct}}Repository) Insert(ctx context.Context, row *{{.Struct}}) error {
+	_, err := r.db.ExecContext(ctx, {{.Prefix}}InsertSQL{{range .Inserted}}, {{.Arg}}{{end}})
+	return err
+}
+{{- end}}
+{{- if .PK}}
+
+// GetByPK returns the row with the given primary key, or sql.ErrNoRows if there is none.
+func (r *{{.Struct}}Repository) GetByPK(ctx context.Context{{range .PK}}, {{.Param}} {{.GoType}}{{end}}) (*{{.Struct}}, error) {
+	row := &{{.Struct}}{}
+	err := r.db.QueryRowContext(ctx, {{.Prefix}}GetSQL{{range .PK}}, {{.Param}}{{end}}).
+		Scan({{range $i, $c := .Columns}}{{if $i}}, {{end}}{{$c.Dest}}{{end}})
+	if err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+{{- if .Updated}}
+
+// Update writes the non-key columns of row, or returns sql.ErrNoRows if the row does not exist.
+func (r *{{.Struct}}Repository) Update(ctx context.Context, row *{{.Struct}}) error {
+	res, err := r.db.ExecContext(ctx, {{.Prefix}}UpdateSQL{{range .Updated}}, {{.Arg}}{{end}}{{range .PK}}, {{.Arg}}{{end}})
+	if err != nil {
+		return err
+	}
+	return expectRow(res)
+}
+{{- end}}
+
+// Delete deletes the row with the given primary key, or returns sql.ErrNoRows if there is none.
+func (r *{{.Struct}}Repository) Delete(ctx context.Context{{range .PK}}, {{.Param}} {{.GoType}}{{end}}) error {
+	res, err := r.db.ExecContext(ctx, {{.Prefix}}DeleteSQL{{range .PK}}, {{.Param}}{{end}})
+	if err != nil {
+		return err
+	}
+	return expectRow(res)
+}
+{{- end}}
+
+// List returns at most limit rows, ordered by {{if .PK}}primary key{{else}}"{{(index .Columns 0).Name}}"{{end}}, after skipping offset rows.
+func (r *{{.Struct}}Repository) List(ctx context.Context, limit, offset int) ([]*{{.Struct}}, error) {
+	rows, err := r.db.QueryContext(ctx, {{.Prefix}}ListSQL, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*{{.Struct}}
+	for rows.Next() {
+		row := &{{.Struct}}{}
+		if err := rows.Scan({{range $i, $c := .Columns}}{{if $i}}, {{end}}{{$c.Dest}}{{end}}); err != nil {
+			return nil, err
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+`))
+
+var repositoryTestTemplate = template.Must(template.New("repository_test").Funcs(funcs).Parse(`{{header}}
+
+package {{.Package}}
+
+{{imports .Imports}}
+{{- if .RequiredReference}}
+func Test{{.Struct}}Repository(t *testing.T) {
+	t.Skip({{printf "%q" (print .Table " has required foreign keys; seed the referenced rows to test it")}})
+}
+{{- else}}
+// Test{{.Struct}}Repository runs the repository against the database in
+// $DATABASE_URL, inside a transaction that is rolled back. The "{{.Table}}"
+// table must already exist.
+func Test{{.Struct}}Repository(t *testing.T) {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		t.Skip("DATABASE_URL is not set")
+	}
+
+	ctx := context.Background()
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	repo := New{{.Struct}}Repository(tx)
+	row := &{{.Struct}}{
+		{{- range .Inserted}}{{if .Sample}}
+		{{.Field}}: {{.Sample}},
+		{{- end}}{{end}}
+	}
+	if err := repo.Insert(ctx, row); err != nil {
+		t.Fatalf("Insert() error = %v", err)
+	}
+{{- if .PK}}
+
+	got, err := repo.GetByPK(ctx{{range .PK}}, row.{{.Field}}{{end}})
+	if err != nil {
+		t.Fatalf("GetByPK() error = %v", err)
+	}
+	{{- range .PK}}
+	if got.{{.Field}} != row.{{.Field}} {
+		t.Errorf("GetByPK().{{.Field}} = %v, want %v", got.{{.Field}}, row.{{.Field}})
+	}
+	{{- end}}
+{{- if .Updated}}
+
+	if err := repo.Update(ctx, got); err != nil {
+		t.Errorf("Update() error = %v", err)
+	}
+{{- end}}
+{{- end}}
+
+	rows, err := repo.List(ctx, 1, 0)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(rows) != 1 {
+		t.Errorf("List() returned %d rows, want 1", len(rows))
+	}
+{{- if .PK}}
+
+	if err := repo.Delete(ctx{{range .PK}}, row.{{.Field}}{{end}}); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := repo.GetByPK(ctx{{range .PK}}, row.{{.Field}}{{end}}); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetByPK() after Delete() error = %v, want sql.ErrNoRows", err)
+	}
+{{- end}}
+}
+{{- end}}
+`))
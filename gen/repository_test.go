@@ -0,0 +1,224 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+
+	trenovaorm "github.com/emoss08/trenova-orm"
+)
+
+func TestRepository(t *testing.T) {
+	got, err := Repository("models", mustCompile(t, &User{}, &Role{}).Table("users"))
+	if err != nil {
+		t.Fatalf("Repository() error = %v", err)
+	}
+
+	src := string(got)
+	for _, want := range []string{
+		Header,
+		`"github.com/google/uuid"`,
+		"userInsertSQL = `INSERT INTO \"users\" (\"id\", \"email\", \"bio\", \"start_time\", \"metadata\", \"role_id\") VALUES ($1, $2, $3, $4, $5, $6) RETURNING \"created_at\", \"updated_at\"`",
+		"userGetSQL    = `SELECT \"id\", \"email\", \"bio\", \"start_time\", \"metadata\", \"role_id\", \"created_at\", \"updated_at\" FROM \"users\" WHERE \"id\" = $1`",
+		"userUpdateSQL = `UPDATE \"users\" SET \"email\" = $1, \"bio\" = $2, \"start_time\" = $3, \"metadata\" = $4, \"role_id\" = $5, \"created_at\" = $6, \"updated_at\" = $7 WHERE \"id\" = $8`",
+		"userDeleteSQL = `DELETE FROM \"users\" WHERE \"id\" = $1`",
+		"userListSQL   = `SELECT \"id\", \"email\", \"bio\", \"start_time\", \"metadata\", \"role_id\", \"created_at\", \"updated_at\" FROM \"users\" ORDER BY \"id\" LIMIT $1 OFFSET $2`",
+		"func NewUserRepository(db DBTX) *UserRepository {",
+		"row.ID, row.Email, row.Bio, row.StartTime, jsonColumn{&row.Metadata}, row.RoleID).\n\t\tScan(&row.CreatedAt, &row.UpdatedAt)",
+		"func (r *UserRepository) GetByPK(ctx context.Context, id uuid.UUID) (*User, error) {",
+		"func (r *UserRepository) Update(ctx context.Context, row *User) error {",
+		"func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {",
+		"func (r *UserRepository) List(ctx context.Context, limit, offset int) ([]*User, error) {",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("Repository() output does not contain %q:\n%s", want, src)
+		}
+	}
+}
+
+func TestRepository_WithoutPrimaryKey(t *testing.T) {
+	model := &testModel{fields: []trenovaorm.Field{
+		&trenovaorm.TextField{ColumnName: "message"},
+	}}
+	got, err := Repository("models", mustCompile(t, model).Table("user_roles"))
+	if err != nil {
+		t.Fatalf("Repository() error = %v", err)
+	}
+
+	src := string(got)
+	if !strings.Contains(src, "userRolesInsertSQL = `INSERT INTO \"user_roles\" (\"message\") VALUES ($1)`") {
+		t.Errorf("Repository() output is missing the INSERT statement:\n%s", src)
+	}
+	if !strings.Contains(src, `ORDER BY "message" LIMIT $1 OFFSET $2`) {
+		t.Errorf("Repository() output does not order List by the first column:\n%s", src)
+	}
+	for _, method := range []string{"GetByPK", "Update", "Delete"} {
+		if strings.Contains(src, method) {
+			t.Errorf("Repository() output declares %s for a table without a primary key", method)
+		}
+	}
+}
+
+func TestRepository_Generated(t *testing.T) {
+	model := &testModel{fields: []trenovaorm.Field{
+		&trenovaorm.UUIDField{ColumnName: "id", PrimaryKey: true},
+		&trenovaorm.TextField{ColumnName: "first_name"},
+		&trenovaorm.GeneratedField{
+			Field:      &trenovaorm.TextField{ColumnName: "full_name", StructTag: `json:"fullName"`},
+			Expression: trenovaorm.Raw{SQL: `"first_name" || '!'`},
+		},
+	}}
+	got, err := Repository("models", mustCompile(t, model).Table("user_roles"))
+	if err != nil {
+		t.Fatalf("Repository() error = %v", err)
+	}
+
+	src := string(got)
+	for _, want := range []string{
+		"userRolesInsertSQL = `INSERT INTO \"user_roles\" (\"id\", \"first_name\") VALUES ($1, $2) RETURNING \"full_name\"`",
+		"userRolesUpdateSQL = `UPDATE \"user_roles\" SET \"first_name\" = $1 WHERE \"id\" = $2`",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("Repository() output does not contain %q:\n%s", want, src)
+		}
+	}
+
+	file, err := File("models", mustCompile(t, model).Table("user_roles"))
+	if err != nil {
+		t.Fatalf("File() error = %v", err)
+	}
+	if want := "FullName  string    `json:\"fullName\" db:\"full_name\"`"; !strings.Contains(string(file), want) {
+		t.Errorf("File() output does not contain %q:\n%s", want, file)
+	}
+}
+
+func TestRepositoryTest(t *testing.T) {
+	model := &testModel{fields: []trenovaorm.Field{
+		&trenovaorm.UUIDField{ColumnName: "id", PrimaryKey: true, Default: trenovaorm.UUIDGenerateV4},
+		&trenovaorm.CharField{ColumnName: "name", MaxLength: 100},
+		&trenovaorm.DateField{ColumnName: "expires_at", Nullable: true},
+	}}
+	got, err := RepositoryTest("models", mustCompile(t, model).Table("user_roles"))
+	if err != nil {
+		t.Fatalf("RepositoryTest() error = %v", err)
+	}
+
+	src := string(got)
+	for _, want := range []string{
+		`_ "github.com/lib/pq"`,
+		`os.Getenv("DATABASE_URL")`,
+		"repo := NewUserRolesRepository(tx)",
+		"Name: \"x\",\n\t}",
+		"repo.GetByPK(ctx, row.ID)",
+		"repo.Delete(ctx, row.ID)",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("RepositoryTest() output does not contain %q:\n%s", want, src)
+		}
+	}
+	if strings.Contains(src, `"time"`) {
+		t.Error("RepositoryTest() imports time without using it")
+	}
+}
+
+func TestRepositoryTest_RequiredReference(t *testing.T) {
+	got, err := RepositoryTest("models", mustCompile(t, &User{}, &Role{}).Table("users"))
+	if err != nil {
+		t.Fatalf("RepositoryTest() error = %v", err)
+	}
+	if !strings.Contains(string(got), `t.Skip("users has required foreign keys`) {
+		t.Errorf("RepositoryTest() does not skip a model with required foreign keys:\n%s", got)
+	}
+}
+
+func TestRepositoryTest_CompositeReference(t *testing.T) {
+	tests := []struct {
+		name     string
+		nullable bool
+		want     bool
+	}{
+		{"Required", false, true},
+		{"Nullable column", true, false},
+	}
+
+	users := &testModel{
+		name: "users",
+		fields: []trenovaorm.Field{
+			&trenovaorm.UUIDField{ColumnName: "id", PrimaryKey: true},
+			&trenovaorm.UUIDField{ColumnName: "organization_id"},
+		},
+		constraints: []trenovaorm.TableConstraint{trenovaorm.Unique{Columns: []string{"id", "organization_id"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := &testModel{
+				fields: []trenovaorm.Field{
+					&trenovaorm.UUIDField{ColumnName: "id", PrimaryKey: true},
+					&trenovaorm.UUIDField{ColumnName: "user_id"},
+					&trenovaorm.UUIDField{ColumnName: "organization_id", Nullable: tt.nullable},
+				},
+				constraints: []trenovaorm.TableConstraint{trenovaorm.ForeignKey{
+					Columns:          []string{"user_id", "organization_id"},
+					ReferenceTable:   "users",
+					ReferenceColumns: []string{"id", "organization_id"},
+				}},
+			}
+			got, err := RepositoryTest("models", mustCompile(t, model, users).Table("user_roles"))
+			if err != nil {
+				t.Fatalf("RepositoryTest() error = %v", err)
+			}
+			if skipped := strings.Contains(string(got), "has required foreign keys"); skipped != tt.want {
+				t.Errorf("RepositoryTest() skipped = %v, want %v:\n%s", skipped, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRepository_IdentityAlways(t *testing.T) {
+	model := &testModel{fields: []trenovaorm.Field{
+		&trenovaorm.UUIDField{ColumnName: "id", PrimaryKey: true},
+		&trenovaorm.BigIntegerField{ColumnName: "number", Identity: trenovaorm.Identity{Generation: trenovaorm.IdentityAlways}},
+		&trenovaorm.BigIntegerField{ColumnName: "position", Identity: trenovaorm.Identity{Generation: trenovaorm.IdentityByDefault}},
+		&trenovaorm.TextField{ColumnName: "name"},
+	}}
+	got, err := Repository("models", mustCompile(t, model).Table("user_roles"))
+	if err != nil {
+		t.Fatalf("Repository() error = %v", err)
+	}
+	want := "userRolesUpdateSQL = `UPDATE \"user_roles\" SET \"position\" = $1, \"name\" = $2 WHERE \"id\" = $3`"
+	if !strings.Contains(string(got), want) {
+		t.Errorf("Repository() output does not contain %q:\n%s", want, got)
+	}
+}
+
+func TestDBFile(t *testing.T) {
+	got, err := DBFile("models")
+	if err != nil {
+		t.Fatalf("DBFile() error = %v", err)
+	}
+	for _, want := range []string{"package models", "type DBTX interface {", "func expectRow(", "type jsonColumn struct {"} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("DBFile() output does not contain %q", want)
+		}
+	}
+}
+
+func TestUnexportedName(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"User", "user"},
+		{"ID", "id"},
+		{"APIKey", "apiKey"},
+		{"UserRoles", "userRoles"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := unexportedName(tt.in); got != tt.want {
+				t.Errorf("unexportedName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
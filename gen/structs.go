@@ -0,0 +1,326 @@
+// Package gen generates Go source code from trenovaorm models.
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"reflect"
+	"sort"
+	"strings"
+
+	trenovaorm "github.com/emoss08/trenova-orm"
+)
+
+// Header marks generated files so tools and reviewers skip them.
+const Header = "// Code generated by trenova-orm. DO NOT EDIT."
+
+const ormImportPath = "github.com/emoss08/trenova-orm"
+
+// packageImports maps the package qualifiers used by field Go types to their import paths.
+var packageImports = map[string]string{
+	"time":       "time",
+	"uuid":       "github.com/google/uuid",
+	"json":       "encoding/json",
+	"trenovaorm": ormImportPath,
+}
+
+// commonInitialisms are rendered in upper case in exported names, as golint expects.
+var commonInitialisms = map[string]bool{
+	"API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true,
+	"HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true,
+	"SQL": true, "SSH": true, "TLS": true, "TTL": true, "UI": true, "URI": true,
+	"URL": true, "UTF8": true, "UUID": true, "XML": true,
+}
+
+// FileName returns the name of the generated file for a model, prefixed with
+// its schema unless that is the default one, e.g. billing_invoices.go.
+func FileName(model trenovaorm.Model) string {
+	return baseName(model) + ".go"
+}
+
+// baseName returns the table name of a model, prefixed with its schema unless
+// that is DefaultSchemaName, so that same-named tables of different schemas
+// generate distinct files and types.
+func baseName(model trenovaorm.Model) string {
+	return schemaPrefixed(trenovaorm.ModelSchemaName(model), model.TableName())
+}
+
+// schemaPrefixed returns name prefixed with schema and an underscore, or name
+// alone if schema is empty or DefaultSchemaName.
+func schemaPrefixed(schema, name string) string {
+	if schema == "" || schema == trenovaorm.DefaultSchemaName {
+		return name
+	}
+	return schema + "_" + name
+}
+
+// StructName returns the exported Go name of the struct generated for a model:
+// the model's own type name, or the CamelCase table name, prefixed with a
+// non-default schema, for unnamed types.
+func StructName(model trenovaorm.Model) string {
+	typ := reflect.TypeOf(model)
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if name := typ.Name(); name != "" && ast.IsExported(name) {
+		return name
+	}
+	return ExportedName(baseName(model))
+}
+
+// ExportedName converts a snake_case column or table name into an exported Go
+// identifier, upper-casing common initialisms such as ID and URL.
+func ExportedName(s string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(s, "_") {
+		if part == "" {
+			continue
+		}
+		if upper := strings.ToUpper(part); commonInitialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// File generates a formatted Go source file in package pkg that declares the
+// struct for a compiled table, including its mixin fields. The fields are those
+// of the compiled table, so foreign keys have the Go type of the column they
+// reference. The output depends only on the model definitions, so regenerating
+// an unchanged model is byte-for-byte stable.
+func File(pkg string, table *trenovaorm.Table) ([]byte, error) {
+	return structFile(pkg, table.Model, table.Fields, false)
+}
+
+// ViewFile generates a formatted Go source file in package pkg that declares
+// the row type of a compiled view.
+func ViewFile(pkg string, view *trenovaorm.CompiledView) ([]byte, error) {
+	return structFile(pkg, view.Model, view.Fields, true)
+}
+
+// structFile generates the file declaring the struct of a model with the
+// given fields, documented as the row type of a view if view is set.
+func structFile(pkg string, model trenovaorm.Model, fields []trenovaorm.Field, view bool) ([]byte, error) {
+	imports := make(imports)
+
+	var body bytes.Buffer
+	name := StructName(model)
+	if view {
+		fmt.Fprintf(&body, "// %s is the row type of the %q view. Views are read-only.\n", name, trenovaorm.QualifiedTableName(model))
+	} else {
+		fmt.Fprintf(&body, "// %s is the row type of the %q table.\n", name, trenovaorm.QualifiedTableName(model))
+	}
+	fmt.Fprintf(&body, "type %s struct {\n", name)
+	for _, field := range fields {
+		goType, err := fieldType(field, imports)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name(), err)
+		}
+		if comment := trenovaorm.ColumnOf(field).Comment; comment != "" {
+			writeComment(&body, comment)
+		}
+		fmt.Fprintf(&body, "\t%s %s `%s`\n", ExportedName(field.Name()), goType, structTag(field))
+	}
+	body.WriteString("}\n")
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "%s\n\npackage %s\n\n", Header, pkg)
+	imports.write(&src)
+	src.Write(body.Bytes())
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format %s: %w", FileName(model), err)
+	}
+	return formatted, nil
+}
+
+// writeComment writes a column comment above its struct field, one line
+// comment per line of the comment.
+func writeComment(w *bytes.Buffer, comment string) {
+	for _, line := range strings.Split(strings.TrimSpace(comment), "\n") {
+		w.WriteString(strings.TrimRight("\t// "+strings.TrimRight(line, "\r"), " \t") + "\n")
+	}
+}
+
+// imports collects the import paths of a generated file, mapped to their
+// package names; an empty name imports the package under its default name.
+type imports map[string]string
+
+// add records an import of path under its default name, or under trenovaorm for this package.
+func (im imports) add(path string) {
+	if path == ormImportPath {
+		im[path] = "trenovaorm"
+		return
+	}
+	if _, ok := im[path]; !ok {
+		im[path] = ""
+	}
+}
+
+// write writes a sorted import block, standard library first.
+func (im imports) write(w *bytes.Buffer) {
+	if len(im) == 0 {
+		return
+	}
+
+	var std, external []string
+	for path := range im {
+		if strings.Contains(path, ".") {
+			external = append(external, path)
+		} else {
+			std = append(std, path)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(external)
+
+	w.WriteString("import (\n")
+	for _, path := range std {
+		fmt.Fprintf(w, "\t%s%q\n", im.alias(path), path)
+	}
+	if len(std) > 0 && len(external) > 0 {
+		w.WriteString("\n")
+	}
+	for _, path := range external {
+		fmt.Fprintf(w, "\t%s%q\n", im.alias(path), path)
+	}
+	w.WriteString(")\n\n")
+}
+
+func (im imports) alias(path string) string {
+	if name := im[path]; name != "" {
+		return name + " "
+	}
+	return ""
+}
+
+// fieldType returns the Go type of a field's struct field. Enum columns and the
+// elements of enum arrays use the named type generated by EnumFile; other types
+// are qualified by qualifyType. The columns of views take the type of the
+// column they read, as a pointer if the view may yield NULL.
+func fieldType(field trenovaorm.Field, imports imports) (string, error) {
+	switch f := baseField(field).(type) {
+	case *trenovaorm.ViewField:
+		goType, err := fieldType(f.Source, imports)
+		if err == nil && f.Nullable && !strings.HasPrefix(goType, "*") {
+			goType = "*" + goType
+		}
+		return goType, err
+	case *trenovaorm.EnumField:
+		name := EnumTypeName(f.Enum())
+		if f.Nullable {
+			return "*" + name, nil
+		}
+		return name, nil
+	case *trenovaorm.ArrayField:
+		if enum, ok := f.Enum(); ok {
+			imports.add(ormImportPath)
+			return fmt.Sprintf("trenovaorm.Array[%s]", EnumTypeName(enum)), nil
+		}
+	}
+	return qualifyType(field.GoType(), imports)
+}
+
+// baseField returns the field computed by a GeneratedField, or field itself.
+func baseField(field trenovaorm.Field) trenovaorm.Field {
+	if g, ok := field.(*trenovaorm.GeneratedField); ok && g.Field != nil {
+		return g.Field
+	}
+	return field
+}
+
+// qualifyType rewrites a field's Go type for use outside the trenovaorm package,
+// qualifying trenovaorm types such as TimeOnly, and records the imports it needs.
+func qualifyType(goType string, imports imports) (string, error) {
+	if goType == "" {
+		return "", fmt.Errorf("no Go type")
+	}
+	expr, err := parser.ParseExpr(goType)
+	if err != nil {
+		return "", fmt.Errorf("invalid Go type %q: %w", goType, err)
+	}
+
+	var qualifyErr error
+	expr = rewrite(expr, func(e ast.Expr) ast.Expr {
+		switch e := e.(type) {
+		case *ast.SelectorExpr:
+			pkg, ok := e.X.(*ast.Ident)
+			if !ok {
+				return e
+			}
+			path, known := packageImports[pkg.Name]
+			if !known {
+				qualifyErr = fmt.Errorf("unknown package %q in Go type %q", pkg.Name, goType)
+				return e
+			}
+			imports.add(path)
+		case *ast.Ident:
+			if ast.IsExported(e.Name) {
+				imports.add(ormImportPath)
+				return &ast.SelectorExpr{X: ast.NewIdent("trenovaorm"), Sel: e}
+			}
+		}
+		return e
+	})
+	if qualifyErr != nil {
+		return "", qualifyErr
+	}
+
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, token.NewFileSet(), expr); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// rewrite applies fn to the type names within a type expression, leaving the
+// selector parts of qualified names untouched.
+func rewrite(expr ast.Expr, fn func(ast.Expr) ast.Expr) ast.Expr {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		e.X = rewrite(e.X, fn)
+	case *ast.ArrayType:
+		e.Elt = rewrite(e.Elt, fn)
+	case *ast.MapType:
+		e.Key = rewrite(e.Key, fn)
+		e.Value = rewrite(e.Value, fn)
+	case *ast.IndexExpr:
+		e.X = rewrite(e.X, fn)
+		e.Index = rewrite(e.Index, fn)
+	case *ast.SelectorExpr, *ast.Ident:
+		return fn(e)
+	}
+	return expr
+}
+
+// structTag merges the field's StructTag with a db tag naming its column. A db
+// key already present in the StructTag wins.
+func structTag(field trenovaorm.Field) string {
+	var tag string
+	v := reflect.ValueOf(baseField(field))
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Struct {
+		if f := v.FieldByName("StructTag"); f.IsValid() && f.Kind() == reflect.String {
+			tag = strings.TrimSpace(f.String())
+		}
+	}
+
+	if _, ok := reflect.StructTag(tag).Lookup("db"); ok {
+		return tag
+	}
+	dbTag := fmt.Sprintf("db:%q", field.Name())
+	if tag == "" {
+		return dbTag
+	}
+	return tag + " " + dbTag
+}
@@ -0,0 +1,339 @@
+package gen
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	trenovaorm "github.com/emoss08/trenova-orm"
+)
+
+type User struct {
+	trenovaorm.BaseModel
+}
+
+func (User) TableName() string {
+	return "users"
+}
+
+func (User) Fields() []trenovaorm.Field {
+	return []trenovaorm.Field{
+		&trenovaorm.UUIDField{
+			ColumnName: "id",
+			PrimaryKey: true,
+			Comment:    "Unique identifier of the user",
+			StructTag:  `json:"id" validate:"required"`,
+		},
+		&trenovaorm.CharField{ColumnName: "email", MaxLength: 255, StructTag: `json:"email"`},
+		&trenovaorm.TextField{ColumnName: "bio", Nullable: true, StructTag: `json:"bio" db:"biography"`},
+		&trenovaorm.TimeField{ColumnName: "start_time", Nullable: true},
+		&trenovaorm.JSONField{ColumnName: "metadata"},
+		&trenovaorm.ForeignKeyField{ColumnName: "role_id", ReferenceTable: "roles", ReferenceField: "id", ReferencedType: "uuid.UUID"},
+	}
+}
+
+func (User) Mixins() []trenovaorm.Mixin {
+	return []trenovaorm.Mixin{trenovaorm.TimestampedMixin{}}
+}
+
+// Role is the table referenced by the role_id foreign key of User.
+type Role struct {
+	trenovaorm.BaseModel
+}
+
+func (Role) TableName() string {
+	return "roles"
+}
+
+func (Role) Fields() []trenovaorm.Field {
+	return []trenovaorm.Field{&trenovaorm.UUIDField{ColumnName: "id", PrimaryKey: true}}
+}
+
+// mustCompile compiles the models, which the generators take compiled.
+func mustCompile(t *testing.T, models ...trenovaorm.Model) *trenovaorm.Schema {
+	t.Helper()
+	schema, err := trenovaorm.Compile(models...)
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	return schema
+}
+
+func TestFile(t *testing.T) {
+	table := mustCompile(t, &User{}, &Role{}).Table("users")
+	got, err := File("models", table)
+	if err != nil {
+		t.Fatalf("File() error = %v", err)
+	}
+
+	want := `// Code generated by trenova-orm. DO NOT EDIT.
+
+package models
+
+import (
+	"time"
+
+	trenovaorm "github.com/emoss08/trenova-orm"
+	"github.com/google/uuid"
+)
+
+// User is the row type of the "users" table.
+type User struct {
+	// Unique identifier of the user
+	ID        uuid.UUID            ` + "`json:\"id\" validate:\"required\" db:\"id\"`" + `
+	Email     string               ` + "`json:\"email\" db:\"email\"`" + `
+	Bio       *string              ` + "`json:\"bio\" db:\"biography\"`" + `
+	StartTime *trenovaorm.TimeOnly ` + "`db:\"start_time\"`" + `
+	Metadata  map[string]any       ` + "`db:\"metadata\"`" + `
+	RoleID    uuid.UUID            ` + "`db:\"role_id\"`" + `
+	// Creation timestamp
+	CreatedAt time.Time ` + "`json:\"created_at\" validate:\"required\" db:\"created_at\"`" + `
+	// Update timestamp
+	UpdatedAt time.Time ` + "`json:\"updated_at\" validate:\"required\" db:\"updated_at\"`" + `
+}
+`
+	if string(got) != want {
+		t.Errorf("File() =\n%s\nwant\n%s", got, want)
+	}
+
+	again, err := File("models", mustCompile(t, &User{}, &Role{}).Table("users"))
+	if err != nil {
+		t.Fatalf("File() error = %v", err)
+	}
+	if string(again) != string(got) {
+		t.Error("File() output is not reproducible")
+	}
+}
+
+// UserRoleIDs is a view of the foreign keys of the user_roles table.
+type UserRoleIDs struct {
+	trenovaorm.BaseModel
+}
+
+func (UserRoleIDs) TableName() string {
+	return "user_role_ids"
+}
+
+func (UserRoleIDs) View() trenovaorm.View {
+	return trenovaorm.View{Query: trenovaorm.Select(&testModel{fields: userRoleFields()}, "role_id", "granted_by")}
+}
+
+// userRoleFields are foreign keys that leave their Go types to be resolved
+// from the columns they reference.
+func userRoleFields() []trenovaorm.Field {
+	return []trenovaorm.Field{
+		&trenovaorm.ForeignKeyField{ColumnName: "role_id", ReferenceTable: "roles", ReferenceField: "id"},
+		&trenovaorm.ForeignKeyField{ColumnName: "granted_by", ReferenceTable: "users", ReferenceField: "id", Nullable: true},
+	}
+}
+
+func TestFile_ForeignKeyType(t *testing.T) {
+	schema := mustCompile(t, &User{}, &Role{}, &testModel{fields: userRoleFields()}, &UserRoleIDs{})
+
+	got, err := File("models", schema.Table("user_roles"))
+	if err != nil {
+		t.Fatalf("File() error = %v", err)
+	}
+	for _, want := range []string{"\tRoleID    uuid.UUID  `db:\"role_id\"`\n", "\tGrantedBy *uuid.UUID `db:\"granted_by\"`\n"} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("File() =\n%s\nwant %q", got, want)
+		}
+	}
+
+	view, err := ViewFile("models", schema.View("user_role_ids"))
+	if err != nil {
+		t.Fatalf("ViewFile() error = %v", err)
+	}
+	if want := "\tGrantedBy *uuid.UUID `db:\"granted_by\"`\n"; !strings.Contains(string(view), want) {
+		t.Errorf("ViewFile() =\n%s\nwant %q", view, want)
+	}
+
+	repo, err := Repository("models", schema.Table("user_roles"))
+	if err != nil {
+		t.Fatalf("Repository() error = %v", err)
+	}
+	if want := "row.RoleID, row.GrantedBy"; !strings.Contains(string(repo), want) {
+		t.Errorf("Repository() output does not contain %q:\n%s", want, repo)
+	}
+}
+
+func TestFile_MultilineComment(t *testing.T) {
+	model := &testModel{fields: []trenovaorm.Field{
+		&trenovaorm.TextField{ColumnName: "notes", Comment: "Free-form notes.\n\nShown to dispatchers only."},
+	}}
+	got, err := File("models", mustCompile(t, model).Table("user_roles"))
+	if err != nil {
+		t.Fatalf("File() error = %v", err)
+	}
+	want := "\t// Free-form notes.\n\t//\n\t// Shown to dispatchers only.\n\tNotes string `db:\"notes\"`\n"
+	if !strings.Contains(string(got), want) {
+		t.Errorf("File() =\n%s\nwant %q", got, want)
+	}
+}
+
+func TestFile_Array(t *testing.T) {
+	model := &testModel{fields: []trenovaorm.Field{
+		&trenovaorm.ArrayField{ColumnName: "owners", Element: &trenovaorm.UUIDField{}},
+		&trenovaorm.ArrayField{ColumnName: "statuses", Element: &trenovaorm.EnumField{TypeName: "role_status", Values: []string{"active"}}},
+	}}
+	got, err := File("models", mustCompile(t, model).Table("user_roles"))
+	if err != nil {
+		t.Fatalf("File() error = %v", err)
+	}
+	for _, want := range []string{
+		"\tOwners   trenovaorm.Array[uuid.UUID]  `db:\"owners\"`\n",
+		"\tStatuses trenovaorm.Array[RoleStatus] `db:\"statuses\"`\n",
+		"\"github.com/google/uuid\"",
+	} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("File() =\n%s\nwant %q", got, want)
+		}
+	}
+}
+
+type UserActivity struct {
+	trenovaorm.BaseModel
+}
+
+func (UserActivity) TableName() string {
+	return "user_activity"
+}
+
+func (UserActivity) View() trenovaorm.View {
+	return trenovaorm.View{
+		Query:   trenovaorm.Select(&User{}, "id", "bio", "max(updated_at)").GroupBy("id", "bio"),
+		Columns: []string{"id", "bio", "last_seen_at"},
+	}
+}
+
+func TestFile_View(t *testing.T) {
+	got, err := ViewFile("models", mustCompile(t, &User{}, &Role{}, &UserActivity{}).View("user_activity"))
+	if err != nil {
+		t.Fatalf("File() error = %v", err)
+	}
+	for _, want := range []string{
+		"// UserActivity is the row type of the \"user_activity\" view. Views are read-only.\n",
+		"\t// Unique identifier of the user\n\tID         uuid.UUID  `db:\"id\"`\n",
+		"\tBio        *string    `db:\"bio\"`\n",
+		"\tLastSeenAt *time.Time `db:\"last_seen_at\"`\n",
+	} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("File() =\n%s\nwant %q", got, want)
+		}
+	}
+}
+
+type testModel struct {
+	trenovaorm.BaseModel
+	name        string // Table name, defaults to user_roles
+	fields      []trenovaorm.Field
+	constraints []trenovaorm.TableConstraint
+}
+
+func (m *testModel) TableName() string {
+	if m.name != "" {
+		return m.name
+	}
+	return "user_roles"
+}
+
+func (m *testModel) Fields() []trenovaorm.Field {
+	return m.fields
+}
+
+func (m *testModel) Constraints() []trenovaorm.TableConstraint {
+	return m.constraints
+}
+
+func TestStructName(t *testing.T) {
+	if got := StructName(&User{}); got != "User" {
+		t.Errorf("StructName() = %v, want User", got)
+	}
+	if got := StructName(&testModel{}); got != "UserRoles" {
+		t.Errorf("StructName() = %v, want UserRoles", got)
+	}
+}
+
+// schemaModel is a testModel whose table lives in a PostgreSQL schema.
+type schemaModel struct {
+	testModel
+	schema string
+}
+
+func (m *schemaModel) SchemaName() string {
+	return m.schema
+}
+
+func TestNames_Schemas(t *testing.T) {
+	fields := func() []trenovaorm.Field {
+		return []trenovaorm.Field{
+			&trenovaorm.UUIDField{ColumnName: "id", PrimaryKey: true},
+			&trenovaorm.EnumField{ColumnName: "status", TypeName: "payment_status", Values: []string{"paid"}},
+		}
+	}
+	public := &testModel{name: "invoices", fields: fields()}
+	billing := &schemaModel{testModel: testModel{name: "invoices", fields: fields()}, schema: "billing"}
+	schema := mustCompile(t, public, billing)
+
+	names := func(model trenovaorm.Model, enum trenovaorm.EnumType) []string {
+		return []string{
+			FileName(model), StructName(model), RepositoryFileName(model), RepositoryTestFileName(model),
+			EnumFileName(enum), EnumTypeName(enum),
+		}
+	}
+	tests := []struct {
+		table string
+		want  []string
+	}{
+		{"invoices", []string{
+			"invoices.go", "Invoices", "invoices_repository.go", "invoices_repository_test.go",
+			"payment_status_enum.go", "PaymentStatus",
+		}},
+		{"billing.invoices", []string{
+			"billing_invoices.go", "BillingInvoices", "billing_invoices_repository.go", "billing_invoices_repository_test.go",
+			"billing_payment_status_enum.go", "BillingPaymentStatus",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.table, func(t *testing.T) {
+			table := schema.Table(tt.table)
+			enum := table.Fields[1].(*trenovaorm.EnumField).Enum()
+			if got := names(table.Model, enum); !slices.Equal(got, tt.want) {
+				t.Errorf("names = %v, want %v", got, tt.want)
+			}
+
+			src, err := File("models", table)
+			if err != nil {
+				t.Fatalf("File() error = %v", err)
+			}
+			want := fmt.Sprintf("\tStatus %s `db:\"status\"`\n", tt.want[5])
+			if !strings.Contains(string(src), want) {
+				t.Errorf("File() =\n%s\nwant a field %q", src, want)
+			}
+		})
+	}
+}
+
+func TestExportedName(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"id", "ID"},
+		{"user_id", "UserID"},
+		{"avatar_url", "AvatarURL"},
+		{"first_name", "FirstName"},
+		{"json_data", "JSONData"},
+		{"_leading", "Leading"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := ExportedName(tt.in); got != tt.want {
+				t.Errorf("ExportedName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package trenovaorm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// GeneratedField makes the column of Field a stored generated column, computed
+// by PostgreSQL from the other columns of the row:
+//
+//	&GeneratedField{
+//		Field:      &TextField{ColumnName: "full_name"},
+//		Expression: Binary{
+//			Left:     Binary{Left: Ref{Column: "first_name"}, Operator: "||", Right: Literal{Value: " "}},
+//			Operator: "||",
+//			Right:    Ref{Column: "last_name"},
+//		},
+//	}
+//
+// The column takes its name, type, nullability, uniqueness, comment, index and
+// struct tag from Field. The columns the expression references must exist and
+// must not be generated themselves. Field must not have a default, except for
+// the implicit FALSE of a BooleanField, which is not rendered, and its raw
+// Constraints are not rendered. Generated columns cannot be written, so they
+// are rejected by InsertQuery and UpdateQuery and left out of the insert and
+// update statements of generated repositories.
+type GeneratedField struct {
+	Field      Field
+	Expression Expression // Must be immutable, e.g. to_tsvector with an explicit config
+}
+
+// Definition generates the SQL definition for the GeneratedField.
+func (f *GeneratedField) Definition() string {
+	col := ColumnOf(f.Field)
+	def := fmt.Sprintf("%s %s GENERATED ALWAYS AS (%s) STORED", QuoteIdentifier(col.Name), col.Type, f.Expression.Expression())
+
+	if !col.Nullable {
+		def += fmt.Sprintf(" %s", ConstraintNotNull.String())
+	}
+
+	if col.Unique {
+		def += fmt.Sprintf(" %s", ConstraintUnqiue.String())
+	}
+
+	if col.PrimaryKey {
+		def += fmt.Sprintf(" %s", ConstraintPrimaryKey.String())
+	}
+	return def
+}
+
+// Name returns the column name of the underlying field.
+func (f *GeneratedField) Name() string {
+	return f.Field.Name()
+}
+
+// CommentSQL generates the comment statement of the underlying field.
+func (f *GeneratedField) CommentSQL(tableName string) string {
+	return f.Field.CommentSQL(tableName)
+}
+
+// Validate checks the underlying field and that it can be generated.
+func (f *GeneratedField) Validate() error {
+	if f.Field == nil {
+		return errors.New("GeneratedField has no field")
+	}
+	switch f.Field.(type) {
+	case *GeneratedField:
+		return fmt.Errorf("GeneratedField %s: the field is already generated", f.Field.Name())
+	case *ForeignKeyField:
+		return fmt.Errorf("GeneratedField %s: foreign keys cannot be generated", f.Field.Name())
+	}
+	if err := f.Field.Validate(); err != nil {
+		return err
+	}
+
+	col := ColumnOf(f.Field)
+	if col.Type == "" {
+		return fmt.Errorf("GeneratedField %s: unsupported field %T", col.Name, f.Field)
+	}
+	if f.Expression == nil {
+		return fmt.Errorf("GeneratedField %s has no expression", col.Name)
+	}
+	if err := validateExpression(f.Expression); err != nil {
+		return fmt.Errorf("GeneratedField %s: %w", col.Name, err)
+	}
+	if strings.TrimSpace(f.Expression.Expression()) == "" {
+		return fmt.Errorf("GeneratedField %s has no expression", col.Name)
+	}
+	if hasDefault(f.Field) {
+		return fmt.Errorf("GeneratedField %s cannot also have a default", col.Name)
+	}
+	return nil
+}
+
+// hasDefault reports whether field declares a default. The FALSE default a
+// BooleanField always renders is implicit and does not count.
+func hasDefault(field Field) bool {
+	if f, ok := field.(*BooleanField); ok {
+		return f.Default
+	}
+	col := ColumnOf(field)
+	return col.Default != "" || col.ServerDefault
+}
+
+// GoType returns the Go type of the underlying field.
+func (f *GeneratedField) GoType() string {
+	return f.Field.GoType()
+}
+
+// IndexSQL generates the index statement of the underlying field, if it has one.
+func (f *GeneratedField) IndexSQL(tableName string) string {
+	if indexed, ok := f.Field.(indexedField); ok {
+		return indexed.IndexSQL(tableName)
+	}
+	return ""
+}
+
+// IndexName returns the name of the index of the underlying field.
+func (f *GeneratedField) IndexName(tableName string) string {
+	if indexed, ok := f.Field.(indexedField); ok {
+		return indexed.IndexName(tableName)
+	}
+	return ""
+}
@@ -0,0 +1,217 @@
+package trenovaorm
+
+import "testing"
+
+var fullName = Raw{SQL: `"first_name" || ' ' || "last_name"`}
+
+func TestGeneratedField_Definition(t *testing.T) {
+	tests := []struct {
+		name     string
+		field    GeneratedField
+		expected string
+	}{
+		{
+			name:     "Text",
+			field:    GeneratedField{Field: &TextField{ColumnName: "full_name"}, Expression: fullName},
+			expected: `"full_name" TEXT GENERATED ALWAYS AS ("first_name" || ' ' || "last_name") STORED NOT NULL`,
+		},
+		{
+			name: "Nullable tsvector",
+			field: GeneratedField{
+				Field:      &TextField{ColumnName: "search", CustomType: "tsvector", Nullable: true},
+				Expression: Raw{SQL: `to_tsvector('english', "bio")`},
+			},
+			expected: `"search" tsvector GENERATED ALWAYS AS (to_tsvector('english', "bio")) STORED`,
+		},
+		{
+			name: "Unique numeric",
+			field: GeneratedField{
+				Field:      &NumericField{ColumnName: "total", Precision: 19, Scale: 4, Unique: true},
+				Expression: Raw{SQL: `"quantity" * "unit_price"`},
+			},
+			expected: `"total" NUMERIC(19, 4) GENERATED ALWAYS AS ("quantity" * "unit_price") STORED NOT NULL UNIQUE`,
+		},
+		{
+			name:     "Boolean",
+			field:    GeneratedField{Field: &BooleanField{ColumnName: "is_big"}, Expression: Raw{SQL: `"n" > 10`}},
+			expected: `"is_big" BOOLEAN GENERATED ALWAYS AS ("n" > 10) STORED NOT NULL`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.field.Definition(); got != tt.expected {
+				t.Errorf("GeneratedField.Definition() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGeneratedField_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   GeneratedField
+		wantErr bool
+	}{
+		{"Valid", GeneratedField{Field: &TextField{ColumnName: "full_name"}, Expression: fullName}, false},
+		{"Missing field", GeneratedField{Expression: fullName}, true},
+		{"Missing expression", GeneratedField{Field: &TextField{ColumnName: "full_name"}}, true},
+		{"Empty expression", GeneratedField{Field: &TextField{ColumnName: "full_name"}, Expression: Raw{}}, true},
+		{"Invalid field", GeneratedField{Field: &TextField{}, Expression: fullName}, true},
+		{"Literal default", GeneratedField{Field: &TextField{ColumnName: "full_name", Default: "x"}, Expression: fullName}, true},
+		{"Function default", GeneratedField{Field: &UUIDField{ColumnName: "id", Default: UUIDGenerateV4}, Expression: fullName}, true},
+		{"Boolean", GeneratedField{Field: &BooleanField{ColumnName: "is_big"}, Expression: Raw{SQL: `"n" > 10`}}, false},
+		{"Boolean default", GeneratedField{Field: &BooleanField{ColumnName: "is_big", Default: true}, Expression: Raw{SQL: `"n" > 10`}}, true},
+		{"Serial", GeneratedField{Field: &IntegerField{ColumnName: "n", Serial: true}, Expression: Raw{SQL: "1"}}, true},
+		{
+			name:    "Foreign key",
+			field:   GeneratedField{Field: &ForeignKeyField{ColumnName: "role_id", ReferenceTable: "roles", ReferenceField: "id"}, Expression: Raw{SQL: "1"}},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid expression",
+			field:   GeneratedField{Field: &TextField{ColumnName: "full_name"}, Expression: Func{Name: "upper", Args: []Expression{Ref{}}}},
+			wantErr: true,
+		},
+		{
+			name:    "Generated twice",
+			field:   GeneratedField{Field: &GeneratedField{Field: &TextField{ColumnName: "full_name"}, Expression: fullName}, Expression: fullName},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.field.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("GeneratedField.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCompile_GeneratedColumns(t *testing.T) {
+	orders := func(fields ...Field) Model {
+		base := []Field{&IntegerField{ColumnName: "quantity"}, &NumericField{ColumnName: "unit_price", Precision: 19, Scale: 4}}
+		return &testModel{name: "orders", fields: append(base, fields...)}
+	}
+	total := Binary{Left: Ref{Column: "quantity"}, Operator: "*", Right: Ref{Column: "unit_price"}}
+
+	schema := mustCompile(t, orders(&GeneratedField{Field: &NumericField{ColumnName: "total", Precision: 19, Scale: 4}, Expression: total}))
+	want := `"total" NUMERIC(19, 4) GENERATED ALWAYS AS (("quantity" * "unit_price")) STORED NOT NULL`
+	if got := schema.Table("orders").Field("total").Definition(); got != want {
+		t.Errorf("GeneratedField.Definition() = %v, want %v", got, want)
+	}
+
+	bulk := Binary{Left: Ref{Column: "quantity"}, Operator: ">", Right: Literal{Value: 10}}
+	schema = mustCompile(t, orders(&GeneratedField{Field: &BooleanField{ColumnName: "is_bulk"}, Expression: bulk}))
+	want = `"is_bulk" BOOLEAN GENERATED ALWAYS AS (("quantity" > 10)) STORED NOT NULL`
+	if got := schema.Table("orders").Field("is_bulk").Definition(); got != want {
+		t.Errorf("GeneratedField.Definition() = %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		name  string
+		model Model
+	}{
+		{
+			name:  "Unknown column",
+			model: orders(&GeneratedField{Field: &IntegerField{ColumnName: "double"}, Expression: Binary{Left: Ref{Column: "missing"}, Operator: "*", Right: Literal{Value: 2}}}),
+		},
+		{
+			name: "Generated column",
+			model: orders(
+				&GeneratedField{Field: &NumericField{ColumnName: "total", Precision: 19, Scale: 4}, Expression: total},
+				&GeneratedField{Field: &NumericField{ColumnName: "tax", Precision: 19, Scale: 4}, Expression: Binary{Left: Ref{Column: "total"}, Operator: "*", Right: Literal{Value: 0.2}}},
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Compile(tt.model); err == nil {
+				t.Error("Compile() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestGeneratedField_Column(t *testing.T) {
+	field := &GeneratedField{Field: &TextField{ColumnName: "full_name", Index: true, Comment: "Display name"}, Expression: fullName}
+
+	col := ColumnOf(field)
+	if col.Generated != fullName.SQL || col.Type != "TEXT" || col.Comment != "Display name" {
+		t.Errorf("ColumnOf() = %+v, want a generated TEXT column", col)
+	}
+	if got, want := field.GoType(), "string"; got != want {
+		t.Errorf("GeneratedField.GoType() = %v, want %v", got, want)
+	}
+	if got, want := field.IndexSQL("users"), `CREATE INDEX "idx_users_full_name" ON "users" ("full_name");`; got != want {
+		t.Errorf("GeneratedField.IndexSQL() = %v, want %v", got, want)
+	}
+
+	flag := ColumnOf(&GeneratedField{Field: &BooleanField{ColumnName: "is_big"}, Expression: Raw{SQL: `"n" > 10`}})
+	if flag.Default != "" || flag.Type != "BOOLEAN" {
+		t.Errorf("ColumnOf() = %+v, want a BOOLEAN column without a default", flag)
+	}
+}
+
+func TestGeneratedField_Queries(t *testing.T) {
+	users := &testModel{name: "users", fields: []Field{
+		&UUIDField{ColumnName: "id", PrimaryKey: true},
+		&TextField{ColumnName: "first_name"},
+		&TextField{ColumnName: "last_name"},
+		&GeneratedField{Field: &TextField{ColumnName: "full_name"}, Expression: fullName},
+	}}
+
+	if _, _, err := Insert(users).Columns("id", "first_name", "last_name").Values("1", "Ada", "Lovelace").Build(); err != nil {
+		t.Errorf("InsertQuery.Build() without the generated column error = %v", err)
+	}
+	if _, _, err := Insert(users).Columns("id", "first_name", "last_name", "full_name").Values("1", "Ada", "Lovelace", "x").Build(); err == nil {
+		t.Error("InsertQuery.Build() with the generated column error = nil, want error")
+	}
+	if _, _, err := Update(users).Set("full_name", "x").Build(); err == nil {
+		t.Error("UpdateQuery.Build() setting the generated column error = nil, want error")
+	}
+}
+
+func TestDiff_Generated(t *testing.T) {
+	plain := &TextField{ColumnName: "full_name"}
+	generated := func(expr Expression) Model {
+		return &testModel{name: "users", fields: []Field{&GeneratedField{Field: &TextField{ColumnName: "full_name"}, Expression: expr}}}
+	}
+
+	tests := []struct {
+		name string
+		from Model
+		to   Model
+		want []string
+	}{
+		{
+			name: "Change expression",
+			from: generated(fullName),
+			to:   generated(Raw{SQL: `"last_name"`}),
+			want: []string{`ALTER TABLE "users" ALTER COLUMN "full_name" SET EXPRESSION AS ("last_name");`},
+		},
+		{
+			name: "Drop expression",
+			from: generated(fullName),
+			to:   &testModel{name: "users", fields: []Field{plain}},
+			want: []string{`ALTER TABLE "users" ALTER COLUMN "full_name" DROP EXPRESSION;`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Diff(mustCompile(t, tt.from), mustCompile(t, tt.to))
+			if err != nil {
+				t.Fatalf("Diff() error = %v", err)
+			}
+			assertStatements(t, got, tt.want)
+		})
+	}
+
+	from := mustCompile(t, &testModel{name: "users", fields: []Field{plain}})
+	if _, err := Diff(from, mustCompile(t, generated(fullName))); err == nil {
+		t.Error("Diff() making an existing column generated error = nil, want error")
+	}
+}
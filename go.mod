@@ -2,7 +2,10 @@ module github.com/emoss08/trenova-orm
 
 go 1.22.0
 
-require github.com/bytedance/sonic v1.11.9
+require (
+	github.com/bytedance/sonic v1.11.9
+	github.com/lib/pq v1.10.9
+)
 
 require (
 	github.com/bytedance/sonic/loader v0.1.1 // indirect
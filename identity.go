@@ -0,0 +1,100 @@
+package trenovaorm
+
+import (
+	"errors"
+	"fmt"
+)
+
+// IdentityGeneration controls whether an identity column accepts explicit values.
+type IdentityGeneration string
+
+const (
+	// IdentityAlways rejects explicit values unless the insert overrides the system value.
+	IdentityAlways IdentityGeneration = "ALWAYS"
+	// IdentityByDefault uses the sequence only when no value is supplied.
+	IdentityByDefault IdentityGeneration = "BY DEFAULT"
+)
+
+// Identity declares an integer column as GENERATED ... AS IDENTITY. The zero
+// value declares no identity.
+type Identity struct {
+	Generation IdentityGeneration
+	Start      int64 // START WITH; 0 keeps the sequence default of 1
+	Increment  int64 // INCREMENT BY; 0 keeps the sequence default of 1
+}
+
+// IsZero reports whether the identity is unset.
+func (i Identity) IsZero() bool {
+	return i == Identity{}
+}
+
+// SQL generates the identity clause of a column definition, or an empty string
+// when the identity is unset.
+func (i Identity) SQL() string {
+	if i.Generation == "" {
+		return ""
+	}
+	sql := fmt.Sprintf("GENERATED %s AS IDENTITY", i.Generation)
+	var options string
+	if i.Start != 0 {
+		options = fmt.Sprintf("START WITH %d", i.Start)
+	}
+	if i.Increment != 0 {
+		if options != "" {
+			options += " "
+		}
+		options += fmt.Sprintf("INCREMENT BY %d", i.Increment)
+	}
+	if options != "" {
+		sql += " (" + options + ")"
+	}
+	return sql
+}
+
+// Validate checks the generation and that sequence options are only set on identities.
+func (i Identity) Validate() error {
+	switch i.Generation {
+	case IdentityAlways, IdentityByDefault:
+		return nil
+	case "":
+		if !i.IsZero() {
+			return errors.New("identity options require a generation")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown identity generation %q", i.Generation)
+	}
+}
+
+// normalized returns the identity with options equal to the sequence defaults
+// cleared, so that the identity compares equal to one that omits them.
+func (i Identity) normalized() Identity {
+	if i.Start == 1 {
+		i.Start = 0
+	}
+	if i.Increment == 1 {
+		i.Increment = 0
+	}
+	return i
+}
+
+// validateInteger checks the key and generation options shared by the integer fields.
+func validateInteger(nullable, primaryKey, serial, hasDefault bool, identity Identity) error {
+	if err := identity.Validate(); err != nil {
+		return err
+	}
+	if primaryKey && nullable {
+		return errors.New("primary key field cannot be nullable")
+	}
+	generated := serial || !identity.IsZero()
+	if serial && !identity.IsZero() {
+		return errors.New("a column cannot be both serial and an identity")
+	}
+	if generated && nullable {
+		return errors.New("serial and identity columns cannot be nullable")
+	}
+	if generated && hasDefault {
+		return errors.New("serial and identity columns cannot have a default")
+	}
+	return nil
+}
@@ -3,15 +3,128 @@ package trenovaorm
 import (
 	"errors"
 	"fmt"
+	"regexp"
+	"slices"
 	"strings"
 )
 
+// IndexMethod is the access method of an index.
+type IndexMethod string
+
+const (
+	IndexBtree  IndexMethod = "btree" // The default, for equality and range lookups and sorting
+	IndexHash   IndexMethod = "hash"  // Equality lookups on a single column
+	IndexGist   IndexMethod = "gist"  // Geometric, range and full-text data
+	IndexSPGist IndexMethod = "spgist"
+	IndexGin    IndexMethod = "gin"  // Arrays, jsonb, tsvector and trigrams
+	IndexBrin   IndexMethod = "brin" // Large tables whose rows are physically ordered by the key
+)
+
+// indexMethodSupport describes the index options an access method accepts.
+type indexMethodSupport struct {
+	unique      bool
+	include     bool
+	order       bool
+	multicolumn bool
+	parameters  []string
+}
+
+// indexMethods lists the options of the built-in access methods. Methods added
+// by extensions, such as bloom, are accepted without sorting or uniqueness.
+var indexMethods = map[IndexMethod]indexMethodSupport{
+	IndexBtree:  {unique: true, include: true, order: true, multicolumn: true, parameters: []string{"fillfactor", "deduplicate_items"}},
+	IndexHash:   {parameters: []string{"fillfactor"}},
+	IndexGist:   {include: true, multicolumn: true, parameters: []string{"fillfactor", "buffering"}},
+	IndexSPGist: {include: true, parameters: []string{"fillfactor"}},
+	IndexGin:    {multicolumn: true, parameters: []string{"fastupdate", "gin_pending_list_limit"}},
+	IndexBrin:   {multicolumn: true, parameters: []string{"pages_per_range", "autosummarize"}},
+}
+
+// NullsOrder places NULLs before or after the other values of an index key.
+type NullsOrder string
+
+const (
+	NullsFirst NullsOrder = "FIRST"
+	NullsLast  NullsOrder = "LAST"
+)
+
+var (
+	storageParameter = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+	storageValue     = regexp.MustCompile(`^[A-Za-z0-9_.]+$`)
+)
+
+// IndexElement is an index key with options, such as an operator class or a
+// sort order. It indexes either Column or Expression.
+type IndexElement struct {
+	Column        string
+	Expression    Expression // Indexed instead of Column when set
+	OperatorClass string     // e.g. gin_trgm_ops or jsonb_path_ops
+	Descending    bool       // Sorts the key in descending order, btree only
+	Nulls         NullsOrder // Places NULLs first or last, btree only
+}
+
+// name returns the name the element contributes to a generated index name.
+func (e IndexElement) name() string {
+	if e.Expression != nil {
+		return strings.Join(e.Expression.ColumnNames(), "_")
+	}
+	return e.Column
+}
+
+// sql renders the element as it appears in the key list.
+func (e IndexElement) sql() string {
+	def := QuoteIdentifier(e.Column)
+	if e.Expression != nil {
+		def = e.Expression.Expression()
+	}
+	if e.OperatorClass != "" {
+		def += " " + e.OperatorClass
+	}
+	if e.Descending {
+		def += " DESC"
+	}
+	if e.Nulls != "" {
+		def += " NULLS " + string(e.Nulls)
+	}
+	return def
+}
+
+// validate checks the element on its own.
+func (e IndexElement) validate() error {
+	if (e.Column == "") == (e.Expression == nil) {
+		return errors.New("index element must have either a column or an expression")
+	}
+	if e.Expression != nil {
+		if err := validateExpression(e.Expression); err != nil {
+			return err
+		}
+	}
+	if e.OperatorClass != "" && !qualifiedName.MatchString(e.OperatorClass) {
+		return fmt.Errorf("invalid operator class %q", e.OperatorClass)
+	}
+	if e.Nulls != "" && e.Nulls != NullsFirst && e.Nulls != NullsLast {
+		return fmt.Errorf("invalid nulls order %q", e.Nulls)
+	}
+	return nil
+}
+
 // Index defines the structure for database indices, supporting both simple and complex cases.
 type Index struct {
-	Name        string       // Index name
-	Columns     []string     // Simple column names
-	Expressions []Expression // Custom SQL expressions as Expression interface
-	Unique      bool         // Whether the index is unique
+	Name        string            // Index name
+	Columns     []string          // Simple column names
+	Expressions []Expression      // Custom SQL expressions as Expression interface
+	Elements    []IndexElement    // Keys with options, listed after Columns and Expressions
+	Unique      bool              // Whether the index is unique
+	Method      IndexMethod       // Access method, defaults to btree
+	Include     []string          // Non-key columns stored in the index for index-only scans
+	Where       string            // SQL predicate of a partial index, used verbatim
+	Condition   Expression        // Predicate of a partial index, used instead of Where when set
+	With        map[string]string // Storage parameters, e.g. {"fillfactor": "70"}
+	// Concurrently builds the index without blocking writes to the table.
+	// PostgreSQL refuses it inside a transaction block, so migrate.Generate
+	// writes it as a migration of its own. Indexes of new tables are built
+	// normally.
+	Concurrently bool
 }
 
 // generateName generates an index name based on the table and column names.
@@ -25,17 +138,157 @@ func (idx *Index) generateName(tableName string) string {
 	colNames = append(colNames, idx.Columns...)
 
 	for _, exp := range idx.Expressions {
-		colNames = append(colNames, exp.ColumnName())
+		colNames = append(colNames, exp.ColumnNames()...)
+	}
+
+	for _, elem := range idx.Elements {
+		colNames = append(colNames, elem.name())
+	}
+
+	return fmt.Sprintf("%s_%s_idx", unqualifiedName(tableName), strings.Join(colNames, "_"))
+}
+
+// method returns the access method of the index, taken from Method or from the
+// deprecated Gist, Gin, Btree and Hash expressions.
+func (idx *Index) method() (IndexMethod, error) {
+	method := idx.Method
+	for _, exp := range idx.Expressions {
+		var m IndexMethod
+		switch exp.(type) {
+		case Gist:
+			m = IndexGist
+		case Gin:
+			m = IndexGin
+		case Btree:
+			m = IndexBtree
+		case Hash:
+			m = IndexHash
+		default:
+			continue
+		}
+		if method != "" && method != m {
+			return "", fmt.Errorf("index method %s conflicts with %s", m, method)
+		}
+		method = m
+	}
+
+	if method == "" {
+		return IndexBtree, nil
+	}
+	if !accessMethod.MatchString(string(method)) {
+		return "", fmt.Errorf("invalid index method %q", method)
+	}
+	return method, nil
+}
+
+// predicate returns the SQL predicate of a partial index, or "" if the index
+// covers every row.
+func (idx *Index) predicate() string {
+	if idx.Condition != nil {
+		return idx.Condition.Expression()
 	}
+	return idx.Where
+}
 
-	return fmt.Sprintf("%s_%s_idx", tableName, strings.Join(colNames, "_"))
+// columnNames returns the table columns the index refers to, including those of
+// its expressions, its predicate condition and the INCLUDE columns.
+func (idx *Index) columnNames() []string {
+	columns := slices.Clone(idx.Columns)
+	columns = append(columns, columnsOf(idx.Expressions...)...)
+	for _, elem := range idx.Elements {
+		if elem.Expression != nil {
+			columns = append(columns, elem.Expression.ColumnNames()...)
+		} else {
+			columns = append(columns, elem.Column)
+		}
+	}
+	if idx.Condition != nil {
+		columns = append(columns, idx.Condition.ColumnNames()...)
+	}
+	return append(columns, idx.Include...)
 }
 
-// Validate checks the integrity of the Index struct.
+// keyColumns returns the key columns of the index, or false if any key is an
+// expression.
+func (idx *Index) keyColumns() ([]string, bool) {
+	if len(idx.Expressions) > 0 {
+		return nil, false
+	}
+	columns := slices.Clone(idx.Columns)
+	for _, elem := range idx.Elements {
+		if elem.Expression != nil {
+			return nil, false
+		}
+		columns = append(columns, elem.Column)
+	}
+	return columns, true
+}
+
+// Validate checks the integrity of the Index struct and that its options are
+// supported by the access method.
 func (idx *Index) Validate() error {
-	if len(idx.Columns) == 0 && len(idx.Expressions) == 0 {
+	keys := len(idx.Columns) + len(idx.Expressions) + len(idx.Elements)
+	if keys == 0 {
 		return errors.New("at least one column or expression must be specified")
 	}
+
+	method, err := idx.method()
+	if err != nil {
+		return err
+	}
+	support, known := indexMethods[method]
+
+	if idx.Unique && !support.unique {
+		return fmt.Errorf("%s indexes cannot be unique", method)
+	}
+	if known && keys > 1 && !support.multicolumn {
+		return fmt.Errorf("%s indexes support a single key", method)
+	}
+
+	if err := validateExpressions(idx.Expressions); err != nil {
+		return err
+	}
+	for _, elem := range idx.Elements {
+		if err := elem.validate(); err != nil {
+			return err
+		}
+		if (elem.Descending || elem.Nulls != "") && !support.order {
+			return fmt.Errorf("%s indexes cannot be sorted", method)
+		}
+	}
+
+	if len(idx.Include) > 0 {
+		if known && !support.include {
+			return fmt.Errorf("%s indexes cannot include columns", method)
+		}
+		if err := validateColumns(idx.Include); err != nil {
+			return fmt.Errorf("include: %w", err)
+		}
+	}
+
+	if idx.Condition != nil {
+		if idx.Where != "" {
+			return errors.New("where predicate and condition cannot both be set")
+		}
+		if err := validateExpression(idx.Condition); err != nil {
+			return fmt.Errorf("condition: %w", err)
+		}
+	}
+	if idx.Where != "" && strings.TrimSpace(idx.Where) == "" {
+		return errors.New("where predicate cannot be blank")
+	}
+
+	for name, value := range idx.With {
+		if !storageParameter.MatchString(name) {
+			return fmt.Errorf("invalid storage parameter %q", name)
+		}
+		if known && !slices.Contains(support.parameters, name) {
+			return fmt.Errorf("%s indexes do not support storage parameter %s", method, name)
+		}
+		if !storageValue.MatchString(value) {
+			return fmt.Errorf("invalid value %q for storage parameter %s", value, name)
+		}
+	}
 	return nil
 }
 
@@ -53,14 +306,40 @@ func (idx *Index) SQL(tableName string) (string, error) {
 		uniqueness = "UNIQUE "
 	}
 
+	concurrently := ""
+	if idx.Concurrently {
+		concurrently = "CONCURRENTLY "
+	}
+
+	using := ""
+	if method, _ := idx.method(); method != IndexBtree {
+		using = fmt.Sprintf("USING %s ", method)
+	}
+
 	var parts []string
 	for _, col := range idx.Columns {
-		parts = append(parts, quoteIdentifier(col))
+		parts = append(parts, QuoteIdentifier(col))
 	}
 	for _, exp := range idx.Expressions {
 		parts = append(parts, exp.Expression())
 	}
+	for _, elem := range idx.Elements {
+		parts = append(parts, elem.sql())
+	}
 
 	expressions := strings.Join(parts, ", ")
-	return fmt.Sprintf(`CREATE %sINDEX IF NOT EXISTS "%s" ON "%s" (%s);`, uniqueness, idx.Name, tableName, expressions), nil
+	sql := fmt.Sprintf("CREATE %sINDEX %sIF NOT EXISTS %s ON %s %s(%s)", uniqueness, concurrently, QuoteIdentifier(idx.Name), QuoteQualifiedName(tableName), using, expressions)
+
+	if len(idx.Include) > 0 {
+		sql += fmt.Sprintf(" INCLUDE (%s)", joinColumns(idx.Include))
+	}
+
+	if len(idx.With) > 0 {
+		sql += fmt.Sprintf(" WITH (%s)", storageList(idx.With))
+	}
+
+	if where := idx.predicate(); where != "" {
+		sql += fmt.Sprintf(" WHERE (%s)", where)
+	}
+	return sql + ";", nil
 }
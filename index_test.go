@@ -11,6 +11,26 @@ func TestIndex_Validate(t *testing.T) {
 		{"Valid Index with Columns", Index{Columns: []string{"col1"}}, false},
 		{"Valid Index with Expressions", Index{Expressions: []Expression{Lower{Column: "col1"}}}, false},
 		{"Invalid Index with No Columns or Expressions", Index{}, true},
+		{"Valid Gin Index with Operator Class", Index{Method: IndexGin, Elements: []IndexElement{{Column: "name", OperatorClass: "gin_trgm_ops"}}}, false},
+		{"Valid Extension Method", Index{Method: "bloom", Columns: []string{"a", "b"}, With: map[string]string{"length": "80"}}, false},
+		{"Invalid Method", Index{Method: "gin; DROP", Columns: []string{"col1"}}, true},
+		{"Conflicting Deprecated Method", Index{Method: IndexGist, Expressions: []Expression{Gin{Column: "col1"}}}, true},
+		{"Invalid Unique Gin Index", Index{Method: IndexGin, Columns: []string{"col1"}, Unique: true}, true},
+		{"Invalid Multicolumn Hash Index", Index{Method: IndexHash, Columns: []string{"col1", "col2"}}, true},
+		{"Invalid Sorted Gist Index", Index{Method: IndexGist, Elements: []IndexElement{{Column: "col1", Descending: true}}}, true},
+		{"Invalid Include on Gin Index", Index{Method: IndexGin, Columns: []string{"col1"}, Include: []string{"col2"}}, true},
+		{"Invalid Duplicate Include", Index{Columns: []string{"col1"}, Include: []string{"col2", "col2"}}, true},
+		{"Invalid Element with Column and Expression", Index{Elements: []IndexElement{{Column: "col1", Expression: Lower{Column: "col1"}}}}, true},
+		{"Invalid Operator Class", Index{Elements: []IndexElement{{Column: "col1", OperatorClass: "ops)"}}}, true},
+		{"Invalid Nulls Order", Index{Elements: []IndexElement{{Column: "col1", Nulls: "MIDDLE"}}}, true},
+		{"Invalid Storage Parameter for Method", Index{Columns: []string{"col1"}, With: map[string]string{"fastupdate": "off"}}, true},
+		{"Invalid Storage Parameter Value", Index{Columns: []string{"col1"}, With: map[string]string{"fillfactor": "70)"}}, true},
+		{"Invalid Blank Where", Index{Columns: []string{"col1"}, Where: " "}, true},
+		{"Valid Condition", Index{Columns: []string{"col1"}, Condition: Binary{Left: Ref{Column: "col2"}, Operator: ">", Right: Literal{Value: 0}}}, false},
+		{"Invalid Where and Condition", Index{Columns: []string{"col1"}, Where: "true", Condition: Ref{Column: "active"}}, true},
+		{"Invalid Condition", Index{Columns: []string{"col1"}, Condition: Binary{Left: Ref{Column: "col2"}, Operator: ";", Right: Literal{Value: 0}}}, true},
+		{"Invalid Expression", Index{Expressions: []Expression{Func{Name: "lower", Args: []Expression{nil}}}}, true},
+		{"Invalid Element Expression", Index{Elements: []IndexElement{{Expression: Cast{Operand: Ref{Column: "a"}, Type: "--"}}}}, true},
 	}
 
 	for _, tt := range tests {
@@ -66,6 +86,70 @@ func TestIndex_SQL(t *testing.T) {
 			`CREATE UNIQUE INDEX IF NOT EXISTS "table_col1_idx" ON "table" (LOWER("col1"));`,
 			false,
 		},
+		{
+			"SQL for Partial Covering Index",
+			Index{
+				Columns:      []string{"email"},
+				Unique:       true,
+				Include:      []string{"name"},
+				Where:        `"deleted_at" IS NULL`,
+				With:         map[string]string{"fillfactor": "70", "deduplicate_items": "off"},
+				Concurrently: true,
+			},
+			"table",
+			`CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS "table_email_idx" ON "table" ("email") INCLUDE ("name") ` +
+				`WITH (deduplicate_items = off, fillfactor = 70) WHERE ("deleted_at" IS NULL);`,
+			false,
+		},
+		{
+			"SQL for Partial Index with Condition",
+			Index{
+				Columns:   []string{"email"},
+				Unique:    true,
+				Condition: AllOf{Operands: []Expression{Negate{Operand: Ref{Column: "deleted"}}, Binary{Left: Ref{Column: "status"}, Operator: "=", Right: Literal{Value: "it's active"}}}},
+			},
+			"table",
+			`CREATE UNIQUE INDEX IF NOT EXISTS "table_email_idx" ON "table" ("email") WHERE (((NOT "deleted") AND ("status" = 'it''s active')));`,
+			false,
+		},
+		{
+			"SQL for Index with Sorted Elements",
+			Index{Columns: []string{"tenant_id"}, Elements: []IndexElement{{Column: "created_at", Descending: true, Nulls: NullsLast}}},
+			"table",
+			`CREATE INDEX IF NOT EXISTS "table_tenant_id_created_at_idx" ON "table" ("tenant_id", "created_at" DESC NULLS LAST);`,
+			false,
+		},
+		{
+			"SQL for Gin Index with Operator Class",
+			Index{Method: IndexGin, Elements: []IndexElement{{Column: "metadata", OperatorClass: "jsonb_path_ops"}}},
+			"table",
+			`CREATE INDEX IF NOT EXISTS "table_metadata_idx" ON "table" USING gin ("metadata" jsonb_path_ops);`,
+			false,
+		},
+		{
+			"SQL for Deprecated Gist Expression",
+			Index{Expressions: []Expression{Gist{Column: "area"}}},
+			"table",
+			`CREATE INDEX IF NOT EXISTS "table_area_idx" ON "table" USING gist ("area");`,
+			false,
+		},
+		{
+			"SQL for Expression Tree Index",
+			Index{Elements: []IndexElement{
+				{Expression: DateTrunc{Field: "day", Operand: Ref{Column: "created_at"}}, Descending: true},
+				{Expression: Binary{Left: Ref{Column: "metadata"}, Operator: "->>", Right: Literal{Value: "kind"}}},
+			}},
+			"table",
+			`CREATE INDEX IF NOT EXISTS "table_created_at_metadata_idx" ON "table" (date_trunc('day', "created_at") DESC, ("metadata" ->> 'kind'));`,
+			false,
+		},
+		{
+			"SQL for Explicit Btree Index",
+			Index{Method: IndexBtree, Columns: []string{"col1"}},
+			"table",
+			`CREATE INDEX IF NOT EXISTS "table_col1_idx" ON "table" ("col1");`,
+			false,
+		},
 		{
 			"SQL for Invalid Index with No Columns or Expressions",
 			Index{},
@@ -88,3 +172,74 @@ func TestIndex_SQL(t *testing.T) {
 		})
 	}
 }
+
+func TestCompile_IndexColumns(t *testing.T) {
+	fields := []Field{&TextField{ColumnName: "email"}, &TextField{ColumnName: "name"}}
+	for _, idx := range []Index{
+		{Columns: []string{"email"}, Include: []string{"missing"}},
+		{Elements: []IndexElement{{Column: "missing", Descending: true}}},
+		{Expressions: []Expression{Coalesce{Args: []Expression{Ref{Column: "email"}, Ref{Column: "missing"}}}}},
+		{Elements: []IndexElement{{Expression: JSONGetText{Operand: Ref{Column: "missing"}, Key: "a"}}}},
+		{Columns: []string{"email"}, Condition: Binary{Left: Ref{Column: "missing"}, Operator: ">", Right: Literal{Value: 0}}},
+	} {
+		if _, err := Compile(&testModel{name: "users", fields: fields, indexes: []Index{idx}}); err == nil {
+			t.Errorf("Compile() with index %+v error = nil, want error", idx)
+		}
+	}
+}
+
+func TestDiff_IndexConcurrently(t *testing.T) {
+	fields := []Field{&TextField{ColumnName: "email"}, &TextField{ColumnName: "name"}}
+	from := mustCompile(t, &testModel{name: "users", fields: fields, indexes: []Index{
+		{Name: "users_email_idx", Columns: []string{"email"}, Concurrently: true},
+		{Name: "users_name_idx", Columns: []string{"name"}, Concurrently: true},
+	}})
+	to := mustCompile(t, &testModel{name: "users", fields: fields, indexes: []Index{
+		{Name: "users_email_idx", Columns: []string{"email"}},
+	}})
+
+	got, err := Diff(from, to)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	assertStatements(t, got, []string{`DROP INDEX CONCURRENTLY IF EXISTS "users_name_idx";`})
+	if !got[0].Concurrent || got[0].Index != "users_name_idx" {
+		t.Errorf("Diff() statement = %+v, want a concurrent drop of users_name_idx", got[0])
+	}
+
+	created, err := Diff(mustCompile(t), from)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	assertStatements(t, created[1:], []string{
+		`CREATE INDEX IF NOT EXISTS "users_email_idx" ON "users" ("email");`,
+		`CREATE INDEX IF NOT EXISTS "users_name_idx" ON "users" ("name");`,
+	})
+}
+
+func TestInsertQuery_PartialIndexConflictTarget(t *testing.T) {
+	model := &testModel{
+		name:   "users",
+		fields: []Field{&TextField{ColumnName: "email"}},
+		indexes: []Index{
+			{Columns: []string{"email"}, Unique: true, Where: `"deleted_at" IS NULL`},
+		},
+	}
+	query := Insert(model).Columns("email").Values("a@example.com").OnConflict("email").DoNothing()
+	if _, _, err := query.Build(); err == nil {
+		t.Error("InsertQuery.Build() with partial index conflict target error = nil, want error")
+	}
+
+	model.indexes[0].Where = ""
+	model.indexes[0].Condition = Negate{Operand: Ref{Column: "archived"}}
+	if _, _, err := query.Build(); err == nil {
+		t.Error("InsertQuery.Build() with partial index condition conflict target error = nil, want error")
+	}
+
+	model.indexes[0].Condition = nil
+	model.indexes[0].Columns = nil
+	model.indexes[0].Elements = []IndexElement{{Column: "email", Descending: true}}
+	if _, _, err := query.Build(); err != nil {
+		t.Errorf("InsertQuery.Build() with unique index conflict target error = %v", err)
+	}
+}
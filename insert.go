@@ -0,0 +1,273 @@
+package trenovaorm
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// InsertQuery builds a parameterized, possibly multi-row INSERT statement for
+// the table of a model, with optional ON CONFLICT and RETURNING clauses.
+type InsertQuery struct {
+	model     Model
+	scope     queryScope
+	columns   []string
+	rows      [][]any
+	conflict  []string
+	action    conflictAction
+	updates   []string
+	returning []string
+}
+
+// conflictAction is the action of an ON CONFLICT clause.
+type conflictAction int
+
+const (
+	conflictNone conflictAction = iota
+	conflictDoNothing
+	conflictDoUpdate
+)
+
+// Insert starts an INSERT statement for the table of model.
+func Insert(model Model) *InsertQuery {
+	return &InsertQuery{
+		model: model,
+		scope: queryScope{tables: []queryTable{newQueryTable(model)}},
+	}
+}
+
+// Columns sets the columns supplied by each row.
+func (q *InsertQuery) Columns(columns ...string) *InsertQuery {
+	q.columns = columns
+	return q
+}
+
+// Values adds a row with one value per column. Call Values again to insert several rows.
+func (q *InsertQuery) Values(values ...any) *InsertQuery {
+	q.rows = append(q.rows, values)
+	return q
+}
+
+// OnConflict sets the conflict target. The columns must match the primary key,
+// a Unique field or a unique Index of the model.
+func (q *InsertQuery) OnConflict(columns ...string) *InsertQuery {
+	q.conflict = columns
+	return q
+}
+
+// DoNothing skips rows that conflict with an existing row.
+func (q *InsertQuery) DoNothing() *InsertQuery {
+	q.action = conflictDoNothing
+	return q
+}
+
+// DoUpdate overwrites the given columns of a conflicting row with the values
+// of the proposed row. Without columns, every inserted column outside the
+// conflict target is overwritten.
+func (q *InsertQuery) DoUpdate(columns ...string) *InsertQuery {
+	q.action = conflictDoUpdate
+	q.updates = columns
+	return q
+}
+
+// Returning sets the columns returned for each inserted row.
+func (q *InsertQuery) Returning(columns ...string) *InsertQuery {
+	q.returning = columns
+	return q
+}
+
+// Build returns the INSERT statement with $n placeholders and its arguments, or
+// an error if a column is unknown, a required column is missing, or the
+// conflict target is not unique.
+func (q *InsertQuery) Build() (string, []any, error) {
+	if IsView(q.model) {
+		return "", nil, fmt.Errorf("view %q is read-only", q.model.TableName())
+	}
+	table := q.scope.tables[0]
+	if len(q.columns) == 0 {
+		return "", nil, errors.New("insert requires at least one column")
+	}
+	if len(q.rows) == 0 {
+		return "", nil, errors.New("insert requires at least one row")
+	}
+	if err := q.validateColumns(); err != nil {
+		return "", nil, err
+	}
+
+	b := &queryBuilder{scope: &q.scope}
+	b.write(fmt.Sprintf("INSERT INTO %s (", table.sql()))
+	b.columns(q.columns)
+	b.write(") VALUES ")
+	for i, row := range q.rows {
+		if len(row) != len(q.columns) {
+			return "", nil, fmt.Errorf("row %d has %d values, want %d", i+1, len(row), len(q.columns))
+		}
+		if i > 0 {
+			b.write(", ")
+		}
+		b.write("(")
+		for j, value := range row {
+			if j > 0 {
+				b.write(", ")
+			}
+			b.arg(value)
+		}
+		b.write(")")
+	}
+
+	if err := q.appendConflict(b); err != nil {
+		return "", nil, err
+	}
+	b.returning(q.returning)
+	return b.build()
+}
+
+// validateColumns checks that the columns exist, that no generated column or
+// identity column generated always is supplied and that every non-nullable
+// column without a default is supplied with a non-nil value.
+func (q *InsertQuery) validateColumns() error {
+	supplied := make(map[string]int, len(q.columns))
+	for i, c := range q.columns {
+		if !q.scope.tables[0].has(c) {
+			return fmt.Errorf("column %q does not exist on table %q", c, q.model.TableName())
+		}
+		if _, dup := supplied[c]; dup {
+			return fmt.Errorf("column %q is supplied twice", c)
+		}
+		supplied[c] = i
+	}
+
+	for _, field := range ModelFields(q.model) {
+		col := ColumnOf(field)
+		i, ok := supplied[col.Name]
+		if ok {
+			if err := checkAssignable(col); err != nil {
+				return err
+			}
+		}
+		if col.Nullable || col.Generated != "" {
+			continue
+		}
+		if !ok {
+			if col.Default == "" && !col.ServerDefault {
+				return fmt.Errorf("column %q is not nullable and has no default", col.Name)
+			}
+			continue
+		}
+		for r, row := range q.rows {
+			if i < len(row) && row[i] == nil {
+				return fmt.Errorf("row %d: column %q is not nullable", r+1, col.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// appendConflict appends the ON CONFLICT clause.
+func (q *InsertQuery) appendConflict(b *queryBuilder) error {
+	if q.action == conflictNone {
+		if len(q.conflict) > 0 {
+			return errors.New("ON CONFLICT requires DoNothing or DoUpdate")
+		}
+		return nil
+	}
+	if q.action == conflictDoUpdate && len(q.conflict) == 0 {
+		return errors.New("DO UPDATE requires a conflict target")
+	}
+	if len(q.conflict) > 0 && !isUniqueKey(q.model, q.conflict) {
+		return fmt.Errorf("conflict target (%s) is not a unique key of table %q",
+			strings.Join(q.conflict, ", "), q.model.TableName())
+	}
+
+	b.write(" ON CONFLICT")
+	if len(q.conflict) > 0 {
+		b.write(" (")
+		b.columns(q.conflict)
+		b.write(")")
+	}
+	if q.action == conflictDoNothing {
+		b.write(" DO NOTHING")
+		return nil
+	}
+
+	updates := q.updates
+	if len(updates) == 0 {
+		for _, c := range q.columns {
+			if !slices.Contains(q.conflict, c) {
+				updates = append(updates, c)
+			}
+		}
+	}
+	if len(updates) == 0 {
+		return errors.New("DO UPDATE has no columns to update")
+	}
+	columns := make(map[string]Column)
+	for _, field := range ModelFields(q.model) {
+		col := ColumnOf(field)
+		columns[col.Name] = col
+	}
+	b.write(" DO UPDATE SET ")
+	for i, c := range updates {
+		col, ok := columns[c]
+		if !ok {
+			return fmt.Errorf("column %q does not exist on table %q", c, q.model.TableName())
+		}
+		if err := checkAssignable(col); err != nil {
+			return err
+		}
+		if i > 0 {
+			b.write(", ")
+		}
+		b.write(fmt.Sprintf("%s = EXCLUDED.%s", QuoteIdentifier(c), QuoteIdentifier(c)))
+	}
+	return nil
+}
+
+// isUniqueKey reports whether columns, in any order, are exactly the primary
+// key, a Unique field or the columns of a unique, non-partial Index of model.
+func isUniqueKey(model Model, columns []string) bool {
+	target := sortedCopy(columns)
+
+	var primaryKey []string
+	for _, field := range ModelFields(model) {
+		col := ColumnOf(field)
+		if col.PrimaryKey {
+			primaryKey = append(primaryKey, col.Name)
+		}
+		if col.Unique && len(target) == 1 && target[0] == col.Name {
+			return true
+		}
+	}
+	if len(primaryKey) > 0 && slices.Equal(sortedCopy(primaryKey), target) {
+		return true
+	}
+
+	for _, c := range ModelConstraints(model) {
+		var columns []string
+		if pk, ok := asPrimaryKey(c); ok {
+			columns = pk.Columns
+		} else if u, ok := asUnique(c); ok {
+			columns = u.Columns
+		}
+		if columns != nil && slices.Equal(sortedCopy(columns), target) {
+			return true
+		}
+	}
+
+	for _, idx := range model.Indexes() {
+		if !idx.Unique || idx.predicate() != "" {
+			continue
+		}
+		if columns, ok := idx.keyColumns(); ok && slices.Equal(sortedCopy(columns), target) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedCopy(s []string) []string {
+	c := slices.Clone(s)
+	slices.Sort(c)
+	return c
+}
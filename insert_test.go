@@ -0,0 +1,149 @@
+package trenovaorm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInsertQuery_Build(t *testing.T) {
+	accounts := &testModel{
+		name: "accounts",
+		fields: []Field{
+			&UUIDField{ColumnName: "id", PrimaryKey: true, Default: UUIDGenerateV4},
+			&CharField{ColumnName: "email", MaxLength: 255, Unique: true},
+			&CharField{ColumnName: "code", MaxLength: 10},
+			&IntegerField{ColumnName: "tenant_id"},
+			&BooleanField{ColumnName: "active"},
+		},
+		indexes: []Index{{Columns: []string{"tenant_id", "code"}, Unique: true}},
+	}
+
+	tests := []struct {
+		name     string
+		query    *InsertQuery
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name: "Identity and serial columns are optional",
+			query: Insert(&testModel{name: "events", fields: []Field{
+				&BigIntegerField{ColumnName: "id", PrimaryKey: true, Identity: Identity{Generation: IdentityByDefault}},
+				&IntegerField{ColumnName: "seq", Serial: true},
+				&TextField{ColumnName: "payload"},
+			}}).Columns("payload").Values("{}").Returning("id", "seq"),
+			wantSQL:  `INSERT INTO "events" ("payload") VALUES ($1) RETURNING "id", "seq"`,
+			wantArgs: []any{"{}"},
+		},
+		{
+			name:     "Single row",
+			query:    Insert(accounts).Columns("email", "code", "tenant_id").Values("a@example.com", "A", 1),
+			wantSQL:  `INSERT INTO "accounts" ("email", "code", "tenant_id") VALUES ($1, $2, $3)`,
+			wantArgs: []any{"a@example.com", "A", 1},
+		},
+		{
+			name: "Multiple rows with RETURNING",
+			query: Insert(accounts).Columns("email", "code", "tenant_id").
+				Values("a@example.com", "A", 1).
+				Values("b@example.com", "B", 1).
+				Returning("id"),
+			wantSQL:  `INSERT INTO "accounts" ("email", "code", "tenant_id") VALUES ($1, $2, $3), ($4, $5, $6) RETURNING "id"`,
+			wantArgs: []any{"a@example.com", "A", 1, "b@example.com", "B", 1},
+		},
+		{
+			name: "Upsert on a unique field",
+			query: Insert(accounts).Columns("email", "code", "tenant_id").
+				Values("a@example.com", "A", 1).
+				OnConflict("email").DoUpdate(),
+			wantSQL: `INSERT INTO "accounts" ("email", "code", "tenant_id") VALUES ($1, $2, $3) ` +
+				`ON CONFLICT ("email") DO UPDATE SET "code" = EXCLUDED."code", "tenant_id" = EXCLUDED."tenant_id"`,
+			wantArgs: []any{"a@example.com", "A", 1},
+		},
+		{
+			name: "Upsert on a unique index",
+			query: Insert(accounts).Columns("email", "code", "tenant_id").
+				Values("a@example.com", "A", 1).
+				OnConflict("code", "tenant_id").DoUpdate("email").
+				Returning("id", "active"),
+			wantSQL: `INSERT INTO "accounts" ("email", "code", "tenant_id") VALUES ($1, $2, $3) ` +
+				`ON CONFLICT ("code", "tenant_id") DO UPDATE SET "email" = EXCLUDED."email" RETURNING "id", "active"`,
+			wantArgs: []any{"a@example.com", "A", 1},
+		},
+		{
+			name: "Do nothing",
+			query: Insert(accounts).Columns("id", "email", "code", "tenant_id").
+				Values("1", "a@example.com", "A", 1).
+				OnConflict("id").DoNothing(),
+			wantSQL:  `INSERT INTO "accounts" ("id", "email", "code", "tenant_id") VALUES ($1, $2, $3, $4) ON CONFLICT ("id") DO NOTHING`,
+			wantArgs: []any{"1", "a@example.com", "A", 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.query.Build()
+			if err != nil {
+				t.Fatalf("InsertQuery.Build() error = %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("InsertQuery.Build() sql = %v, want %v", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("InsertQuery.Build() args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestInsertQuery_BuildErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query *InsertQuery
+	}{
+		{name: "No columns", query: Insert(queryOrganizations).Values("1", "Acme")},
+		{name: "No rows", query: Insert(queryOrganizations).Columns("id", "name")},
+		{name: "Unknown column", query: Insert(queryOrganizations).Columns("id", "name", "slug").Values("1", "Acme", "acme")},
+		{name: "Missing required column", query: Insert(queryOrganizations).Columns("id").Values("1")},
+		{name: "Nil required value", query: Insert(queryOrganizations).Columns("id", "name").Values("1", nil)},
+		{name: "Wrong number of values", query: Insert(queryOrganizations).Columns("id", "name").Values("1")},
+		{
+			name:  "Conflict target is not unique",
+			query: Insert(queryOrganizations).Columns("id", "name").Values("1", "Acme").OnConflict("name").DoNothing(),
+		},
+		{
+			name:  "Do update without a target",
+			query: Insert(queryOrganizations).Columns("id", "name").Values("1", "Acme").DoUpdate(),
+		},
+		{
+			name: "Value for an identity generated always",
+			query: Insert(&testModel{name: "events", fields: []Field{
+				&BigIntegerField{ColumnName: "id", PrimaryKey: true, Identity: Identity{Generation: IdentityAlways}},
+			}}).Columns("id").Values(int64(1)),
+		},
+		{
+			name: "Do update of an identity generated always",
+			query: Insert(&testModel{name: "events", fields: []Field{
+				&BigIntegerField{ColumnName: "id", PrimaryKey: true, Identity: Identity{Generation: IdentityAlways}},
+				&TextField{ColumnName: "code", Unique: true},
+			}}).Columns("code").Values("a").OnConflict("code").DoUpdate("id"),
+		},
+		{
+			name: "Do update of a generated column",
+			query: Insert(&testModel{name: "events", fields: []Field{
+				&TextField{ColumnName: "code", Unique: true},
+				&GeneratedField{Field: &TextField{ColumnName: "slug"}, Expression: Func{Name: "lower", Args: []Expression{Ref{Column: "code"}}}},
+			}}).Columns("code").Values("a").OnConflict("code").DoUpdate("slug"),
+		},
+		{
+			name:  "Aggregate in RETURNING",
+			query: Insert(queryOrganizations).Columns("id", "name").Values("1", "Acme").Returning("count(*)"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := tt.query.Build(); err == nil {
+				t.Error("InsertQuery.Build() error = nil, want error")
+			}
+		})
+	}
+}
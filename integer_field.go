@@ -8,6 +8,9 @@ import (
 // IntegerField represents an integer field in the database.
 type IntegerField struct {
 	ColumnName  string
+	PrimaryKey  bool
+	Serial      bool     // Use SERIAL, backed by a sequence
+	Identity    Identity // Declare the column GENERATED ... AS IDENTITY
 	Nullable    bool
 	Unique      bool
 	Default     int
@@ -20,17 +23,20 @@ type IntegerField struct {
 
 // Definition generates the SQL definition for the IntegerField.
 func (f *IntegerField) Definition() string {
-	typ := "INTEGER"
-	if f.CustomType != "" {
-		typ = f.CustomType
-	}
-
-	def := fmt.Sprintf(`"%s" %s`, f.ColumnName, typ)
+	def := fmt.Sprintf("%s %s", QuoteIdentifier(f.ColumnName), f.sqlType())
 
 	if !f.Nullable {
 		def += fmt.Sprintf(" %s", ConstraintNotNull.String())
 	}
 
+	if identity := f.Identity.SQL(); identity != "" {
+		def += " " + identity
+	}
+
+	if f.PrimaryKey {
+		def += fmt.Sprintf(" %s", ConstraintPrimaryKey.String())
+	}
+
 	if f.Unique {
 		def += fmt.Sprintf(" %s", ConstraintUnqiue.String())
 	}
@@ -56,7 +62,7 @@ func (f *IntegerField) CommentSQL(tableName string) string {
 	if f.Comment == "" {
 		return ""
 	}
-	return fmt.Sprintf(`COMMENT ON COLUMN "%s"."%s" IS '%s';`, tableName, f.ColumnName, f.Comment)
+	return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;", QuoteQualifiedName(tableName), QuoteIdentifier(f.ColumnName), QuoteLiteral(f.Comment))
 }
 
 // Validate checks if the field's configuration is valid.
@@ -65,7 +71,7 @@ func (f *IntegerField) Validate() error {
 		return fmt.Errorf("column name cannot be empty")
 	}
 
-	return nil
+	return validateInteger(f.Nullable, f.PrimaryKey, f.Serial, f.Default != 0, f.Identity)
 }
 
 // GoType returns the Go type for the IntegerField.
@@ -81,6 +87,23 @@ func (f *IntegerField) IndexSQL(tableName string) string {
 	if !f.Index {
 		return ""
 	}
-	indexName := fmt.Sprintf("idx_%s_%s", tableName, f.ColumnName)
-	return fmt.Sprintf(`CREATE INDEX "%s" ON "%s" ("%s");`, indexName, tableName, f.ColumnName)
+	indexName := f.IndexName(tableName)
+	return fmt.Sprintf("CREATE INDEX %s ON %s (%s);", QuoteIdentifier(indexName), QuoteQualifiedName(tableName), QuoteIdentifier(f.ColumnName))
+}
+
+// IndexName returns the name of the index generated by IndexSQL.
+func (f *IntegerField) IndexName(tableName string) string {
+	return fmt.Sprintf("idx_%s_%s", unqualifiedName(tableName), f.ColumnName)
+}
+
+// sqlType returns the SQL type of the column.
+func (f *IntegerField) sqlType() string {
+	switch {
+	case f.CustomType != "":
+		return f.CustomType
+	case f.Serial:
+		return "SERIAL"
+	default:
+		return "INTEGER"
+	}
 }
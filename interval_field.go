@@ -0,0 +1,100 @@
+package trenovaorm
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// IntervalField represents an interval field in the database.
+type IntervalField struct {
+	ColumnName  string
+	Precision   int // Fractional digits of the seconds, 1-6; 0 keeps the PostgreSQL default of 6
+	Nullable    bool
+	Unique      bool
+	Default     time.Duration
+	Index       bool
+	Comment     string
+	CustomType  string
+	Constraints []string
+	StructTag   string
+}
+
+// Definition generates the SQL definition for the IntervalField.
+func (f *IntervalField) Definition() string {
+	def := fmt.Sprintf("%s %s", QuoteIdentifier(f.ColumnName), f.sqlType())
+
+	if !f.Nullable {
+		def += fmt.Sprintf(" %s", ConstraintNotNull.String())
+	}
+
+	if f.Unique {
+		def += fmt.Sprintf(" %s", ConstraintUnqiue.String())
+	}
+
+	if f.Default != 0 {
+		def += " DEFAULT " + QuoteLiteral(formatInterval(f.Default))
+	}
+
+	if len(f.Constraints) > 0 {
+		def += " " + strings.Join(f.Constraints, " ")
+	}
+	return def
+}
+
+// Name returns the column name for the IntervalField.
+func (f *IntervalField) Name() string {
+	return f.ColumnName
+}
+
+// CommentSQL generates the SQL statement for adding a comment to the IntervalField.
+func (f *IntervalField) CommentSQL(tableName string) string {
+	if f.Comment == "" {
+		return ""
+	}
+	return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;", QuoteQualifiedName(tableName), QuoteIdentifier(f.ColumnName), QuoteLiteral(f.Comment))
+}
+
+// Validate checks if the field's configuration is valid.
+func (f *IntervalField) Validate() error {
+	if f.ColumnName == "" {
+		return fmt.Errorf("column name cannot be empty")
+	}
+	if f.Precision < 0 || f.Precision > 6 {
+		return fmt.Errorf("IntervalField %s precision must be between 0 and 6", f.ColumnName)
+	}
+	return nil
+}
+
+// GoType returns the Go type for the IntervalField.
+func (f *IntervalField) GoType() string {
+	if f.Nullable {
+		return "*Interval"
+	}
+	return "Interval"
+}
+
+// IndexSQL generates the SQL statement for creating an index if Index is true.
+func (f *IntervalField) IndexSQL(tableName string) string {
+	if !f.Index {
+		return ""
+	}
+	indexName := f.IndexName(tableName)
+	return fmt.Sprintf("CREATE INDEX %s ON %s (%s);", QuoteIdentifier(indexName), QuoteQualifiedName(tableName), QuoteIdentifier(f.ColumnName))
+}
+
+// IndexName returns the name of the index generated by IndexSQL.
+func (f *IntervalField) IndexName(tableName string) string {
+	return fmt.Sprintf("idx_%s_%s", unqualifiedName(tableName), f.ColumnName)
+}
+
+// sqlType returns the SQL type of the column.
+func (f *IntervalField) sqlType() string {
+	if f.CustomType != "" {
+		return f.CustomType
+	}
+	if f.Precision > 0 {
+		return fmt.Sprintf("INTERVAL(%d)", f.Precision)
+	}
+	return "INTERVAL"
+}
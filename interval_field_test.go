@@ -0,0 +1,119 @@
+package trenovaorm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIntervalField_Definition(t *testing.T) {
+	tests := []struct {
+		name     string
+		field    IntervalField
+		expected string
+	}{
+		{
+			name:     "Basic IntervalField",
+			field:    IntervalField{ColumnName: "timeout"},
+			expected: `"timeout" INTERVAL NOT NULL`,
+		},
+		{
+			name:     "IntervalField with Default",
+			field:    IntervalField{ColumnName: "timeout", Default: 90*time.Minute + 1500*time.Millisecond},
+			expected: `"timeout" INTERVAL NOT NULL DEFAULT '01:30:01.5'`,
+		},
+		{
+			name:     "Nullable IntervalField",
+			field:    IntervalField{ColumnName: "timeout", Nullable: true},
+			expected: `"timeout" INTERVAL`,
+		},
+		{
+			name:     "IntervalField with precision",
+			field:    IntervalField{ColumnName: "timeout", Precision: 3},
+			expected: `"timeout" INTERVAL(3) NOT NULL`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.field.Definition(); got != tt.expected {
+				t.Errorf("IntervalField.Definition() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIntervalField_GoType(t *testing.T) {
+	if got := (&IntervalField{ColumnName: "timeout"}).GoType(); got != "Interval" {
+		t.Errorf("IntervalField.GoType() = %v, want Interval", got)
+	}
+	if got := (&IntervalField{ColumnName: "timeout", Nullable: true}).GoType(); got != "*Interval" {
+		t.Errorf("IntervalField.GoType() = %v, want *Interval", got)
+	}
+}
+
+func TestInterval_Scan(t *testing.T) {
+	day := 24 * time.Hour
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"00:00:00", 0},
+		{"01:30:00", 90 * time.Minute},
+		{"26:03:04.5", 26*time.Hour + 3*time.Minute + 4500*time.Millisecond},
+		{"-00:00:01", -time.Second},
+		{"1 day", day},
+		{"3 days 04:05:06.000789", 3*day + 4*time.Hour + 5*time.Minute + 6*time.Second + 789*time.Microsecond},
+		{"-1 days +02:00:00", -day + 2*time.Hour},
+		{"1 year 2 mons", time.Duration(365.25*float64(day)) + 60*day},
+		{"1 mon -1 days", 29 * day},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			var got Interval
+			if err := got.Scan([]byte(tt.in)); err != nil {
+				t.Fatalf("Interval.Scan() error = %v", err)
+			}
+			if got.Duration != tt.want {
+				t.Errorf("Interval.Scan() = %v, want %v", got.Duration, tt.want)
+			}
+		})
+	}
+
+	for _, bad := range []string{"", "1", "1 fortnight", "1:2:3:4", "aa:00"} {
+		var i Interval
+		if err := i.Scan(bad); err == nil {
+			t.Errorf("Interval.Scan(%q) error = nil, want error", bad)
+		}
+	}
+}
+
+func TestInterval_Value(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{0, "00:00:00"},
+		{90 * time.Minute, "01:30:00"},
+		{-time.Second - 250*time.Millisecond, "-00:00:01.25"},
+		{50 * time.Hour, "50:00:00"},
+		{time.Nanosecond, "00:00:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			got, err := Interval{Duration: tt.in}.Value()
+			if err != nil {
+				t.Fatalf("Interval.Value() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Interval.Value() = %v, want %v", got, tt.want)
+			}
+
+			var back Interval
+			if err := back.Scan(got); err != nil || back.Duration != tt.in.Truncate(time.Microsecond) {
+				t.Errorf("Interval round trip = %v, %v, want %v", back.Duration, err, tt.in)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package introspect
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	trenovaorm "github.com/emoss08/trenova-orm"
+)
+
+const tablesQuery = `SELECT cls.relname, COALESCE(obj_description(cls.oid, 'pg_class'), ''),
+	COALESCE(array_to_string(cls.reloptions, ','), ''), COALESCE(spc.spcname, ''),
+	cls.relpersistence = 'u', COALESCE(pg_get_partkeydef(cls.oid), '')
+FROM pg_class cls
+JOIN pg_namespace nsp ON nsp.oid = cls.relnamespace
+LEFT JOIN pg_tablespace spc ON spc.oid = cls.reltablespace
+WHERE nsp.nspname = $1 AND cls.relkind IN ('r', 'p') AND NOT cls.relispartition
+ORDER BY cls.relname`
+
+const partitionsQuery = `SELECT parent.relname, child.relname, pg_get_expr(child.relpartbound, child.oid)
+FROM pg_inherits inh
+JOIN pg_class parent ON parent.oid = inh.inhparent
+JOIN pg_class child ON child.oid = inh.inhrelid
+JOIN pg_namespace nsp ON nsp.oid = parent.relnamespace
+WHERE nsp.nspname = $1 AND parent.relkind = 'p'
+ORDER BY parent.relname, child.relname`
+
+const columnsQuery = `SELECT table_name, column_name, data_type, udt_name, character_maximum_length,
+	numeric_precision, numeric_scale, datetime_precision, is_nullable, column_default,
+	is_identity, COALESCE(identity_generation, ''), identity_start, identity_increment,
+	COALESCE(generation_expression, '')
+FROM information_schema.columns
+WHERE table_schema = $1
+ORDER BY table_name, ordinal_position`
+
+const constraintsQuery = `SELECT rel.relname, con.conname, con.contype,
+	array_to_string(ARRAY(
+		SELECT att.attname FROM unnest(con.conkey) WITH ORDINALITY AS k(attnum, ord)
+		JOIN pg_attribute att ON att.attrelid = con.conrelid AND att.attnum = k.attnum
+		ORDER BY k.ord), ','),
+	COALESCE(fnsp.nspname, ''), COALESCE(frel.relname, ''),
+	COALESCE(array_to_string(ARRAY(
+		SELECT att.attname FROM unnest(con.confkey) WITH ORDINALITY AS k(attnum, ord)
+		JOIN pg_attribute att ON att.attrelid = con.confrelid AND att.attnum = k.attnum
+		ORDER BY k.ord), ','), ''),
+	con.confdeltype, con.confupdtype, con.confmatchtype, con.condeferrable, con.condeferred,
+	pg_get_constraintdef(con.oid)
+FROM pg_constraint con
+JOIN pg_class rel ON rel.oid = con.conrelid
+JOIN pg_namespace nsp ON nsp.oid = rel.relnamespace
+LEFT JOIN pg_class frel ON frel.oid = con.confrelid
+LEFT JOIN pg_namespace fnsp ON fnsp.oid = frel.relnamespace
+WHERE nsp.nspname = $1 AND con.contype IN ('p', 'u', 'f', 'c', 'x')
+ORDER BY rel.relname, con.conname`
+
+const indexesQuery = `SELECT tablename, indexname, indexdef
+FROM pg_indexes
+WHERE schemaname = $1
+ORDER BY tablename, indexname`
+
+const enumsQuery = `SELECT typ.typname, enm.enumlabel
+FROM pg_type typ
+JOIN pg_enum enm ON enm.enumtypid = typ.oid
+JOIN pg_namespace nsp ON nsp.oid = typ.typnamespace
+WHERE nsp.nspname = $1
+ORDER BY typ.typname, enm.enumsortorder`
+
+const commentsQuery = `SELECT cls.relname, att.attname, dsc.description
+FROM pg_description dsc
+JOIN pg_class cls ON cls.oid = dsc.objoid
+JOIN pg_namespace nsp ON nsp.oid = cls.relnamespace
+JOIN pg_attribute att ON att.attrelid = cls.oid AND att.attnum = dsc.objsubid
+WHERE nsp.nspname = $1 AND dsc.objsubid > 0
+ORDER BY cls.relname, att.attnum`
+
+// tableRow is a table of pg_class with its table-wide options.
+type tableRow struct {
+	Name       string
+	Comment    string
+	Options    string // reloptions joined by commas, e.g. fillfactor=70,autovacuum_enabled=off
+	Tablespace string // empty for the database's default tablespace
+	Unlogged   bool
+	// PartitionKey is the partition key of partitioned tables, e.g. RANGE (created_at).
+	PartitionKey string
+}
+
+// partitionRow is a partition of a partitioned table.
+type partitionRow struct {
+	Parent string
+	Name   string
+	Bound  string // e.g. FOR VALUES IN ('a', 'b')
+}
+
+// columnRow is a row of information_schema.columns.
+type columnRow struct {
+	Table     string
+	Name      string
+	DataType  string
+	UDTName   string // underlying type name, e.g. the name of an enum type
+	MaxLength sql.NullInt64
+	Precision sql.NullInt64
+	Scale     sql.NullInt64
+	// DateTimePrecision is the fractional seconds precision of timestamp and interval columns.
+	DateTimePrecision sql.NullInt64
+	Nullable          bool
+	Default           sql.NullString
+
+	// Identity is the identity generation (ALWAYS or BY DEFAULT) of identity columns.
+	Identity          string
+	IdentityStart     sql.NullInt64
+	IdentityIncrement sql.NullInt64
+
+	// Generated is the expression of stored generated columns, as deparsed by PostgreSQL.
+	Generated string
+
+	// EnumValues are the values of the column's enum type, or of its element
+	// type for arrays, if it has one.
+	EnumValues []string
+}
+
+// constraintRow is a primary key, unique, foreign key, check or exclusion
+// constraint row of pg_constraint.
+type constraintRow struct {
+	Table      string
+	Name       string
+	Type       string
+	Columns    string
+	RefSchema  string // schema of the table a foreign key references
+	RefTable   string // qualified as described by TableSchema if RefSchema is another schema
+	RefColumns string
+	OnDelete   string
+	OnUpdate   string
+	Match      string
+	Deferrable bool
+	Deferred   bool
+	// Definition is the constraint as deparsed by pg_get_constraintdef, e.g.
+	// CHECK ((amount > 0)).
+	Definition string
+}
+
+// indexRow is a row of pg_indexes.
+type indexRow struct {
+	Table      string
+	Name       string
+	Definition string
+}
+
+// commentRow is a column comment from pg_description.
+type commentRow struct {
+	Table   string
+	Column  string
+	Comment string
+}
+
+// catalog holds the raw catalog rows for a single PostgreSQL schema.
+type catalog struct {
+	schema      string
+	tables      []tableRow
+	partitions  []partitionRow
+	columns     []columnRow
+	constraints []constraintRow
+	indexes     []indexRow
+	comments    []commentRow
+}
+
+// loadCatalog reads every catalog table needed to reconstruct the models of a schema.
+func loadCatalog(ctx context.Context, db Queryer, schema string) (*catalog, error) {
+	c := &catalog{schema: schema}
+
+	err := query(ctx, db, tablesQuery, schema, func(rows *sql.Rows) error {
+		var row tableRow
+		if err := rows.Scan(&row.Name, &row.Comment, &row.Options, &row.Tablespace, &row.Unlogged, &row.PartitionKey); err != nil {
+			return err
+		}
+		c.tables = append(c.tables, row)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read tables: %w", err)
+	}
+
+	err = query(ctx, db, partitionsQuery, schema, func(rows *sql.Rows) error {
+		var row partitionRow
+		if err := rows.Scan(&row.Parent, &row.Name, &row.Bound); err != nil {
+			return err
+		}
+		c.partitions = append(c.partitions, row)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read partitions: %w", err)
+	}
+
+	err = query(ctx, db, columnsQuery, schema, func(rows *sql.Rows) error {
+		var row columnRow
+		var nullable, identity string
+		if err := rows.Scan(&row.Table, &row.Name, &row.DataType, &row.UDTName, &row.MaxLength,
+			&row.Precision, &row.Scale, &row.DateTimePrecision, &nullable, &row.Default,
+			&identity, &row.Identity, &row.IdentityStart, &row.IdentityIncrement, &row.Generated); err != nil {
+			return err
+		}
+		row.Nullable = nullable == "YES"
+		if identity != "YES" {
+			row.Identity = ""
+		}
+		c.columns = append(c.columns, row)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read columns: %w", err)
+	}
+
+	enums := make(map[string][]string)
+	err = query(ctx, db, enumsQuery, schema, func(rows *sql.Rows) error {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			return err
+		}
+		enums[name] = append(enums[name], value)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read enum types: %w", err)
+	}
+	for i := range c.columns {
+		switch c.columns[i].DataType {
+		case "USER-DEFINED":
+			c.columns[i].EnumValues = enums[c.columns[i].UDTName]
+		case "ARRAY":
+			c.columns[i].EnumValues = enums[strings.TrimPrefix(c.columns[i].UDTName, "_")]
+		}
+	}
+
+	err = query(ctx, db, constraintsQuery, schema, func(rows *sql.Rows) error {
+		var row constraintRow
+		if err := rows.Scan(&row.Table, &row.Name, &row.Type, &row.Columns,
+			&row.RefSchema, &row.RefTable, &row.RefColumns, &row.OnDelete, &row.OnUpdate,
+			&row.Match, &row.Deferrable, &row.Deferred, &row.Definition); err != nil {
+			return err
+		}
+		if row.RefSchema != "" && row.RefSchema != schema {
+			row.RefTable = trenovaorm.QualifyName(TableSchema(row.RefSchema), row.RefTable)
+		}
+		c.constraints = append(c.constraints, row)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read constraints: %w", err)
+	}
+
+	err = query(ctx, db, indexesQuery, schema, func(rows *sql.Rows) error {
+		var row indexRow
+		if err := rows.Scan(&row.Table, &row.Name, &row.Definition); err != nil {
+			return err
+		}
+		c.indexes = append(c.indexes, row)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read indexes: %w", err)
+	}
+
+	err = query(ctx, db, commentsQuery, schema, func(rows *sql.Rows) error {
+		var row commentRow
+		if err := rows.Scan(&row.Table, &row.Column, &row.Comment); err != nil {
+			return err
+		}
+		c.comments = append(c.comments, row)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read comments: %w", err)
+	}
+
+	return c, nil
+}
+
+// query runs a catalog query and calls scan for each returned row.
+func query(ctx context.Context, db Queryer, q, schema string, scan func(*sql.Rows) error) error {
+	rows, err := db.QueryContext(ctx, q, schema)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
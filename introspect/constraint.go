@@ -0,0 +1,105 @@
+package introspect
+
+import (
+	"strings"
+
+	trenovaorm "github.com/emoss08/trenova-orm"
+)
+
+// checkConstraint reconstructs a check constraint from its definition, of the
+// form CHECK ((expression)) with an optional NOT VALID or NO INHERIT. The
+// expression is kept as PostgreSQL deparses it.
+func checkConstraint(con constraintRow) trenovaorm.TableConstraint {
+	def := trimConstraintOptions(con.Definition)
+	expr, ok := strings.CutPrefix(def, "CHECK ")
+	if !ok {
+		return rawConstraint{name: con.Name, definition: con.Definition, columns: splitColumns(con.Columns)}
+	}
+	return trenovaorm.Check{Name: con.Name, Expression: unwrap(unwrap(expr)), Columns: splitColumns(con.Columns)}
+}
+
+// excludeConstraint reconstructs an exclusion constraint from a definition of
+// the form EXCLUDE USING method (column WITH operator, ...) [WHERE ((predicate))].
+// Definitions with expressions, operator classes or schema-qualified operators
+// are kept verbatim.
+func excludeConstraint(con constraintRow) trenovaorm.TableConstraint {
+	raw := rawConstraint{name: con.Name, definition: con.Definition, columns: splitColumns(con.Columns)}
+
+	rest, ok := strings.CutPrefix(trimConstraintOptions(con.Definition), "EXCLUDE USING ")
+	if !ok {
+		return raw
+	}
+	open := strings.Index(rest, "(")
+	if open < 0 {
+		return raw
+	}
+	c := trenovaorm.Exclude{Name: con.Name, Using: strings.TrimSpace(rest[:open])}
+
+	elements, rest, ok := splitList(rest[open:])
+	if !ok {
+		return raw
+	}
+	for _, elem := range elements {
+		key, operator, ok := strings.Cut(elem, " WITH ")
+		column, isColumn := unquoteIdentifier(key)
+		if !ok || !isColumn {
+			return raw
+		}
+		c.Elements = append(c.Elements, trenovaorm.ExcludeElement{Column: column, Operator: operator})
+	}
+
+	if rest = strings.TrimSpace(rest); rest != "" {
+		where, ok := strings.CutPrefix(rest, "WHERE ")
+		if !ok {
+			return raw
+		}
+		c.Where = unwrap(unwrap(where))
+	}
+	if c.Validate() != nil {
+		return raw
+	}
+	return c
+}
+
+// trimConstraintOptions removes the NOT VALID and NO INHERIT options that
+// pg_get_constraintdef appends to a definition.
+func trimConstraintOptions(def string) string {
+	def = strings.TrimSuffix(def, " NOT VALID")
+	return strings.TrimSuffix(def, " NO INHERIT")
+}
+
+// splitColumns splits a comma-joined column list, returning nil for an empty one.
+func splitColumns(columns string) []string {
+	if columns == "" {
+		return nil
+	}
+	return strings.Split(columns, ",")
+}
+
+// rawConstraint is a constraint whose definition is kept as PostgreSQL
+// reports it, for definitions the trenovaorm constraints cannot express.
+type rawConstraint struct {
+	name       string
+	definition string
+	columns    []string
+}
+
+// ConstraintName returns the name of the constraint in the database.
+func (c rawConstraint) ConstraintName(string) string {
+	return c.name
+}
+
+// Definition returns the definition reported by pg_get_constraintdef.
+func (c rawConstraint) Definition() string {
+	return c.definition
+}
+
+// ColumnNames returns the columns the constraint refers to.
+func (c rawConstraint) ColumnNames() []string {
+	return c.columns
+}
+
+// Validate accepts the definition, which PostgreSQL has already validated.
+func (c rawConstraint) Validate() error {
+	return nil
+}
@@ -0,0 +1,446 @@
+package introspect
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	trenovaorm "github.com/emoss08/trenova-orm"
+)
+
+// columnMeta carries the constraint and comment information attached to a column.
+type columnMeta struct {
+	primaryKey bool
+	unique     bool
+	foreignKey *constraintRow
+	comment    string
+}
+
+// buildField reconstructs the trenovaorm field that produces the given column.
+// Types without a dedicated field are returned as a TextField with a CustomType.
+func buildField(col columnRow, meta columnMeta, refType string) trenovaorm.Field {
+	if meta.foreignKey != nil {
+		typ := sqlType(col)
+		if typ == "INTEGER" {
+			typ = ""
+		}
+		field := &trenovaorm.ForeignKeyField{
+			ColumnName:     col.Name,
+			ReferenceTable: meta.foreignKey.RefTable,
+			ReferenceField: meta.foreignKey.RefColumns,
+			Annotations:    annotation(*meta.foreignKey),
+			Nullable:       col.Nullable,
+			Unique:         meta.unique,
+			Default:        literalDefault(col.Default.String),
+			Comment:        meta.comment,
+			CustomType:     typ,
+			ReferencedType: refType,
+		}
+		if meta.foreignKey.Name != field.ConstraintName(col.Table) {
+			field.ForeignKeyName = meta.foreignKey.Name
+		}
+		return field
+	}
+
+	var constraints []string
+	if meta.primaryKey && !hasPrimaryKeyFlag(col.DataType) {
+		constraints = append(constraints, trenovaorm.ConstraintPrimaryKey.String())
+	}
+
+	if col.EnumValues != nil && col.DataType != "ARRAY" {
+		return &trenovaorm.EnumField{
+			ColumnName:  col.Name,
+			TypeName:    col.UDTName,
+			Values:      col.EnumValues,
+			Nullable:    col.Nullable,
+			Unique:      meta.unique,
+			Default:     literalDefault(col.Default.String),
+			Comment:     meta.comment,
+			Constraints: constraints,
+		}
+	}
+
+	switch col.DataType {
+	case "ARRAY":
+		return &trenovaorm.ArrayField{
+			ColumnName:  col.Name,
+			Element:     elementField(col),
+			Nullable:    col.Nullable,
+			Default:     arrayDefault(col.Default.String),
+			Comment:     meta.comment,
+			Constraints: constraints,
+		}
+	case "character varying":
+		return &trenovaorm.CharField{
+			ColumnName:  col.Name,
+			MaxLength:   int(col.MaxLength.Int64),
+			Nullable:    col.Nullable,
+			Unique:      meta.unique,
+			Default:     literalDefault(col.Default.String),
+			Comment:     meta.comment,
+			Constraints: constraints,
+		}
+	case "text":
+		return &trenovaorm.TextField{
+			ColumnName:  col.Name,
+			Nullable:    col.Nullable,
+			Unique:      meta.unique,
+			Default:     literalDefault(col.Default.String),
+			Comment:     meta.comment,
+			Constraints: constraints,
+		}
+	case "jsonb", "json":
+		field := &trenovaorm.JSONField{
+			ColumnName:  col.Name,
+			Nullable:    col.Nullable,
+			Unique:      meta.unique,
+			Default:     literalDefault(col.Default.String),
+			Comment:     meta.comment,
+			Constraints: constraints,
+		}
+		if col.DataType == "json" {
+			field.CustomType = "JSON"
+		}
+		return field
+	case "boolean":
+		return &trenovaorm.BooleanField{
+			ColumnName:  col.Name,
+			Nullable:    col.Nullable,
+			Unique:      meta.unique,
+			Default:     literalDefault(col.Default.String) == "true",
+			Comment:     meta.comment,
+			Constraints: constraints,
+		}
+	case "integer":
+		serial, identity, def := integerOptions(col)
+		return &trenovaorm.IntegerField{
+			ColumnName:  col.Name,
+			PrimaryKey:  meta.primaryKey,
+			Serial:      serial,
+			Identity:    identity,
+			Nullable:    col.Nullable,
+			Unique:      meta.unique,
+			Default:     int(def),
+			Comment:     meta.comment,
+			Constraints: constraints,
+		}
+	case "bigint":
+		serial, identity, def := integerOptions(col)
+		return &trenovaorm.BigIntegerField{
+			ColumnName:  col.Name,
+			PrimaryKey:  meta.primaryKey,
+			Serial:      serial,
+			Identity:    identity,
+			Nullable:    col.Nullable,
+			Unique:      meta.unique,
+			Default:     def,
+			Comment:     meta.comment,
+			Constraints: constraints,
+		}
+	case "smallint":
+		serial, identity, def := integerOptions(col)
+		return &trenovaorm.SmallIntegerField{
+			ColumnName:  col.Name,
+			PrimaryKey:  meta.primaryKey,
+			Serial:      serial,
+			Identity:    identity,
+			Nullable:    col.Nullable,
+			Unique:      meta.unique,
+			Default:     int16(def),
+			Comment:     meta.comment,
+			Constraints: constraints,
+		}
+	case "numeric":
+		def, _ := strconv.ParseFloat(literalDefault(col.Default.String), 64)
+		return &trenovaorm.NumericField{
+			ColumnName:  col.Name,
+			Precision:   int(col.Precision.Int64),
+			Scale:       int(col.Scale.Int64),
+			Nullable:    col.Nullable,
+			Unique:      meta.unique,
+			Default:     def,
+			Comment:     meta.comment,
+			Constraints: constraints,
+		}
+	case "date":
+		return &trenovaorm.DateField{
+			ColumnName:  col.Name,
+			Nullable:    col.Nullable,
+			Unique:      meta.unique,
+			Default:     functionDefault(col.Default.String),
+			Comment:     meta.comment,
+			Constraints: constraints,
+		}
+	case "timestamp with time zone", "timestamp without time zone":
+		return &trenovaorm.TimestampField{
+			ColumnName:   col.Name,
+			WithTimeZone: col.DataType == "timestamp with time zone",
+			Precision:    timePrecision(col),
+			Nullable:     col.Nullable,
+			Unique:       meta.unique,
+			Default:      functionDefault(col.Default.String),
+			Comment:      meta.comment,
+			Constraints:  constraints,
+		}
+	case "interval":
+		var def time.Duration
+		if literal := literalDefault(col.Default.String); literal != "" {
+			var interval trenovaorm.Interval
+			if err := interval.Scan(literal); err == nil {
+				def = interval.Duration
+			}
+		}
+		return &trenovaorm.IntervalField{
+			ColumnName:  col.Name,
+			Precision:   timePrecision(col),
+			Nullable:    col.Nullable,
+			Unique:      meta.unique,
+			Default:     def,
+			Comment:     meta.comment,
+			Constraints: constraints,
+		}
+	case "time without time zone":
+		return &trenovaorm.TimeField{
+			ColumnName:  col.Name,
+			Nullable:    col.Nullable,
+			Unique:      meta.unique,
+			Default:     functionDefault(col.Default.String),
+			Comment:     meta.comment,
+			Constraints: constraints,
+		}
+	case "uuid":
+		return &trenovaorm.UUIDField{
+			ColumnName: col.Name,
+			Nullable:   col.Nullable,
+			Unique:     meta.unique,
+			Default:    functionDefault(col.Default.String),
+			Comment:    meta.comment,
+			PrimaryKey: meta.primaryKey,
+		}
+	default:
+		return &trenovaorm.TextField{
+			ColumnName:  col.Name,
+			Nullable:    col.Nullable,
+			Unique:      meta.unique,
+			Default:     literalDefault(col.Default.String),
+			Comment:     meta.comment,
+			CustomType:  sqlType(col),
+			Constraints: constraints,
+		}
+	}
+}
+
+// hasPrimaryKeyFlag reports whether the field built for a column of the given
+// data type declares its primary key with a PrimaryKey flag rather than a raw constraint.
+func hasPrimaryKeyFlag(dataType string) bool {
+	switch dataType {
+	case "uuid", "integer", "bigint", "smallint":
+		return true
+	}
+	return false
+}
+
+// integerOptions reports whether an integer column is serial, which PostgreSQL
+// records as a nextval() default, its identity, and its literal default.
+func integerOptions(col columnRow) (bool, trenovaorm.Identity, int64) {
+	if strings.HasPrefix(col.Default.String, "nextval(") {
+		return true, trenovaorm.Identity{}, 0
+	}
+	var identity trenovaorm.Identity
+	if col.Identity != "" {
+		identity.Generation = trenovaorm.IdentityGeneration(col.Identity)
+		if start := col.IdentityStart.Int64; start != 1 {
+			identity.Start = start
+		}
+		if increment := col.IdentityIncrement.Int64; increment != 1 {
+			identity.Increment = increment
+		}
+	}
+	def, _ := strconv.ParseInt(literalDefault(col.Default.String), 10, 64)
+	return false, identity, def
+}
+
+// timePrecision returns the precision of a timestamp or interval column, or 0
+// when it has the default precision of 6.
+func timePrecision(col columnRow) int {
+	if !col.DateTimePrecision.Valid || col.DateTimePrecision.Int64 == 6 {
+		return 0
+	}
+	return int(col.DateTimePrecision.Int64)
+}
+
+// elementField returns a field with the element type of an array column. The
+// underlying type of an array is its element type prefixed with an underscore.
+// information_schema does not report the length of varchar elements, so they
+// are returned as unbounded VARCHAR.
+func elementField(col columnRow) trenovaorm.Field {
+	udt := strings.TrimPrefix(col.UDTName, "_")
+	if col.EnumValues != nil {
+		return &trenovaorm.EnumField{TypeName: udt, Values: col.EnumValues}
+	}
+	switch udt {
+	case "text":
+		return &trenovaorm.TextField{}
+	case "varchar":
+		return &trenovaorm.TextField{CustomType: "VARCHAR"}
+	case "bool":
+		return &trenovaorm.BooleanField{}
+	case "int4":
+		return &trenovaorm.IntegerField{}
+	case "numeric":
+		return &trenovaorm.TextField{CustomType: "NUMERIC"}
+	case "date":
+		return &trenovaorm.DateField{}
+	case "time":
+		return &trenovaorm.TimeField{}
+	case "uuid":
+		return &trenovaorm.UUIDField{}
+	case "jsonb":
+		return &trenovaorm.JSONField{}
+	default:
+		return &trenovaorm.TextField{CustomType: strings.ToUpper(udt)}
+	}
+}
+
+// arrayDefault parses an array literal default such as '{a,b}'::text[] into
+// its elements. Defaults written as ARRAY[...] expressions are not recognized.
+func arrayDefault(def string) []string {
+	if def == "" {
+		return nil
+	}
+	var elems trenovaorm.Array[string]
+	if err := elems.Scan(literalDefault(def)); err != nil {
+		return nil
+	}
+	return elems
+}
+
+// sqlType returns the SQL type of a column spelled the way the trenovaorm fields spell it.
+func sqlType(col columnRow) string {
+	switch col.DataType {
+	case "character varying":
+		return fmt.Sprintf("VARCHAR(%d)", col.MaxLength.Int64)
+	case "numeric":
+		return fmt.Sprintf("NUMERIC(%d, %d)", col.Precision.Int64, col.Scale.Int64)
+	case "time without time zone":
+		return "TIME"
+	case "uuid":
+		return "uuid"
+	default:
+		return strings.ToUpper(col.DataType)
+	}
+}
+
+// literalDefault strips the quoting and type cast PostgreSQL adds to literal
+// defaults, e.g. 'active'::character varying becomes active.
+func literalDefault(def string) string {
+	if def == "" {
+		return ""
+	}
+	if !strings.HasPrefix(def, "'") {
+		if strings.HasPrefix(def, "(") && strings.HasSuffix(def, ")") {
+			def = def[1 : len(def)-1]
+		}
+		if i := strings.Index(def, "::"); i >= 0 && !strings.Contains(def, "(") {
+			def = def[:i]
+		}
+		return def
+	}
+
+	end := strings.LastIndex(def, "'")
+	if end <= 0 {
+		return def
+	}
+	return strings.ReplaceAll(def[1:end], "''", "'")
+}
+
+// functionDefault maps a function default back onto the predefined PSQLFunction values.
+func functionDefault(def string) trenovaorm.PSQLFunction {
+	switch strings.ToLower(def) {
+	case "":
+		return ""
+	case "current_timestamp":
+		return trenovaorm.CurrentTimestamp
+	case "uuid_generate_v4()":
+		return trenovaorm.UUIDGenerateV4
+	case "gen_random_uuid()":
+		return trenovaorm.GenRandomUUID
+	default:
+		return trenovaorm.PSQLFunction(def)
+	}
+}
+
+// annotation reconstructs the annotations of a foreign key constraint. MATCH
+// SIMPLE is the default and, like NO ACTION, maps to an empty option.
+func annotation(con constraintRow) trenovaorm.Annotation {
+	a := trenovaorm.Annotation{
+		OnDelete:          trenovaorm.OnDeleteOption(referentialAction(con.OnDelete)),
+		OnUpdate:          trenovaorm.OnUpdateOption(referentialAction(con.OnUpdate)),
+		Deferrable:        con.Deferrable,
+		InitiallyDeferred: con.Deferrable && con.Deferred,
+	}
+	if con.Match == "f" {
+		a.Match = trenovaorm.MatchFull
+	}
+	return a
+}
+
+// referentialAction maps pg_constraint action codes to their SQL keywords. NO ACTION
+// is the PostgreSQL default and maps to an empty action so the constraint renders
+// the same way a model that omits the annotation does.
+func referentialAction(code string) string {
+	switch code {
+	case "c":
+		return "CASCADE"
+	case "n":
+		return "SET NULL"
+	case "r":
+		return "RESTRICT"
+	case "d":
+		return "SET DEFAULT"
+	default:
+		return ""
+	}
+}
+
+// setIndex toggles the field-level Index flag on the fields that support it.
+func setIndex(field trenovaorm.Field, index bool) bool {
+	switch f := field.(type) {
+	case *trenovaorm.CharField:
+		f.Index = index
+	case *trenovaorm.TextField:
+		f.Index = index
+	case *trenovaorm.JSONField:
+		f.Index = index
+	case *trenovaorm.BooleanField:
+		f.Index = index
+	case *trenovaorm.IntegerField:
+		f.Index = index
+	case *trenovaorm.BigIntegerField:
+		f.Index = index
+	case *trenovaorm.SmallIntegerField:
+		f.Index = index
+	case *trenovaorm.NumericField:
+		f.Index = index
+	case *trenovaorm.DateField:
+		f.Index = index
+	case *trenovaorm.TimestampField:
+		f.Index = index
+	case *trenovaorm.IntervalField:
+		f.Index = index
+	case *trenovaorm.TimeField:
+		f.Index = index
+	case *trenovaorm.UUIDField:
+		f.Index = index
+	case *trenovaorm.ArrayField:
+		f.Index = index
+	case *trenovaorm.ForeignKeyField:
+		f.Index = index
+	case *trenovaorm.GeneratedField:
+		return setIndex(f.Field, index)
+	default:
+		return false
+	}
+	return true
+}
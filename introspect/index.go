@@ -0,0 +1,239 @@
+package introspect
+
+import (
+	"regexp"
+	"strings"
+
+	trenovaorm "github.com/emoss08/trenova-orm"
+)
+
+// indexDefinition is the parsed form of a pg_indexes.indexdef value.
+type indexDefinition struct {
+	unique   bool
+	method   string
+	elements []string
+	include  []string
+	with     map[string]string
+	where    string
+}
+
+// parseIndexDefinition parses definitions of the form
+// CREATE [UNIQUE] INDEX name ON [ONLY] schema.table USING method (element, ...)
+// [INCLUDE (column, ...)] [WITH (parameter=value, ...)] [WHERE predicate].
+func parseIndexDefinition(def string) (indexDefinition, bool) {
+	result := indexDefinition{unique: strings.HasPrefix(def, "CREATE UNIQUE ")}
+
+	using := strings.Index(def, " USING ")
+	if using < 0 {
+		return result, false
+	}
+	rest := def[using+len(" USING "):]
+
+	open := strings.Index(rest, "(")
+	if open < 0 {
+		return result, false
+	}
+	result.method = strings.TrimSpace(rest[:open])
+
+	elements, rest, ok := splitList(rest[open:])
+	if !ok {
+		return result, false
+	}
+	result.elements = elements
+
+	for rest = strings.TrimSpace(rest); rest != ""; rest = strings.TrimSpace(rest) {
+		var items []string
+		switch {
+		case strings.HasPrefix(rest, "INCLUDE "):
+			items, rest, ok = splitList(strings.TrimSpace(rest[len("INCLUDE "):]))
+			if !ok {
+				return result, false
+			}
+			for _, item := range items {
+				column, isColumn := unquoteIdentifier(item)
+				if !isColumn {
+					return result, false
+				}
+				result.include = append(result.include, column)
+			}
+		case strings.HasPrefix(rest, "NULLS NOT DISTINCT"):
+			rest = rest[len("NULLS NOT DISTINCT"):]
+		case strings.HasPrefix(rest, "WITH "):
+			items, rest, ok = splitList(strings.TrimSpace(rest[len("WITH "):]))
+			if !ok {
+				return result, false
+			}
+			result.with = make(map[string]string, len(items))
+			for _, item := range items {
+				name, value, _ := strings.Cut(item, "=")
+				result.with[strings.TrimSpace(name)] = strings.Trim(strings.TrimSpace(value), "'")
+			}
+		case strings.HasPrefix(rest, "WHERE "):
+			result.where = unwrap(strings.TrimSpace(rest[len("WHERE "):]))
+			return result, true
+		default:
+			return result, false
+		}
+	}
+	return result, true
+}
+
+// splitList splits a parenthesized, comma-separated list at its top level and
+// returns the items and what follows the closing parenthesis.
+func splitList(s string) ([]string, string, bool) {
+	if !strings.HasPrefix(s, "(") {
+		return nil, s, false
+	}
+
+	var items []string
+	depth := 0
+	start := 1
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				items = append(items, strings.TrimSpace(s[start:i]))
+				return items, s[i+1:], true
+			}
+		case ',':
+			if depth == 1 {
+				items = append(items, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return nil, s, false
+}
+
+// unwrap removes the parentheses PostgreSQL puts around a whole predicate.
+func unwrap(s string) string {
+	if items, rest, ok := splitList(s); ok && len(items) == 1 && rest == "" {
+		return items[0]
+	}
+	return s
+}
+
+// parseElement splits an index element into its key and the operator class
+// and sort order that follow it.
+func parseElement(elem string) trenovaorm.IndexElement {
+	var result trenovaorm.IndexElement
+	if key, ok := strings.CutSuffix(elem, " NULLS FIRST"); ok {
+		elem, result.Nulls = key, trenovaorm.NullsFirst
+	} else if key, ok := strings.CutSuffix(elem, " NULLS LAST"); ok {
+		elem, result.Nulls = key, trenovaorm.NullsLast
+	}
+	if key, ok := strings.CutSuffix(elem, " DESC"); ok {
+		elem, result.Descending = key, true
+	} else {
+		elem = strings.TrimSuffix(elem, " ASC")
+	}
+
+	if i := strings.LastIndex(elem, " "); i > 0 {
+		key, class := elem[:i], elem[i+1:]
+		_, isColumn := unquoteIdentifier(key)
+		if operatorClass.MatchString(class) && (isColumn || strings.HasSuffix(key, ")")) {
+			elem, result.OperatorClass = key, class
+		}
+	}
+
+	if column, ok := unquoteIdentifier(elem); ok {
+		result.Column = column
+	} else {
+		result.Expression = parseExpression(elem)
+	}
+	return result
+}
+
+var operatorClass = regexp.MustCompile(`^[a-z_][a-z0-9_]*(\.[a-z_][a-z0-9_]*)?$`)
+
+// index converts the parsed definition into a trenovaorm.Index. Keys are kept
+// in Columns and Expressions, unless one has options or listing columns first
+// would reorder them, in which case they are all kept in Elements.
+func (d indexDefinition) index(name string) trenovaorm.Index {
+	idx := trenovaorm.Index{Name: name, Unique: d.unique, Include: d.include, With: d.with, Where: d.where}
+	if d.method != "btree" {
+		idx.Method = trenovaorm.IndexMethod(d.method)
+	}
+
+	elements := make([]trenovaorm.IndexElement, len(d.elements))
+	plain := true
+	for i, elem := range d.elements {
+		elements[i] = parseElement(elem)
+		e := elements[i]
+		if e.OperatorClass != "" || e.Descending || e.Nulls != "" {
+			plain = false
+		}
+		if e.Column != "" && i > 0 && elements[i-1].Expression != nil {
+			plain = false
+		}
+	}
+
+	if !plain {
+		idx.Elements = elements
+		return idx
+	}
+	for _, e := range elements {
+		if e.Expression != nil {
+			idx.Expressions = append(idx.Expressions, e.Expression)
+		} else {
+			idx.Columns = append(idx.Columns, e.Column)
+		}
+	}
+	return idx
+}
+
+// parseExpression recognizes LOWER and UPPER over a single column and keeps any
+// other index expression verbatim.
+func parseExpression(elem string) trenovaorm.Expression {
+	for _, fn := range []string{"lower", "upper"} {
+		if !strings.HasPrefix(elem, fn+"(") || !strings.HasSuffix(elem, ")") {
+			continue
+		}
+		arg := elem[len(fn)+1 : len(elem)-1]
+		if i := strings.LastIndex(arg, "::"); i >= 0 {
+			arg = arg[:i]
+		}
+		arg = strings.TrimSuffix(strings.TrimPrefix(arg, "("), ")")
+		column, ok := unquoteIdentifier(arg)
+		if !ok {
+			break
+		}
+		if fn == "lower" {
+			return trenovaorm.Lower{Column: column}
+		}
+		return trenovaorm.Upper{Column: column}
+	}
+	return rawExpression(elem)
+}
+
+// rawExpression is an index expression kept exactly as PostgreSQL reports it.
+type rawExpression string
+
+func (r rawExpression) Expression() string {
+	return string(r)
+}
+
+func (r rawExpression) ColumnNames() []string {
+	return nil
+}
+
+// unquoteIdentifier returns the identifier named by s, or false if s is not a
+// plain or double-quoted identifier.
+func unquoteIdentifier(s string) (string, bool) {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return strings.ReplaceAll(s[1:len(s)-1], `""`, `"`), true
+	}
+	if s == "" {
+		return "", false
+	}
+	for i, r := range s {
+		valid := r == '_' || (r >= 'a' && r <= 'z') || (i > 0 && ((r >= '0' && r <= '9') || r == '$'))
+		if !valid {
+			return "", false
+		}
+	}
+	return s, true
+}
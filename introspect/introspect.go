@@ -0,0 +1,372 @@
+// Package introspect reads a live PostgreSQL catalog and reconstructs the
+// trenovaorm models that describe it, so deployed schemas can be compiled and
+// diffed against the models defined in code.
+package introspect
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+
+	trenovaorm "github.com/emoss08/trenova-orm"
+)
+
+// DefaultSchema is the PostgreSQL schema inspected when none is given.
+const DefaultSchema = "public"
+
+// Queryer is the subset of *sql.DB and *sql.Tx used to read the catalog.
+type Queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// Model is a trenovaorm.Model reconstructed from the database catalog.
+type Model struct {
+	name         string
+	schema       string
+	fields       []trenovaorm.Field
+	indexes      []trenovaorm.Index
+	constraints  []trenovaorm.TableConstraint
+	options      trenovaorm.TableOptions
+	partitioning trenovaorm.Partitioning
+}
+
+// TableName returns the name of the inspected table.
+func (m *Model) TableName() string {
+	return m.name
+}
+
+// SchemaName returns the schema of the inspected table, as reported by TableSchema.
+func (m *Model) SchemaName() string {
+	return m.schema
+}
+
+// Fields returns the fields reconstructed from the table's columns.
+func (m *Model) Fields() []trenovaorm.Field {
+	return m.fields
+}
+
+// Indexes returns the indexes that are not expressed by a field-level flag.
+func (m *Model) Indexes() []trenovaorm.Index {
+	return m.indexes
+}
+
+// Constraints returns the composite primary key, multi-column unique,
+// multi-column foreign key, check and exclusion constraints of the table.
+func (m *Model) Constraints() []trenovaorm.TableConstraint {
+	return m.constraints
+}
+
+// TableComment returns the comment on the table.
+func (m *Model) TableComment() string {
+	return m.options.Comment
+}
+
+// StorageParameters returns the storage parameters set on the table.
+func (m *Model) StorageParameters() map[string]string {
+	return m.options.Storage
+}
+
+// Tablespace returns the tablespace of the table, or "" for the default one.
+func (m *Model) Tablespace() string {
+	return m.options.Tablespace
+}
+
+// Unlogged reports whether the table is unlogged.
+func (m *Model) Unlogged() bool {
+	return m.options.Unlogged
+}
+
+// Partitioning returns the partition key and partitions of a partitioned table.
+func (m *Model) Partitioning() trenovaorm.Partitioning {
+	return m.partitioning
+}
+
+// Mixins returns no mixins; inspected fields are always listed directly.
+func (m *Model) Mixins() []trenovaorm.Mixin {
+	return nil
+}
+
+// Inspect reads the tables of the given PostgreSQL schema and returns one model per table.
+func Inspect(ctx context.Context, db Queryer, schema string) ([]trenovaorm.Model, error) {
+	if schema == "" {
+		schema = DefaultSchema
+	}
+
+	c, err := loadCatalog(ctx, db, schema)
+	if err != nil {
+		return nil, err
+	}
+	return c.models(), nil
+}
+
+// Compile inspects the given PostgreSQL schemas, or DefaultSchema if none is
+// given, and compiles their tables. The schemas their foreign keys reference
+// are inspected and compiled with them, so that foreign keys to tables of
+// other schemas resolve, and are left out of the result.
+func Compile(ctx context.Context, db Queryer, schemas ...string) (*trenovaorm.Schema, error) {
+	if len(schemas) == 0 {
+		schemas = []string{DefaultSchema}
+	}
+
+	var models []trenovaorm.Model
+	var tableSchemas []string
+	seen := map[string]bool{}
+	var pending []string
+	for _, schema := range schemas {
+		if schema == "" {
+			schema = DefaultSchema
+		}
+		if !seen[schema] {
+			seen[schema] = true
+			pending = append(pending, schema)
+			tableSchemas = append(tableSchemas, TableSchema(schema))
+		}
+	}
+	for ; len(pending) > 0; pending = pending[1:] {
+		c, err := loadCatalog(ctx, db, pending[0])
+		if err != nil {
+			return nil, err
+		}
+		models = append(models, c.models()...)
+		for _, con := range c.constraints {
+			if con.RefSchema != "" && !seen[con.RefSchema] {
+				seen[con.RefSchema] = true
+				pending = append(pending, con.RefSchema)
+			}
+		}
+	}
+
+	compiled, err := trenovaorm.Compile(models...)
+	if err != nil {
+		return nil, err
+	}
+	return compiled.Subset(tableSchemas...), nil
+}
+
+// models returns one model per table of the catalog.
+func (c *catalog) models() []trenovaorm.Model {
+	models := make([]trenovaorm.Model, 0, len(c.tables))
+	for _, table := range c.tables {
+		model := c.buildModel(table)
+		model.schema = TableSchema(c.schema)
+		models = append(models, model)
+	}
+	return models
+}
+
+// TableSchema returns the schema name inspected models report for the tables
+// of the given PostgreSQL schema. The tables of the public schema are left
+// unqualified unless trenovaorm.DefaultSchemaName is set, so that they
+// compare equal to models that declare no schema.
+func TableSchema(schema string) string {
+	if schema == "" {
+		schema = DefaultSchema
+	}
+	if schema == DefaultSchema && trenovaorm.DefaultSchemaName == "" {
+		return ""
+	}
+	return schema
+}
+
+// Schemas returns the PostgreSQL schemas of the tables, views and enum types
+// of a compiled schema, in order of first use. Unqualified names map to
+// DefaultSchema, the reverse of TableSchema.
+func Schemas(schema *trenovaorm.Schema) []string {
+	var names []string
+	unqualified := schema.Subset("")
+	if len(unqualified.Tables) > 0 || len(unqualified.Views) > 0 || len(unqualified.Enums) > 0 {
+		names = append(names, DefaultSchema)
+	}
+	for _, name := range schema.SchemaNames() {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Drift inspects the database and returns the statements that would migrate it to the given models.
+// An empty result means the deployed schema matches the models. Models of
+// tables in other schemas are ignored, and views are compared as described by
+// WithViews.
+func Drift(ctx context.Context, db Queryer, schema string, models ...trenovaorm.Model) ([]trenovaorm.Statement, error) {
+	from, err := Compile(ctx, db, schema)
+	if err != nil {
+		return nil, err
+	}
+	to, err := trenovaorm.Compile(models...)
+	if err != nil {
+		return nil, err
+	}
+
+	target := to.Subset(TableSchema(schema))
+	return trenovaorm.Diff(WithViews(from, target), target)
+}
+
+// WithViews returns a copy of the inspected schema from holding the views of
+// the schema to. Views are not inspected; assuming the deployed views match
+// the models lets Diff recreate the views that read changed tables, which
+// PostgreSQL would otherwise refuse to alter, without recreating the others.
+// Views added to the models are therefore not detected, and views removed
+// from them are not dropped.
+func WithViews(from, to *trenovaorm.Schema) *trenovaorm.Schema {
+	schema := *from
+	schema.Views = to.Views
+	return &schema
+}
+
+// buildModel reconstructs the model for a single table.
+func (c *catalog) buildModel(row tableRow) *Model {
+	table := row.Name
+	model := &Model{name: table, options: trenovaorm.TableOptions{
+		Comment:    row.Comment,
+		Storage:    parseOptions(row.Options),
+		Tablespace: row.Tablespace,
+		Unlogged:   row.Unlogged,
+	}}
+	model.partitioning = c.partitioning(row)
+
+	meta := make(map[string]*columnMeta)
+	metaFor := func(column string) *columnMeta {
+		if meta[column] == nil {
+			meta[column] = &columnMeta{}
+		}
+		return meta[column]
+	}
+
+	// Indexes backing primary key, unique and exclusion constraints are
+	// expressed by field flags or table constraints rather than as model indexes.
+	backing := make(map[string]bool)
+	for i, con := range c.constraints {
+		if con.Table != table {
+			continue
+		}
+		columns := strings.Split(con.Columns, ",")
+		switch con.Type {
+		case "p":
+			backing[con.Name] = true
+			if len(columns) == 1 {
+				metaFor(columns[0]).primaryKey = true
+			} else {
+				model.constraints = append(model.constraints, trenovaorm.PrimaryKey{Name: con.Name, Columns: columns})
+			}
+		case "u":
+			backing[con.Name] = true
+			if len(columns) == 1 {
+				metaFor(columns[0]).unique = true
+			} else {
+				model.constraints = append(model.constraints, trenovaorm.Unique{Name: con.Name, Columns: columns})
+			}
+		case "f":
+			if len(columns) == 1 {
+				metaFor(columns[0]).foreignKey = &c.constraints[i]
+			} else {
+				model.constraints = append(model.constraints, trenovaorm.ForeignKey{
+					Name:             con.Name,
+					Columns:          columns,
+					ReferenceTable:   con.RefTable,
+					ReferenceColumns: strings.Split(con.RefColumns, ","),
+					Annotations:      annotation(con),
+				})
+			}
+		case "c":
+			model.constraints = append(model.constraints, checkConstraint(con))
+		case "x":
+			backing[con.Name] = true
+			model.constraints = append(model.constraints, excludeConstraint(con))
+		}
+	}
+
+	for _, com := range c.comments {
+		if com.Table == table {
+			metaFor(com.Column).comment = com.Comment
+		}
+	}
+
+	for _, col := range c.columns {
+		if col.Table != table {
+			continue
+		}
+		m := metaFor(col.Name)
+		refType := ""
+		if m.foreignKey != nil && m.foreignKey.RefSchema == c.schema {
+			refType = c.goType(m.foreignKey.RefTable, m.foreignKey.RefColumns)
+		}
+		field := buildField(col, *m, refType)
+		if col.Generated != "" {
+			field = &trenovaorm.GeneratedField{Field: field, Expression: trenovaorm.Raw{SQL: col.Generated}}
+		}
+		model.fields = append(model.fields, field)
+	}
+
+	for _, idx := range c.indexes {
+		if idx.Table != table || backing[idx.Name] {
+			continue
+		}
+		def, ok := parseIndexDefinition(idx.Definition)
+		if !ok {
+			continue
+		}
+		if model.matchFieldIndex(idx.Name, def) {
+			continue
+		}
+		model.indexes = append(model.indexes, def.index(idx.Name))
+	}
+
+	return model
+}
+
+// parseOptions splits reloptions joined by commas into storage parameters.
+func parseOptions(options string) map[string]string {
+	if options == "" {
+		return nil
+	}
+	params := make(map[string]string)
+	for _, option := range strings.Split(options, ",") {
+		name, value, _ := strings.Cut(option, "=")
+		params[name] = value
+	}
+	return params
+}
+
+// goType returns the non-pointer Go type of the given column.
+func (c *catalog) goType(table, column string) string {
+	for _, col := range c.columns {
+		if col.Table == table && col.Name == column {
+			return strings.TrimPrefix(buildField(col, columnMeta{}, "").GoType(), "*")
+		}
+	}
+	return ""
+}
+
+// matchFieldIndex reports whether the index is the one a field emits through its
+// Index flag, and sets that flag if so.
+func (m *Model) matchFieldIndex(name string, def indexDefinition) bool {
+	if len(def.elements) != 1 || def.method != "btree" || len(def.include) > 0 || len(def.with) > 0 || def.where != "" {
+		return false
+	}
+
+	column, ok := unquoteIdentifier(def.elements[0])
+	if !ok {
+		return false
+	}
+
+	for _, field := range m.fields {
+		if field.Name() != column || !setIndex(field, true) {
+			continue
+		}
+		indexer, ok := field.(interface {
+			IndexSQL(tableName string) string
+			IndexName(tableName string) string
+		})
+		if ok {
+			sql := indexer.IndexSQL(m.name)
+			if indexer.IndexName(m.name) == name && strings.HasPrefix(sql, "CREATE UNIQUE") == def.unique {
+				return true
+			}
+		}
+		setIndex(field, false)
+	}
+	return false
+}
@@ -0,0 +1,672 @@
+package introspect
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	trenovaorm "github.com/emoss08/trenova-orm"
+)
+
+// fakeDriver serves canned catalog rows keyed by query text. The rows of
+// schemas other than public are keyed by schema first.
+type fakeDriver struct {
+	results map[string][][]driver.Value
+	schemas map[string]map[string][][]driver.Value
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) {
+	return &fakeConn{driver: d}, nil
+}
+
+type fakeConn struct {
+	driver *fakeDriver
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepare not supported")
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions not supported")
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	results := c.driver.results
+	if len(args) > 0 && args[0].Value != DefaultSchema {
+		results = c.driver.schemas[args[0].Value.(string)]
+	}
+	rows, ok := results[query]
+	if !ok {
+		return nil, errors.New("unexpected query")
+	}
+	return &fakeRows{rows: rows}, nil
+}
+
+type fakeRows struct {
+	rows [][]driver.Value
+	pos  int
+}
+
+func (r *fakeRows) Columns() []string {
+	if len(r.rows) == 0 {
+		return []string{"column"}
+	}
+	return make([]string, len(r.rows[0]))
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.pos])
+	r.pos++
+	return nil
+}
+
+func openFake(t *testing.T) *sql.DB {
+	t.Helper()
+	drv := &fakeDriver{results: map[string][][]driver.Value{
+		tablesQuery: {
+			{"events", "", "fillfactor=70,autovacuum_enabled=off", "", true, ""},
+			{"roles", "", "", "", false, ""},
+			{"user_roles", "", "", "", false, ""},
+			{"users", "Registered users", "", "fast_ssd", false, ""},
+		},
+		partitionsQuery: {},
+		columnsQuery: {
+			{"events", "id", "bigint", "int8", nil, int64(64), int64(0), nil, "NO", nil, "YES", "ALWAYS", "1000", "1", ""},
+			{"events", "sequence", "integer", "int4", nil, int64(32), int64(0), nil, "NO", "nextval('events_sequence_seq'::regclass)", "NO", "", nil, nil, ""},
+			{"events", "user_id", "uuid", "uuid", nil, nil, nil, nil, "YES", nil, "NO", "", nil, nil, ""},
+			{"events", "role_id", "uuid", "uuid", nil, nil, nil, nil, "YES", nil, "NO", "", nil, nil, ""},
+			{"events", "attempts", "smallint", "int2", nil, int64(16), int64(0), nil, "NO", "3", "NO", "", nil, nil, ""},
+			{"roles", "id", "uuid", "uuid", nil, nil, nil, nil, "NO", "uuid_generate_v4()", "NO", "", nil, nil, ""},
+			{"user_roles", "user_id", "uuid", "uuid", nil, nil, nil, nil, "NO", nil, "NO", "", nil, nil, ""},
+			{"user_roles", "role_id", "uuid", "uuid", nil, nil, nil, nil, "NO", nil, "NO", "", nil, nil, ""},
+			{"user_roles", "granted_by", "uuid", "uuid", nil, nil, nil, nil, "NO", nil, "NO", "", nil, nil, ""},
+			{"users", "id", "uuid", "uuid", nil, nil, nil, nil, "NO", "uuid_generate_v4()", "NO", "", nil, nil, ""},
+			{"users", "email", "character varying", "varchar", int64(255), nil, nil, nil, "NO", nil, "NO", "", nil, nil, ""},
+			{"users", "bio", "text", "text", nil, nil, nil, nil, "YES", nil, "NO", "", nil, nil, ""},
+			{"users", "is_active", "boolean", "bool", nil, nil, nil, nil, "NO", "true", "NO", "", nil, nil, ""},
+			{"users", "rating", "numeric", "numeric", nil, int64(19), int64(2), nil, "YES", "19.00", "NO", "", nil, nil, ""},
+			{"users", "status", "character varying", "varchar", int64(20), nil, nil, nil, "NO", "'active'::character varying", "NO", "", nil, nil, ""},
+			{"users", "role_id", "uuid", "uuid", nil, nil, nil, nil, "NO", nil, "NO", "", nil, nil, ""},
+			{"users", "priority", "USER-DEFINED", "priority_level", nil, nil, nil, nil, "NO", "'normal'::priority_level", "NO", "", nil, nil, ""},
+			{"users", "tags", "ARRAY", "_text", nil, nil, nil, nil, "NO", "'{}'::text[]", "NO", "", nil, nil, ""},
+			{"users", "priorities", "ARRAY", "_priority_level", nil, nil, nil, nil, "YES", "'{low,high}'::priority_level[]", "NO", "", nil, nil, ""},
+			{"users", "created_at", "date", "date", nil, nil, nil, int64(0), "NO", "CURRENT_TIMESTAMP", "NO", "", nil, nil, ""},
+			{"users", "last_seen_at", "timestamp with time zone", "timestamptz", nil, nil, nil, int64(3), "YES", nil, "NO", "", nil, nil, ""},
+			{"users", "session_timeout", "interval", "interval", nil, nil, nil, int64(6), "NO", "'01:30:00'::interval", "NO", "", nil, nil, ""},
+			{"users", "search", "tsvector", "tsvector", nil, nil, nil, nil, "YES", nil, "NO", "", nil, nil, "to_tsvector('english'::regconfig, bio)"},
+		},
+		enumsQuery: {{"priority_level", "low"}, {"priority_level", "normal"}, {"priority_level", "high"}},
+		constraintsQuery: {
+			{"events", "events_pkey", "p", "id", "", "", "", " ", " ", " ", false, false, "PRIMARY KEY (id)"},
+			{"events", "events_user_id_fkey", "f", "user_id", "public", "users", "id", "a", "a", "s", false, false, "FOREIGN KEY (user_id) REFERENCES users(id)"},
+			{"events", "events_user_id_role_id_fkey", "f", "user_id,role_id", "public", "user_roles", "user_id,role_id", "a", "a", "f", false, false,
+				"FOREIGN KEY (user_id, role_id) REFERENCES user_roles(user_id, role_id) MATCH FULL"},
+			{"roles", "roles_pkey", "p", "id", "", "", "", " ", " ", " ", false, false, "PRIMARY KEY (id)"},
+			{"user_roles", "user_roles_granter_fkey", "f", "granted_by", "public", "users", "id", "a", "a", "s", true, true,
+				"FOREIGN KEY (granted_by) REFERENCES users(id) DEFERRABLE INITIALLY DEFERRED"},
+			{"user_roles", "user_roles_pkey", "p", "user_id,role_id", "", "", "", " ", " ", " ", false, false, "PRIMARY KEY (user_id, role_id)"},
+			{"user_roles", "user_roles_role_id_granted_by_excl", "x", "role_id,granted_by", "", "", "", " ", " ", " ", false, false,
+				"EXCLUDE USING gist (role_id WITH =, granted_by WITH =) WHERE ((role_id <> granted_by))"},
+			{"user_roles", "user_roles_role_id_granted_by_key", "u", "role_id,granted_by", "", "", "", " ", " ", " ", false, false, "UNIQUE (role_id, granted_by)"},
+			{"users", "users_email_key", "u", "email", "", "", "", " ", " ", " ", false, false, "UNIQUE (email)"},
+			{"users", "users_pkey", "p", "id", "", "", "", " ", " ", " ", false, false, "PRIMARY KEY (id)"},
+			{"users", "users_rating_check", "c", "rating", "", "", "", " ", " ", " ", false, false, "CHECK ((rating >= (0)::numeric))"},
+			{"users", "users_role_id_fkey", "f", "role_id", "public", "roles", "id", "c", "a", "s", false, false, "FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE"},
+		},
+		indexesQuery: {
+			{"roles", "roles_pkey", "CREATE UNIQUE INDEX roles_pkey ON public.roles USING btree (id)"},
+			{"user_roles", "user_roles_pkey", "CREATE UNIQUE INDEX user_roles_pkey ON public.user_roles USING btree (user_id, role_id)"},
+			{"user_roles", "user_roles_role_id_granted_by_excl",
+				"CREATE INDEX user_roles_role_id_granted_by_excl ON public.user_roles USING gist (role_id, granted_by) WHERE (role_id <> granted_by)"},
+			{"user_roles", "user_roles_role_id_granted_by_key", "CREATE UNIQUE INDEX user_roles_role_id_granted_by_key ON public.user_roles USING btree (role_id, granted_by)"},
+			{"users", "idx_users_bio", "CREATE INDEX idx_users_bio ON public.users USING btree (bio)"},
+			{"users", "users_email_key", "CREATE UNIQUE INDEX users_email_key ON public.users USING btree (email)"},
+			{"users", "users_lower_email_idx", "CREATE UNIQUE INDEX users_lower_email_idx ON public.users USING btree (status, lower((email)::text))"},
+			{"users", "users_pkey", "CREATE UNIQUE INDEX users_pkey ON public.users USING btree (id)"},
+		},
+		commentsQuery: {
+			{"users", "email", "Email address of the user"},
+		},
+	}}
+
+	return openDriver(t, drv)
+}
+
+func openDriver(t *testing.T, drv *fakeDriver) *sql.DB {
+	t.Helper()
+	name := "introspect-fake-" + t.Name()
+	sql.Register(name, drv)
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func expectedModels() []trenovaorm.Model {
+	return []trenovaorm.Model{
+		&Model{
+			name: "events",
+			fields: []trenovaorm.Field{
+				&trenovaorm.BigIntegerField{
+					ColumnName: "id",
+					PrimaryKey: true,
+					Identity:   trenovaorm.Identity{Generation: trenovaorm.IdentityAlways, Start: 1000},
+				},
+				&trenovaorm.IntegerField{ColumnName: "sequence", Serial: true},
+				&trenovaorm.ForeignKeyField{
+					ColumnName:     "user_id",
+					ReferenceTable: "users",
+					ReferenceField: "id",
+					Nullable:       true,
+					CustomType:     "uuid",
+					ReferencedType: "uuid.UUID",
+				},
+				&trenovaorm.UUIDField{ColumnName: "role_id", Nullable: true},
+				&trenovaorm.SmallIntegerField{ColumnName: "attempts", Default: 3},
+			},
+			constraints: []trenovaorm.TableConstraint{
+				trenovaorm.ForeignKey{
+					Columns:          []string{"user_id", "role_id"},
+					ReferenceTable:   "user_roles",
+					ReferenceColumns: []string{"user_id", "role_id"},
+					Annotations:      trenovaorm.Annotation{Match: trenovaorm.MatchFull},
+				},
+			},
+			options: trenovaorm.TableOptions{
+				Storage:  map[string]string{"fillfactor": "70", "autovacuum_enabled": "off"},
+				Unlogged: true,
+			},
+		},
+		&Model{
+			name: "roles",
+			fields: []trenovaorm.Field{
+				&trenovaorm.UUIDField{ColumnName: "id", Default: trenovaorm.UUIDGenerateV4, PrimaryKey: true},
+			},
+		},
+		&Model{
+			name: "user_roles",
+			fields: []trenovaorm.Field{
+				&trenovaorm.UUIDField{ColumnName: "user_id"},
+				&trenovaorm.UUIDField{ColumnName: "role_id"},
+				&trenovaorm.ForeignKeyField{
+					ColumnName:     "granted_by",
+					ReferenceTable: "users",
+					ReferenceField: "id",
+					CustomType:     "uuid",
+					ReferencedType: "uuid.UUID",
+					ForeignKeyName: "user_roles_granter_fkey",
+					Annotations:    trenovaorm.Annotation{Deferrable: true, InitiallyDeferred: true},
+				},
+			},
+			constraints: []trenovaorm.TableConstraint{
+				trenovaorm.PrimaryKey{Columns: []string{"user_id", "role_id"}},
+				trenovaorm.Exclude{
+					Elements: []trenovaorm.ExcludeElement{{Column: "role_id", Operator: "="}, {Column: "granted_by", Operator: "="}},
+					Where:    "role_id <> granted_by",
+				},
+				trenovaorm.Unique{Columns: []string{"role_id", "granted_by"}},
+			},
+		},
+		&Model{
+			name: "users",
+			fields: []trenovaorm.Field{
+				&trenovaorm.UUIDField{ColumnName: "id", Default: trenovaorm.UUIDGenerateV4, PrimaryKey: true},
+				&trenovaorm.CharField{ColumnName: "email", MaxLength: 255, Unique: true, Comment: "Email address of the user"},
+				&trenovaorm.TextField{ColumnName: "bio", Nullable: true, Index: true},
+				&trenovaorm.BooleanField{ColumnName: "is_active", Default: true},
+				&trenovaorm.NumericField{ColumnName: "rating", Precision: 19, Scale: 2, Nullable: true, Default: 19},
+				&trenovaorm.CharField{ColumnName: "status", MaxLength: 20, Default: "active"},
+				&trenovaorm.ForeignKeyField{
+					ColumnName:     "role_id",
+					ReferenceTable: "roles",
+					ReferenceField: "id",
+					CustomType:     "uuid",
+					ReferencedType: "uuid.UUID",
+					Annotations:    trenovaorm.Annotation{OnDelete: trenovaorm.OnDeleteCascade},
+				},
+				&trenovaorm.EnumField{
+					ColumnName: "priority",
+					TypeName:   "priority_level",
+					Values:     []string{"low", "normal", "high"},
+					Default:    "normal",
+				},
+				&trenovaorm.ArrayField{ColumnName: "tags", Element: &trenovaorm.TextField{}, Default: []string{}},
+				&trenovaorm.ArrayField{
+					ColumnName: "priorities",
+					Element:    &trenovaorm.EnumField{TypeName: "priority_level", Values: []string{"low", "normal", "high"}},
+					Nullable:   true,
+					Default:    []string{"low", "high"},
+				},
+				&trenovaorm.DateField{ColumnName: "created_at", Default: trenovaorm.CurrentTimestamp},
+				&trenovaorm.TimestampField{ColumnName: "last_seen_at", WithTimeZone: true, Precision: 3, Nullable: true},
+				&trenovaorm.IntervalField{ColumnName: "session_timeout", Default: 90 * time.Minute},
+				&trenovaorm.GeneratedField{
+					Field:      &trenovaorm.TextField{ColumnName: "search", CustomType: "TSVECTOR", Nullable: true},
+					Expression: trenovaorm.Raw{SQL: "to_tsvector('english'::regconfig, bio)"},
+				},
+			},
+			indexes: []trenovaorm.Index{
+				{
+					Name:        "users_lower_email_idx",
+					Columns:     []string{"status"},
+					Expressions: []trenovaorm.Expression{trenovaorm.Lower{Column: "email"}},
+					Unique:      true,
+				},
+			},
+			constraints: []trenovaorm.TableConstraint{
+				trenovaorm.Check{Expression: "rating >= (0)::numeric", Columns: []string{"rating"}},
+			},
+			options: trenovaorm.TableOptions{Comment: "Registered users", Tablespace: "fast_ssd"},
+		},
+	}
+}
+
+func TestInspect(t *testing.T) {
+	db := openFake(t)
+
+	got, err := Inspect(context.Background(), db, "")
+	if err != nil {
+		t.Fatalf("Inspect() error = %v", err)
+	}
+
+	want := expectedModels()
+	if len(got) != len(want) {
+		t.Fatalf("Inspect() returned %d models, want %d", len(got), len(want))
+	}
+
+	for i := range want {
+		gotFields := trenovaorm.ModelFields(got[i])
+		wantFields := trenovaorm.ModelFields(want[i])
+		if len(gotFields) != len(wantFields) {
+			t.Fatalf("%s: got %d fields, want %d", want[i].TableName(), len(gotFields), len(wantFields))
+		}
+		for j := range wantFields {
+			if g, w := gotFields[j].Definition(), wantFields[j].Definition(); g != w {
+				t.Errorf("%s field %d Definition() = %v, want %v", want[i].TableName(), j, g, w)
+			}
+			if g, w := gotFields[j].GoType(), wantFields[j].GoType(); g != w {
+				t.Errorf("%s field %d GoType() = %v, want %v", want[i].TableName(), j, g, w)
+			}
+		}
+	}
+
+	for i := range want {
+		gotConstraints := trenovaorm.ModelConstraints(got[i])
+		wantConstraints := trenovaorm.ModelConstraints(want[i])
+		if len(gotConstraints) != len(wantConstraints) {
+			t.Fatalf("%s: got %d constraints, want %d", want[i].TableName(), len(gotConstraints), len(wantConstraints))
+		}
+		for j := range wantConstraints {
+			table := want[i].TableName()
+			if g, w := gotConstraints[j].ConstraintName(table), wantConstraints[j].ConstraintName(table); g != w {
+				t.Errorf("%s constraint %d ConstraintName() = %v, want %v", table, j, g, w)
+			}
+			if g, w := gotConstraints[j].Definition(), wantConstraints[j].Definition(); g != w {
+				t.Errorf("%s constraint %d Definition() = %v, want %v", table, j, g, w)
+			}
+		}
+	}
+	for i := range want {
+		if g, w := trenovaorm.ModelTableOptions(got[i]), trenovaorm.ModelTableOptions(want[i]); !reflect.DeepEqual(g, w) {
+			t.Errorf("%s table options = %+v, want %+v", want[i].TableName(), g, w)
+		}
+	}
+	if got := trenovaorm.ModelPrimaryKey(got[2]); len(got) != 2 || got[0] != "user_id" || got[1] != "role_id" {
+		t.Errorf("user_roles primary key = %v, want [user_id role_id]", got)
+	}
+
+	if idx := got[2].Indexes(); len(idx) != 0 {
+		t.Errorf("user_roles indexes = %v, want none; the exclusion constraint's index is not a model index", idx)
+	}
+
+	idx := got[3].Indexes()
+	if len(idx) != 1 {
+		t.Fatalf("users indexes = %v, want 1 index", idx)
+	}
+	sql, err := idx[0].SQL("users")
+	if err != nil {
+		t.Fatalf("Index.SQL() error = %v", err)
+	}
+	wantSQL := `CREATE UNIQUE INDEX IF NOT EXISTS "users_lower_email_idx" ON "users" ("status", LOWER("email"));`
+	if sql != wantSQL {
+		t.Errorf("Index.SQL() = %v, want %v", sql, wantSQL)
+	}
+}
+
+func TestDrift(t *testing.T) {
+	db := openFake(t)
+
+	stmts, err := Drift(context.Background(), db, DefaultSchema, expectedModels()...)
+	if err != nil {
+		t.Fatalf("Drift() error = %v", err)
+	}
+	if len(stmts) != 0 {
+		t.Errorf("Drift() = %v, want no statements", stmts)
+	}
+
+	models := expectedModels()
+	users := models[3].(*Model)
+	users.fields = append(users.fields, &trenovaorm.TextField{ColumnName: "notes", Nullable: true})
+
+	stmts, err = Drift(context.Background(), db, DefaultSchema, models...)
+	if err != nil {
+		t.Fatalf("Drift() error = %v", err)
+	}
+	want := `ALTER TABLE "users" ADD COLUMN "notes" TEXT;`
+	if len(stmts) != 1 || stmts[0].SQL != want {
+		t.Errorf("Drift() = %v, want [%v]", stmts, want)
+	}
+}
+
+func TestDriftCrossSchemaForeignKey(t *testing.T) {
+	empty := map[string][][]driver.Value{
+		partitionsQuery: {},
+		enumsQuery:      {},
+		indexesQuery:    {},
+		commentsQuery:   {},
+	}
+	catalogOf := func(tables, columns, constraints [][]driver.Value) map[string][][]driver.Value {
+		results := map[string][][]driver.Value{tablesQuery: tables, columnsQuery: columns, constraintsQuery: constraints}
+		for query, rows := range empty {
+			results[query] = rows
+		}
+		return results
+	}
+	db := openDriver(t, &fakeDriver{
+		results: catalogOf(
+			[][]driver.Value{{"orders", "", "", "", false, ""}},
+			[][]driver.Value{
+				{"orders", "id", "uuid", "uuid", nil, nil, nil, nil, "NO", nil, "NO", "", nil, nil, ""},
+				{"orders", "invoice_id", "uuid", "uuid", nil, nil, nil, nil, "NO", nil, "NO", "", nil, nil, ""},
+			},
+			[][]driver.Value{
+				{"orders", "orders_invoice_id_fkey", "f", "invoice_id", "billing", "invoices", "id", "a", "a", "s", false, false,
+					"FOREIGN KEY (invoice_id) REFERENCES billing.invoices(id)"},
+				{"orders", "orders_pkey", "p", "id", "", "", "", " ", " ", " ", false, false, "PRIMARY KEY (id)"},
+			},
+		),
+		schemas: map[string]map[string][][]driver.Value{
+			"billing": catalogOf(
+				[][]driver.Value{{"invoices", "", "", "", false, ""}},
+				[][]driver.Value{{"invoices", "id", "uuid", "uuid", nil, nil, nil, nil, "NO", nil, "NO", "", nil, nil, ""}},
+				[][]driver.Value{{"invoices", "invoices_pkey", "p", "id", "", "", "", " ", " ", " ", false, false, "PRIMARY KEY (id)"}},
+			),
+		},
+	})
+
+	models := []trenovaorm.Model{
+		&Model{
+			name: "orders",
+			fields: []trenovaorm.Field{
+				&trenovaorm.UUIDField{ColumnName: "id", PrimaryKey: true},
+				&trenovaorm.ForeignKeyField{
+					ColumnName:     "invoice_id",
+					ReferenceTable: "billing.invoices",
+					ReferenceField: "id",
+					CustomType:     "uuid",
+				},
+			},
+		},
+		&Model{
+			name:   "invoices",
+			schema: "billing",
+			fields: []trenovaorm.Field{&trenovaorm.UUIDField{ColumnName: "id", PrimaryKey: true}},
+		},
+	}
+
+	inspected, err := Inspect(context.Background(), db, DefaultSchema)
+	if err != nil {
+		t.Fatalf("Inspect() error = %v", err)
+	}
+	if fk := inspected[0].Fields()[1].(*trenovaorm.ForeignKeyField); fk.ReferenceTable != "billing.invoices" {
+		t.Errorf("ReferenceTable = %v, want billing.invoices", fk.ReferenceTable)
+	}
+
+	stmts, err := Drift(context.Background(), db, DefaultSchema, models...)
+	if err != nil {
+		t.Fatalf("Drift() error = %v", err)
+	}
+	if len(stmts) != 0 {
+		t.Errorf("Drift() = %v, want no statements", stmts)
+	}
+
+	stmts, err = Drift(context.Background(), db, "billing", models...)
+	if err != nil {
+		t.Fatalf("Drift() error = %v", err)
+	}
+	if len(stmts) != 0 {
+		t.Errorf("Drift() of billing = %v, want no statements", stmts)
+	}
+
+	to, err := trenovaorm.Compile(models...)
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	schemas := Schemas(to)
+	if !reflect.DeepEqual(schemas, []string{DefaultSchema, "billing"}) {
+		t.Errorf("Schemas() = %v, want [public billing]", schemas)
+	}
+	from, err := Compile(context.Background(), db, schemas...)
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	if from.Table("orders") == nil || from.Table("billing.invoices") == nil {
+		t.Errorf("Compile() of %v is missing a table", schemas)
+	}
+	stmts, err = trenovaorm.Diff(from, to)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	if len(stmts) != 0 {
+		t.Errorf("Diff() of %v = %v, want no statements", schemas, stmts)
+	}
+}
+
+func TestParseIndexDefinition(t *testing.T) {
+	tests := []struct {
+		name string
+		def  string
+		want string
+	}{
+		{
+			"Plain columns",
+			"CREATE INDEX users_a_b_idx ON public.users USING btree (a, b)",
+			`CREATE INDEX IF NOT EXISTS "users_a_b_idx" ON "users" ("a", "b");`,
+		},
+		{
+			"Quoted column",
+			`CREATE UNIQUE INDEX users_name_idx ON public.users USING btree ("Name")`,
+			`CREATE UNIQUE INDEX IF NOT EXISTS "users_name_idx" ON "users" ("Name");`,
+		},
+		{
+			"Gin index",
+			"CREATE INDEX users_metadata_idx ON public.users USING gin (metadata)",
+			`CREATE INDEX IF NOT EXISTS "users_metadata_idx" ON "users" USING gin ("metadata");`,
+		},
+		{
+			"Operator class",
+			"CREATE INDEX users_name_trgm_idx ON public.users USING gin (name gin_trgm_ops) WITH (fastupdate=off)",
+			`CREATE INDEX IF NOT EXISTS "users_name_trgm_idx" ON "users" USING gin ("name" gin_trgm_ops) WITH (fastupdate = off);`,
+		},
+		{
+			"Partial covering index",
+			"CREATE UNIQUE INDEX users_email_idx ON public.users USING btree (email) INCLUDE (name, status) WHERE (deleted_at IS NULL)",
+			`CREATE UNIQUE INDEX IF NOT EXISTS "users_email_idx" ON "users" ("email") INCLUDE ("name", "status") WHERE (deleted_at IS NULL);`,
+		},
+		{
+			"Sort order keeps key order",
+			"CREATE INDEX users_created_idx ON public.users USING btree (lower((email)::text), created_at DESC NULLS LAST)",
+			`CREATE INDEX IF NOT EXISTS "users_created_idx" ON "users" (LOWER("email"), "created_at" DESC NULLS LAST);`,
+		},
+		{
+			"Raw expression",
+			"CREATE INDEX users_expr_idx ON public.users USING btree (COALESCE(a, b))",
+			`CREATE INDEX IF NOT EXISTS "users_expr_idx" ON "users" (COALESCE(a, b));`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			def, ok := parseIndexDefinition(tt.def)
+			if !ok {
+				t.Fatalf("parseIndexDefinition(%q) failed", tt.def)
+			}
+			idx := def.index(indexName(tt.want))
+			got, err := idx.SQL("users")
+			if err != nil {
+				t.Fatalf("Index.SQL() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Index.SQL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePartitioning(t *testing.T) {
+	c := &catalog{partitions: []partitionRow{
+		{"events", "events_2024_01", "FOR VALUES FROM ('2024-01-01 00:00:00+00') TO ('2024-02-01 00:00:00+00')"},
+		{"events", "events_default", "DEFAULT"},
+		{"orders", "orders_eu", "FOR VALUES IN ('de', 'fr')"},
+	}}
+
+	got := c.partitioning(tableRow{Name: "events", PartitionKey: "RANGE (created_at)"})
+	want := trenovaorm.Partitioning{
+		Strategy: trenovaorm.PartitionRange,
+		Column:   "created_at",
+		Partitions: []trenovaorm.Partition{
+			{
+				Name: "events_2024_01",
+				From: trenovaorm.Raw{SQL: "'2024-01-01 00:00:00+00'"},
+				To:   trenovaorm.Raw{SQL: "'2024-02-01 00:00:00+00'"},
+			},
+			{Name: "events_default", Default: true},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("catalog.partitioning() = %+v, want %+v", got, want)
+	}
+
+	got = c.partitioning(tableRow{Name: "orders", PartitionKey: `LIST ("Region")`})
+	want = trenovaorm.Partitioning{
+		Strategy: trenovaorm.PartitionList,
+		Column:   "Region",
+		Partitions: []trenovaorm.Partition{
+			{Name: "orders_eu", Values: []any{trenovaorm.Raw{SQL: "'de'"}, trenovaorm.Raw{SQL: "'fr'"}}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("catalog.partitioning() = %+v, want %+v", got, want)
+	}
+
+	if got := c.partitioning(tableRow{Name: "users"}); !got.IsZero() {
+		t.Errorf("catalog.partitioning() of a plain table = %+v, want zero", got)
+	}
+}
+
+func TestParseConstraints(t *testing.T) {
+	tests := []struct {
+		name string
+		con  constraintRow
+		want string
+	}{
+		{
+			"Check",
+			constraintRow{Name: "c", Type: "c", Columns: "amount", Definition: "CHECK ((amount > (0)::numeric))"},
+			"CHECK (amount > (0)::numeric)",
+		},
+		{
+			"Check without columns",
+			constraintRow{Name: "c", Type: "c", Definition: "CHECK (false) NO INHERIT NOT VALID"},
+			"CHECK (false)",
+		},
+		{
+			"Exclude",
+			constraintRow{Name: "x", Type: "x", Columns: "room,during", Definition: `EXCLUDE USING gist (room WITH =, "During" WITH &&)`},
+			`EXCLUDE USING gist ("room" WITH =, "During" WITH &&)`,
+		},
+		{
+			"Exclude on an expression",
+			constraintRow{Name: "x", Type: "x", Definition: "EXCLUDE USING gist (tstzrange(starts_at, ends_at) WITH &&)"},
+			"EXCLUDE USING gist (tstzrange(starts_at, ends_at) WITH &&)",
+		},
+		{
+			"Exclude with a qualified operator",
+			constraintRow{Name: "x", Type: "x", Columns: "room", Definition: "EXCLUDE USING gist (room WITH OPERATOR(public.=))"},
+			"EXCLUDE USING gist (room WITH OPERATOR(public.=))",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &catalog{constraints: []constraintRow{tt.con}}
+			got := c.buildModel(tableRow{}).Constraints()
+			if len(got) != 1 {
+				t.Fatalf("Constraints() = %v, want 1 constraint", got)
+			}
+			if got[0].ConstraintName("") != tt.con.Name {
+				t.Errorf("ConstraintName() = %v, want %v", got[0].ConstraintName(""), tt.con.Name)
+			}
+			if def := got[0].Definition(); def != tt.want {
+				t.Errorf("Definition() = %v, want %v", def, tt.want)
+			}
+		})
+	}
+}
+
+func TestTableSchema(t *testing.T) {
+	tests := []struct {
+		schema        string
+		defaultSchema string
+		want          string
+	}{
+		{"", "", ""},
+		{"public", "", ""},
+		{"billing", "", "billing"},
+		{"public", "app", "public"},
+		{"app", "app", "app"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.schema+"/"+tt.defaultSchema, func(t *testing.T) {
+			trenovaorm.DefaultSchemaName = tt.defaultSchema
+			t.Cleanup(func() { trenovaorm.DefaultSchemaName = "" })
+			if got := TableSchema(tt.schema); got != tt.want {
+				t.Errorf("TableSchema(%q) = %v, want %v", tt.schema, got, tt.want)
+			}
+		})
+	}
+}
+
+// indexName extracts the quoted index name from a CREATE INDEX statement.
+func indexName(sql string) string {
+	start := strings.Index(sql, `"`)
+	if start < 0 {
+		return ""
+	}
+	end := strings.Index(sql[start+1:], `"`)
+	if end < 0 {
+		return ""
+	}
+	return sql[start+1 : start+1+end]
+}
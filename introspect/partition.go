@@ -0,0 +1,57 @@
+package introspect
+
+import (
+	"strings"
+
+	trenovaorm "github.com/emoss08/trenova-orm"
+)
+
+// partitioning reconstructs the partitioning of a table from its partition key
+// and the bounds of its partitions. Bound values are kept as PostgreSQL
+// reports them.
+func (c *catalog) partitioning(table tableRow) trenovaorm.Partitioning {
+	strategy, key, ok := strings.Cut(table.PartitionKey, " ")
+	if !ok {
+		return trenovaorm.Partitioning{}
+	}
+	columns, _, ok := splitList(strings.TrimSpace(key))
+	if !ok || len(columns) != 1 {
+		return trenovaorm.Partitioning{}
+	}
+	column, ok := unquoteIdentifier(columns[0])
+	if !ok {
+		return trenovaorm.Partitioning{}
+	}
+
+	p := trenovaorm.Partitioning{Strategy: trenovaorm.PartitionStrategy(strategy), Column: column}
+	for _, row := range c.partitions {
+		if row.Parent == table.Name {
+			p.Partitions = append(p.Partitions, parsePartition(row))
+		}
+	}
+	return p
+}
+
+// parsePartition parses a bound of the form DEFAULT, FOR VALUES IN (value, ...)
+// or FOR VALUES FROM (value) TO (value).
+func parsePartition(row partitionRow) trenovaorm.Partition {
+	part := trenovaorm.Partition{Name: row.Name}
+	bound := strings.TrimPrefix(row.Bound, "FOR VALUES ")
+
+	switch {
+	case row.Bound == "DEFAULT":
+		part.Default = true
+	case strings.HasPrefix(bound, "IN "):
+		values, _, _ := splitList(strings.TrimPrefix(bound, "IN "))
+		for _, v := range values {
+			part.Values = append(part.Values, trenovaorm.Raw{SQL: v})
+		}
+	case strings.HasPrefix(bound, "FROM "):
+		from, rest, _ := splitList(strings.TrimPrefix(bound, "FROM "))
+		to, _, _ := splitList(strings.TrimPrefix(strings.TrimSpace(rest), "TO "))
+		if len(from) == 1 && len(to) == 1 {
+			part.From, part.To = trenovaorm.Raw{SQL: from[0]}, trenovaorm.Raw{SQL: to[0]}
+		}
+	}
+	return part
+}
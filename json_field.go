@@ -24,7 +24,7 @@ func (f *JSONField) Definition() string {
 	if f.CustomType != "" {
 		typ = f.CustomType
 	}
-	def := fmt.Sprintf(`"%s" %s`, f.ColumnName, typ)
+	def := fmt.Sprintf("%s %s", QuoteIdentifier(f.ColumnName), typ)
 
 	if !f.Nullable {
 		def += " NOT NULL"
@@ -35,7 +35,7 @@ func (f *JSONField) Definition() string {
 	}
 
 	if f.Default != "" {
-		def += fmt.Sprintf(" DEFAULT '%s'", f.Default)
+		def += " DEFAULT " + QuoteLiteral(f.Default)
 	}
 
 	if len(f.Constraints) > 0 {
@@ -55,7 +55,7 @@ func (f *JSONField) CommentSQL(tableName string) string {
 	if f.Comment == "" {
 		return ""
 	}
-	return fmt.Sprintf(`COMMENT ON COLUMN "%s"."%s" IS '%s';`, tableName, f.ColumnName, f.Comment)
+	return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;", QuoteQualifiedName(tableName), QuoteIdentifier(f.ColumnName), QuoteLiteral(f.Comment))
 }
 
 // Validate checks if the field's configuration is valid.
@@ -83,6 +83,11 @@ func (f *JSONField) IndexSQL(tableName string) string {
 	if f.Unique {
 		indexType = "UNIQUE INDEX"
 	}
-	indexName := fmt.Sprintf("%s_%s_idx", tableName, f.ColumnName)
-	return fmt.Sprintf(`CREATE %s "%s" ON "%s" ("%s");`, indexType, indexName, tableName, f.ColumnName)
+	indexName := f.IndexName(tableName)
+	return fmt.Sprintf("CREATE %s %s ON %s (%s);", indexType, QuoteIdentifier(indexName), QuoteQualifiedName(tableName), QuoteIdentifier(f.ColumnName))
+}
+
+// IndexName returns the name of the index generated by IndexSQL.
+func (f *JSONField) IndexName(tableName string) string {
+	return fmt.Sprintf("%s_%s_idx", unqualifiedName(tableName), f.ColumnName)
 }
@@ -0,0 +1,314 @@
+// Package migrate writes versioned migration files from generated DDL plans and
+// applies them to a PostgreSQL database, recording each applied version in a
+// schema_migrations ledger.
+package migrate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	trenovaorm "github.com/emoss08/trenova-orm"
+)
+
+const (
+	upSuffix   = ".up.sql"
+	downSuffix = ".down.sql"
+)
+
+// noTransactionDirective starts the scripts of a migration that runs outside
+// a transaction.
+const noTransactionDirective = "-- trenova:no-transaction"
+
+// ErrNoChanges is returned by Generate when the two schemas are identical.
+var ErrNoChanges = errors.New("no schema changes to migrate")
+
+// Migration is a single numbered migration with its up and down SQL.
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string // SHA-256 of Up, used to detect edits to applied migrations
+
+	// NoTransaction reports whether the scripts run outside a transaction,
+	// as CREATE INDEX CONCURRENTLY requires. Each script then holds a single
+	// statement. It is set by a leading "-- trenova:no-transaction" line in
+	// the up script.
+	NoTransaction bool
+}
+
+// NewMigration builds a migration and computes its checksum.
+func NewMigration(version int64, name, up, down string) Migration {
+	return Migration{
+		Version:       version,
+		Name:          name,
+		Up:            up,
+		Down:          down,
+		Checksum:      checksum(up),
+		NoTransaction: noTransaction(up),
+	}
+}
+
+// noTransaction reports whether a script starts with the no-transaction directive.
+func noTransaction(script string) bool {
+	line, _, _ := strings.Cut(script, "\n")
+	return strings.TrimSpace(line) == noTransactionDirective
+}
+
+// checksum returns the hex-encoded SHA-256 of the migration SQL.
+func checksum(sql string) string {
+	sum := sha256.Sum256([]byte(sql))
+	return hex.EncodeToString(sum[:])
+}
+
+// fileName returns the base file name for the migration with the given suffix.
+func (m Migration) fileName(suffix string) string {
+	return fmt.Sprintf("%06d_%s%s", m.Version, m.Name, suffix)
+}
+
+// Load reads every migration in dir, sorted by version. Each version must have
+// an up file; the down file is optional.
+func Load(dir string) ([]Migration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+
+		var suffix string
+		switch {
+		case strings.HasSuffix(name, upSuffix):
+			suffix = upSuffix
+		case strings.HasSuffix(name, downSuffix):
+			suffix = downSuffix
+		default:
+			continue
+		}
+
+		version, label, err := parseFileName(strings.TrimSuffix(name, suffix))
+		if err != nil {
+			return nil, fmt.Errorf("migration file %s: %w", name, err)
+		}
+
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		mig := byVersion[version]
+		if mig == nil {
+			mig = &Migration{Version: version, Name: label}
+			byVersion[version] = mig
+		}
+		if mig.Name != label {
+			return nil, fmt.Errorf("migration version %d has conflicting names %s and %s", version, mig.Name, label)
+		}
+
+		if suffix == upSuffix {
+			mig.Up = string(content)
+			mig.Checksum = checksum(mig.Up)
+			mig.NoTransaction = noTransaction(mig.Up)
+		} else {
+			mig.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Checksum == "" {
+			return nil, fmt.Errorf("migration version %d has no up file", mig.Version)
+		}
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// parseFileName splits a file name such as 000001_create_users into its version and name.
+func parseFileName(base string) (int64, string, error) {
+	prefix, label, ok := strings.Cut(base, "_")
+	if !ok || label == "" {
+		return 0, "", errors.New("expected <version>_<name>")
+	}
+	version, err := strconv.ParseInt(prefix, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, "", fmt.Errorf("invalid version %q", prefix)
+	}
+	return version, label, nil
+}
+
+// Create writes the up and down files for a new migration into dir, numbered one
+// past the highest existing version. A Concurrent statement must be the only
+// statement of its script, and makes the migration run outside a transaction.
+func Create(dir, name string, up, down []trenovaorm.Statement) (Migration, error) {
+	concurrent := slices.ContainsFunc(up, isConcurrent) || slices.ContainsFunc(down, isConcurrent)
+	if concurrent && (len(up) > 1 || len(down) > 1) {
+		return Migration{}, errors.New("a statement building or dropping an index concurrently must be the only statement of its migration")
+	}
+
+	existing, err := Load(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return Migration{}, err
+	}
+
+	var version int64 = 1
+	if len(existing) > 0 {
+		version = existing[len(existing)-1].Version + 1
+	}
+
+	mig := NewMigration(version, sanitizeName(name), render(up, concurrent), render(down, concurrent))
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return Migration{}, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, mig.fileName(upSuffix)), []byte(mig.Up), 0o644); err != nil {
+		return Migration{}, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, mig.fileName(downSuffix)), []byte(mig.Down), 0o644); err != nil {
+		return Migration{}, err
+	}
+
+	return mig, nil
+}
+
+// Generate diffs the two schemas in both directions and writes the result as
+// new migrations, which it returns in version order. The statements building
+// or dropping an index concurrently cannot run in a transaction, so each is
+// written as a migration of its own: the drops before the other statements and
+// the creates after them.
+func Generate(dir, name string, from, to *trenovaorm.Schema) ([]Migration, error) {
+	up, err := trenovaorm.Diff(from, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(up) == 0 {
+		return nil, ErrNoChanges
+	}
+
+	down, err := trenovaorm.Diff(to, from)
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []Migration
+	for _, p := range split(name, up, down) {
+		mig, err := Create(dir, p.name, p.up, p.down)
+		if err != nil {
+			return migrations, err
+		}
+		migrations = append(migrations, mig)
+	}
+	return migrations, nil
+}
+
+// part is the statements of one of the migrations written by Generate.
+type part struct {
+	name     string
+	up, down []trenovaorm.Statement
+}
+
+// split separates the concurrent statements of a diff into parts of their
+// own. Each is reverted by the concurrent statement of down on the same index:
+// a concurrent build of an index is the inverse of its concurrent drop.
+func split(name string, up, down []trenovaorm.Statement) []part {
+	type key struct {
+		kind  trenovaorm.StatementKind
+		index string
+	}
+	inverse := make(map[key]trenovaorm.Statement)
+	rest := part{name: name}
+	for _, stmt := range down {
+		if stmt.Concurrent {
+			inverse[key{stmt.Kind, stmt.Index}] = stmt
+		} else {
+			rest.down = append(rest.down, stmt)
+		}
+	}
+
+	var drops, creates []part
+	for _, stmt := range up {
+		if !stmt.Concurrent {
+			rest.up = append(rest.up, stmt)
+			continue
+		}
+		p := part{name: name + " " + stmt.Index, up: []trenovaorm.Statement{stmt}}
+		kind := trenovaorm.StatementDropIndex
+		if stmt.Kind == trenovaorm.StatementDropIndex {
+			kind = trenovaorm.StatementCreateIndex
+		}
+		if inv, ok := inverse[key{kind, stmt.Index}]; ok {
+			p.down = []trenovaorm.Statement{inv}
+		}
+		if stmt.Kind == trenovaorm.StatementDropIndex {
+			drops = append(drops, p)
+		} else {
+			creates = append(creates, p)
+		}
+	}
+
+	parts := drops
+	if len(rest.up) > 0 || len(rest.down) > 0 {
+		parts = append(parts, rest)
+	}
+	return append(parts, creates...)
+}
+
+// isConcurrent reports whether a statement builds or drops an index concurrently.
+func isConcurrent(stmt trenovaorm.Statement) bool {
+	return stmt.Concurrent
+}
+
+// render joins statements into a migration file body, one statement per line,
+// after the no-transaction directive if the migration runs outside a
+// transaction.
+func render(stmts []trenovaorm.Statement, noTransaction bool) string {
+	var b strings.Builder
+	if noTransaction {
+		b.WriteString(noTransactionDirective)
+		b.WriteString("\n")
+	}
+	for _, stmt := range stmts {
+		b.WriteString(stmt.SQL)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// sanitizeName converts a free-form migration name into a lower snake_case file name part.
+func sanitizeName(name string) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			underscore = false
+			continue
+		}
+		if !underscore && b.Len() > 0 {
+			b.WriteRune('_')
+			underscore = true
+		}
+	}
+	result := strings.TrimSuffix(b.String(), "_")
+	if result == "" {
+		return "migration"
+	}
+	return result
+}
@@ -0,0 +1,117 @@
+package migrate
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	trenovaorm "github.com/emoss08/trenova-orm"
+)
+
+type testModel struct {
+	trenovaorm.BaseModel
+	fields  []trenovaorm.Field
+	indexes []trenovaorm.Index
+}
+
+func (m *testModel) TableName() string {
+	return "users"
+}
+
+func (m *testModel) Fields() []trenovaorm.Field {
+	return m.fields
+}
+
+func (m *testModel) Indexes() []trenovaorm.Index {
+	return m.indexes
+}
+
+func compile(t *testing.T, models ...trenovaorm.Model) *trenovaorm.Schema {
+	t.Helper()
+	schema, err := trenovaorm.Compile(models...)
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	return schema
+}
+
+func TestGenerateAndLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	empty := compile(t)
+	v1 := compile(t, &testModel{fields: []trenovaorm.Field{&trenovaorm.UUIDField{ColumnName: "id"}}})
+	v2 := compile(t, &testModel{fields: []trenovaorm.Field{
+		&trenovaorm.UUIDField{ColumnName: "id"},
+		&trenovaorm.TextField{ColumnName: "bio", Nullable: true},
+	}})
+
+	first, err := Generate(dir, "Create users", empty, v1)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	second, err := Generate(dir, "add bio", v1, v2)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if _, err := Generate(dir, "noop", v2, v2); !errors.Is(err, ErrNoChanges) {
+		t.Errorf("Generate() error = %v, want %v", err, ErrNoChanges)
+	}
+
+	for _, name := range []string{
+		"000001_create_users.up.sql",
+		"000001_create_users.down.sql",
+		"000002_add_bio.up.sql",
+		"000002_add_bio.down.sql",
+	} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("expected migration file %s: %v", name, err)
+		}
+	}
+
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(loaded) != 2 {
+		t.Fatalf("Load() returned %d migrations, want 2", len(loaded))
+	}
+	if len(first) != 1 || len(second) != 1 || loaded[0] != first[0] || loaded[1] != second[0] {
+		t.Errorf("Load() = %v, want %v", loaded, append(first, second...))
+	}
+
+	wantUp := "ALTER TABLE \"users\" ADD COLUMN \"bio\" TEXT;\n"
+	wantDown := "ALTER TABLE \"users\" DROP COLUMN \"bio\";\n"
+	if second[0].Up != wantUp || second[0].Down != wantDown {
+		t.Errorf("second migration = %q / %q, want %q / %q", second[0].Up, second[0].Down, wantUp, wantDown)
+	}
+}
+
+func TestLoad_InvalidFileName(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "create_users.up.sql"), []byte("SELECT 1;"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(dir); err == nil {
+		t.Error("Load() error = nil, want invalid file name error")
+	}
+}
+
+func TestSanitizeName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Create users", "create_users"},
+		{"  add--Email!! ", "add_email"},
+		{"***", "migration"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizeName(tt.name); got != tt.want {
+				t.Errorf("sanitizeName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// LedgerTable is the table that records applied migrations.
+const LedgerTable = "schema_migrations"
+
+// lockID is the advisory lock key held while migrations run, so that concurrent
+// runners against the same database wait for each other.
+const lockID int64 = 7_283_015_442
+
+const createLedgerSQL = `CREATE TABLE IF NOT EXISTS "schema_migrations" (` +
+	`"version" BIGINT PRIMARY KEY, ` +
+	`"name" TEXT NOT NULL, ` +
+	`"checksum" TEXT NOT NULL, ` +
+	`"applied_at" TIMESTAMPTZ NOT NULL DEFAULT now())`
+
+const (
+	ledgerExistsSQL = `SELECT to_regclass('"schema_migrations"') IS NOT NULL`
+	selectLedgerSQL = `SELECT "version", "name", "checksum", "applied_at" FROM "schema_migrations" ORDER BY "version"`
+	insertLedgerSQL = `INSERT INTO "schema_migrations" ("version", "name", "checksum") VALUES ($1, $2, $3)`
+	deleteLedgerSQL = `DELETE FROM "schema_migrations" WHERE "version" = $1`
+	lockSQL         = `SELECT pg_advisory_lock($1)`
+	unlockSQL       = `SELECT pg_advisory_unlock($1)`
+)
+
+// ErrChecksumMismatch is returned when an applied migration no longer matches its file.
+var ErrChecksumMismatch = errors.New("applied migration checksum mismatch")
+
+// ErrMissingMigration is returned when the ledger records a version that has no migration file.
+var ErrMissingMigration = errors.New("applied migration is missing")
+
+// Status describes whether a migration has been applied.
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// record is a row of the ledger table.
+type record struct {
+	version   int64
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+// Migrator applies migrations to a database and tracks them in the ledger table.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// New returns a Migrator for the given migrations. Versions must be unique.
+func New(db *sql.DB, migrations []Migration) (*Migrator, error) {
+	sorted := append([]Migration{}, migrations...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Version == sorted[i-1].Version {
+			return nil, fmt.Errorf("duplicate migration version %d", sorted[i].Version)
+		}
+	}
+	return &Migrator{db: db, migrations: sorted}, nil
+}
+
+// Up applies every pending migration in version order and returns the ones applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		records, err := m.verify(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			if _, ok := records[mig.Version]; ok {
+				continue
+			}
+			if err := apply(ctx, conn, mig.Up, mig.NoTransaction, insertLedgerSQL, mig.Version, mig.Name, mig.Checksum); err != nil {
+				return fmt.Errorf("apply migration %d_%s: %w", mig.Version, mig.Name, err)
+			}
+			applied = append(applied, mig)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverts up to steps applied migrations, newest first, and returns the ones reverted.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var reverted []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		records, err := m.verify(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			mig := m.migrations[i]
+			if _, ok := records[mig.Version]; !ok {
+				continue
+			}
+			if err := apply(ctx, conn, mig.Down, mig.NoTransaction, deleteLedgerSQL, mig.Version); err != nil {
+				return fmt.Errorf("revert migration %d_%s: %w", mig.Version, mig.Name, err)
+			}
+			reverted = append(reverted, mig)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status reports every known migration and whether it has been applied. It
+// only reads the ledger: it neither takes the migration lock nor creates the
+// ledger table, and reports nothing applied while that table does not exist.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var exists bool
+	if err := conn.QueryRowContext(ctx, ledgerExistsSQL).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("find %s: %w", LedgerTable, err)
+	}
+	records := make(map[int64]record)
+	if exists {
+		if records, err = readLedger(ctx, conn); err != nil {
+			return nil, err
+		}
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		rec, ok := records[mig.Version]
+		statuses = append(statuses, Status{Migration: mig, Applied: ok, AppliedAt: rec.appliedAt})
+	}
+	return statuses, nil
+}
+
+// withLock runs fn on a single connection holding the migration advisory lock,
+// after making sure the ledger table exists.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) (err error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, lockSQL, lockID); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer func() {
+		if _, unlockErr := conn.ExecContext(context.Background(), unlockSQL, lockID); unlockErr != nil && err == nil {
+			err = fmt.Errorf("release migration lock: %w", unlockErr)
+		}
+	}()
+
+	if _, err := conn.ExecContext(ctx, createLedgerSQL); err != nil {
+		return fmt.Errorf("create %s: %w", LedgerTable, err)
+	}
+
+	return fn(conn)
+}
+
+// verify reads the ledger and checks that every applied migration still has a
+// file with an unchanged checksum.
+func (m *Migrator) verify(ctx context.Context, conn *sql.Conn) (map[int64]record, error) {
+	records, err := readLedger(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[int64]Migration, len(m.migrations))
+	for _, mig := range m.migrations {
+		known[mig.Version] = mig
+	}
+
+	versions := make([]int64, 0, len(records))
+	for version := range records {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+
+	for _, version := range versions {
+		rec := records[version]
+		mig, ok := known[version]
+		if !ok {
+			return nil, fmt.Errorf("%w: version %d (%s)", ErrMissingMigration, version, rec.name)
+		}
+		if mig.Checksum != rec.checksum {
+			return nil, fmt.Errorf("%w: version %d (%s)", ErrChecksumMismatch, version, rec.name)
+		}
+	}
+
+	return records, nil
+}
+
+// readLedger returns the applied migrations keyed by version.
+func readLedger(ctx context.Context, conn *sql.Conn) (map[int64]record, error) {
+	rows, err := conn.QueryContext(ctx, selectLedgerSQL)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", LedgerTable, err)
+	}
+	defer rows.Close()
+
+	records := make(map[int64]record)
+	for rows.Next() {
+		var rec record
+		if err := rows.Scan(&rec.version, &rec.name, &rec.checksum, &rec.appliedAt); err != nil {
+			return nil, err
+		}
+		records[rec.version] = rec
+	}
+	return records, rows.Err()
+}
+
+// apply runs the migration SQL and the ledger update in a single transaction.
+// Without a transaction, the script runs on its own first and the ledger is
+// only updated once it succeeded.
+func apply(ctx context.Context, conn *sql.Conn, script string, noTransaction bool, ledgerSQL string, args ...any) error {
+	if noTransaction {
+		if _, err := conn.ExecContext(ctx, script); err != nil {
+			return err
+		}
+		_, err := conn.ExecContext(ctx, ledgerSQL, args...)
+		return err
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if script != "" {
+		if _, err := tx.ExecContext(ctx, script); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, ledgerSQL, args...); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
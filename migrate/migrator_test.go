@@ -0,0 +1,414 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	trenovaorm "github.com/emoss08/trenova-orm"
+)
+
+// fakeDatabase emulates the ledger table and records executed migration scripts.
+// A nil ledger is a ledger table that does not exist yet.
+type fakeDatabase struct {
+	mu        sync.Mutex
+	ledger    map[int64][]driver.Value
+	executed  []string
+	failOn    string
+	locked    bool
+	lockTaken bool
+}
+
+func (d *fakeDatabase) Open(string) (driver.Conn, error) {
+	return &fakeConn{db: d}, nil
+}
+
+type fakeConn struct {
+	db      *fakeDatabase
+	pending []func()
+	inTx    bool
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepare not supported")
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.inTx = true
+	c.pending = nil
+	return c, nil
+}
+
+func (c *fakeConn) Commit() error {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	for _, op := range c.pending {
+		op()
+	}
+	c.inTx = false
+	c.pending = nil
+	return nil
+}
+
+func (c *fakeConn) Rollback() error {
+	c.inTx = false
+	c.pending = nil
+	return nil
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	db := c.db
+	var op func()
+
+	switch query {
+	case lockSQL:
+		db.mu.Lock()
+		db.locked = true
+		db.lockTaken = true
+		db.mu.Unlock()
+		return driver.RowsAffected(0), nil
+	case unlockSQL:
+		db.mu.Lock()
+		db.locked = false
+		db.mu.Unlock()
+		return driver.RowsAffected(0), nil
+	case createLedgerSQL:
+		db.mu.Lock()
+		if db.ledger == nil {
+			db.ledger = make(map[int64][]driver.Value)
+		}
+		db.mu.Unlock()
+		return driver.RowsAffected(0), nil
+	case insertLedgerSQL:
+		op = func() {
+			db.ledger[args[0].Value.(int64)] = []driver.Value{args[0].Value, args[1].Value, args[2].Value, time.Unix(0, 0)}
+		}
+	case deleteLedgerSQL:
+		op = func() {
+			delete(db.ledger, args[0].Value.(int64))
+		}
+	default:
+		if db.failOn != "" && strings.Contains(query, db.failOn) {
+			return nil, errors.New("syntax error")
+		}
+		// Like PostgreSQL, refuse a concurrent index statement in a
+		// transaction block, which a script of several statements implies.
+		if strings.Contains(query, " CONCURRENTLY ") && (c.inTx || strings.Count(query, ";") > 1) {
+			return nil, errors.New("cannot run inside a transaction block")
+		}
+		op = func() {
+			db.executed = append(db.executed, query)
+		}
+	}
+
+	if c.inTx {
+		c.pending = append(c.pending, op)
+	} else {
+		db.mu.Lock()
+		op()
+		db.mu.Unlock()
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+
+	switch {
+	case query == ledgerExistsSQL:
+		return &fakeRows{columns: []string{"exists"}, rows: [][]driver.Value{{c.db.ledger != nil}}}, nil
+	case query != selectLedgerSQL:
+		return nil, errors.New("unexpected query")
+	case c.db.ledger == nil:
+		return nil, errors.New(`relation "schema_migrations" does not exist`)
+	}
+
+	versions := make([]int64, 0, len(c.db.ledger))
+	for version := range c.db.ledger {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+
+	rows := &fakeRows{columns: []string{"version", "name", "checksum", "applied_at"}}
+	for _, version := range versions {
+		rows.rows = append(rows.rows, c.db.ledger[version])
+	}
+	return rows, nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+	pos     int
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.pos])
+	r.pos++
+	return nil
+}
+
+func openFake(t *testing.T) (*sql.DB, *fakeDatabase) {
+	t.Helper()
+	fake := &fakeDatabase{ledger: make(map[int64][]driver.Value)}
+	name := "migrate-fake-" + t.Name()
+	sql.Register(name, fake)
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, fake
+}
+
+func testMigrations() []Migration {
+	return []Migration{
+		NewMigration(2, "add_email", `ALTER TABLE "users" ADD COLUMN "email" TEXT;`, `ALTER TABLE "users" DROP COLUMN "email";`),
+		NewMigration(1, "create_users", `CREATE TABLE "users" ("id" uuid);`, `DROP TABLE "users";`),
+	}
+}
+
+func TestMigrator_UpDown(t *testing.T) {
+	db, fake := openFake(t)
+	ctx := context.Background()
+
+	m, err := New(db, testMigrations())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	applied, err := m.Up(ctx)
+	if err != nil {
+		t.Fatalf("Migrator.Up() error = %v", err)
+	}
+	if len(applied) != 2 || applied[0].Version != 1 || applied[1].Version != 2 {
+		t.Fatalf("Migrator.Up() applied = %v, want versions 1 and 2", applied)
+	}
+	if len(fake.ledger) != 2 {
+		t.Errorf("ledger has %d rows, want 2", len(fake.ledger))
+	}
+	if fake.locked {
+		t.Error("advisory lock was not released")
+	}
+
+	applied, err = m.Up(ctx)
+	if err != nil {
+		t.Fatalf("Migrator.Up() error = %v", err)
+	}
+	if len(applied) != 0 {
+		t.Errorf("second Migrator.Up() applied = %v, want none", applied)
+	}
+
+	reverted, err := m.Down(ctx, 1)
+	if err != nil {
+		t.Fatalf("Migrator.Down() error = %v", err)
+	}
+	if len(reverted) != 1 || reverted[0].Version != 2 {
+		t.Fatalf("Migrator.Down() reverted = %v, want version 2", reverted)
+	}
+	if _, ok := fake.ledger[2]; ok {
+		t.Error("reverted version 2 is still in the ledger")
+	}
+
+	last := fake.executed[len(fake.executed)-1]
+	if last != `ALTER TABLE "users" DROP COLUMN "email";` {
+		t.Errorf("last executed script = %v, want the down script of version 2", last)
+	}
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Migrator.Status() error = %v", err)
+	}
+	if len(statuses) != 2 || !statuses[0].Applied || statuses[1].Applied {
+		t.Errorf("Migrator.Status() = %v, want version 1 applied and version 2 pending", statuses)
+	}
+}
+
+func TestMigrator_StatusWithoutLedger(t *testing.T) {
+	db, fake := openFake(t)
+	fake.ledger = nil
+
+	m, err := New(db, testMigrations())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	statuses, err := m.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Migrator.Status() error = %v", err)
+	}
+	if len(statuses) != 2 || statuses[0].Applied || statuses[1].Applied {
+		t.Errorf("Migrator.Status() = %v, want both versions pending", statuses)
+	}
+	if fake.ledger != nil {
+		t.Error("Migrator.Status() created the ledger table")
+	}
+	if fake.lockTaken {
+		t.Error("Migrator.Status() took the migration lock")
+	}
+}
+
+func TestMigrator_ChecksumMismatch(t *testing.T) {
+	db, fake := openFake(t)
+	ctx := context.Background()
+
+	fake.ledger[1] = []driver.Value{int64(1), "create_users", "stale", time.Unix(0, 0)}
+
+	m, err := New(db, testMigrations())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if _, err := m.Up(ctx); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("Migrator.Up() error = %v, want %v", err, ErrChecksumMismatch)
+	}
+	if len(fake.executed) != 0 {
+		t.Errorf("executed = %v, want nothing", fake.executed)
+	}
+}
+
+func TestMigrator_MissingMigration(t *testing.T) {
+	db, fake := openFake(t)
+
+	fake.ledger[9] = []driver.Value{int64(9), "removed", "x", time.Unix(0, 0)}
+
+	m, err := New(db, testMigrations())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if _, err := m.Up(context.Background()); !errors.Is(err, ErrMissingMigration) {
+		t.Errorf("Migrator.Up() error = %v, want %v", err, ErrMissingMigration)
+	}
+}
+
+func TestMigrator_FailedMigrationRollsBack(t *testing.T) {
+	db, fake := openFake(t)
+	fake.failOn = "ADD COLUMN"
+
+	m, err := New(db, testMigrations())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	applied, err := m.Up(context.Background())
+	if err == nil {
+		t.Fatal("Migrator.Up() error = nil, want failure")
+	}
+	if len(applied) != 1 {
+		t.Errorf("Migrator.Up() applied = %v, want only version 1", applied)
+	}
+	if _, ok := fake.ledger[2]; ok {
+		t.Error("failed version 2 was recorded in the ledger")
+	}
+	if fake.locked {
+		t.Error("advisory lock was not released")
+	}
+}
+
+func TestNew_DuplicateVersion(t *testing.T) {
+	migrations := []Migration{
+		NewMigration(1, "a", "SELECT 1;", ""),
+		NewMigration(1, "b", "SELECT 2;", ""),
+	}
+	if _, err := New(nil, migrations); err == nil {
+		t.Error("New() error = nil, want duplicate version error")
+	}
+}
+
+func TestMigrator_GeneratedConcurrentIndexes(t *testing.T) {
+	dir := t.TempDir()
+	fields := []trenovaorm.Field{
+		&trenovaorm.UUIDField{ColumnName: "id"},
+		&trenovaorm.TextField{ColumnName: "email"},
+		&trenovaorm.TextField{ColumnName: "name"},
+	}
+	v1 := compile(t, &testModel{fields: fields, indexes: []trenovaorm.Index{
+		{Name: "users_name_idx", Columns: []string{"name"}, Concurrently: true},
+	}})
+	v2 := compile(t, &testModel{
+		fields: append(fields, &trenovaorm.TextField{ColumnName: "bio", Nullable: true}),
+		indexes: []trenovaorm.Index{
+			{Name: "users_email_idx", Columns: []string{"email"}, Concurrently: true},
+			{Name: "users_name_idx", Columns: []string{"name", "email"}, Concurrently: true},
+		},
+	})
+	for _, step := range []struct {
+		name     string
+		from, to *trenovaorm.Schema
+	}{
+		{"create users", compile(t), v1},
+		{"add bio", v1, v2},
+	} {
+		if _, err := Generate(dir, step.name, step.from, step.to); err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+	}
+
+	migrations, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	db, fake := openFake(t)
+	m, err := New(db, migrations)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	ctx := context.Background()
+
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("Migrator.Up() error = %v", err)
+	}
+	if len(fake.ledger) != 5 {
+		t.Errorf("ledger has %d rows, want 5", len(fake.ledger))
+	}
+	want := []string{
+		`CREATE TABLE IF NOT EXISTS "users" ("id" uuid NOT NULL, "email" TEXT NOT NULL, "name" TEXT NOT NULL);` + "\n" +
+			`CREATE INDEX IF NOT EXISTS "users_name_idx" ON "users" ("name");` + "\n",
+		"-- trenova:no-transaction\n" + `DROP INDEX CONCURRENTLY IF EXISTS "users_name_idx";` + "\n",
+		`ALTER TABLE "users" ADD COLUMN "bio" TEXT;` + "\n",
+		"-- trenova:no-transaction\n" + `CREATE INDEX CONCURRENTLY IF NOT EXISTS "users_email_idx" ON "users" ("email");` + "\n",
+		"-- trenova:no-transaction\n" + `CREATE INDEX CONCURRENTLY IF NOT EXISTS "users_name_idx" ON "users" ("name", "email");` + "\n",
+	}
+	if !slices.Equal(fake.executed, want) {
+		t.Errorf("executed = %q, want %q", fake.executed, want)
+	}
+
+	fake.executed = nil
+	if _, err := m.Down(ctx, 4); err != nil {
+		t.Fatalf("Migrator.Down() error = %v", err)
+	}
+	want = []string{
+		"-- trenova:no-transaction\n" + `DROP INDEX CONCURRENTLY IF EXISTS "users_name_idx";` + "\n",
+		"-- trenova:no-transaction\n" + `DROP INDEX CONCURRENTLY IF EXISTS "users_email_idx";` + "\n",
+		`ALTER TABLE "users" DROP COLUMN "bio";` + "\n",
+		"-- trenova:no-transaction\n" + `CREATE INDEX CONCURRENTLY IF NOT EXISTS "users_name_idx" ON "users" ("name");` + "\n",
+	}
+	if !slices.Equal(fake.executed, want) {
+		t.Errorf("executed = %q, want %q", fake.executed, want)
+	}
+}
@@ -21,19 +21,21 @@ type TimestampedMixin struct {
 // Fields returns the common timestamp fields.
 func (t TimestampedMixin) Fields() []Field {
 	return []Field{
-		&DateField{
-			ColumnName: "created_at",
-			Nullable:   false,
-			Default:    CurrentTimestamp,
-			Comment:    "Creation timestamp",
-			StructTag:  `json:"created_at" validate:"required"`,
+		&TimestampField{
+			ColumnName:   "created_at",
+			WithTimeZone: true,
+			Nullable:     false,
+			Default:      CurrentTimestamp,
+			Comment:      "Creation timestamp",
+			StructTag:    `json:"created_at" validate:"required"`,
 		},
-		&DateField{
-			ColumnName: "updated_at",
-			Nullable:   false,
-			Default:    CurrentTimestamp,
-			Comment:    "Update timestamp",
-			StructTag:  `json:"updated_at" validate:"required"`,
+		&TimestampField{
+			ColumnName:   "updated_at",
+			WithTimeZone: true,
+			Nullable:     false,
+			Default:      CurrentTimestamp,
+			Comment:      "Update timestamp",
+			StructTag:    `json:"updated_at" validate:"required"`,
 		},
 	}
 }
@@ -0,0 +1,84 @@
+package trenovaorm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultSchemaName is the PostgreSQL schema of the tables of models that do
+// not implement SchemaModel. When it is empty, their names are left
+// unqualified and resolved through the search_path of the connection. It is
+// read when models are compiled or queried, so set it before either, e.g. in
+// an init function.
+var DefaultSchemaName string
+
+// SchemaModel is implemented by models whose table lives in a PostgreSQL
+// schema other than DefaultSchemaName, such as billing.
+type SchemaModel interface {
+	SchemaName() string
+}
+
+// ModelSchemaName returns the schema of a model's table: the one it declares
+// through SchemaModel, or else DefaultSchemaName.
+func ModelSchemaName(model Model) string {
+	if m, ok := model.(SchemaModel); ok {
+		if schema := m.SchemaName(); schema != "" {
+			return schema
+		}
+	}
+	return DefaultSchemaName
+}
+
+// QualifiedTableName returns the table name of a model qualified with its
+// schema, e.g. billing.invoices, or the bare table name if it has none. It is
+// the name of the table in a compiled Schema.
+func QualifiedTableName(model Model) string {
+	return QualifyName(ModelSchemaName(model), model.TableName())
+}
+
+// QualifyName qualifies name with schema, unless schema is empty.
+func QualifyName(schema, name string) string {
+	if schema == "" {
+		return name
+	}
+	return schema + "." + name
+}
+
+// splitQualifiedName splits a name qualified with a schema into the schema and
+// the unqualified name. The schema is empty if the name is not qualified.
+func splitQualifiedName(name string) (string, string) {
+	if schema, rest, ok := strings.Cut(name, "."); ok {
+		return schema, rest
+	}
+	return "", name
+}
+
+// unqualifiedName returns name without its schema. Names derived from a table
+// name, such as those of its constraints and indexes, use the unqualified name,
+// since they live in the schema of the table.
+func unqualifiedName(name string) string {
+	_, name = splitQualifiedName(name)
+	return name
+}
+
+// schemaOf returns the schema a qualified name belongs to, or the empty string.
+func schemaOf(name string) string {
+	schema, _ := splitQualifiedName(name)
+	return schema
+}
+
+// validateTableName checks the schema and table name of a model.
+func validateTableName(schema, name string) error {
+	if strings.Contains(name, ".") {
+		return fmt.Errorf("table name %s cannot contain a dot; implement SchemaModel to place the table in a schema", name)
+	}
+	if schema != "" && (strings.TrimSpace(schema) == "" || strings.Contains(schema, ".")) {
+		return fmt.Errorf("table %s: invalid schema name %q", name, schema)
+	}
+	return nil
+}
+
+// createSchemaSQL generates the statement creating a schema.
+func createSchemaSQL(schema string) string {
+	return fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s;", QuoteIdentifier(schema))
+}
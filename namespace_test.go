@@ -0,0 +1,277 @@
+package trenovaorm
+
+import "testing"
+
+type schemaModel struct {
+	testModel
+	schema string
+}
+
+func (m *schemaModel) SchemaName() string {
+	return m.schema
+}
+
+func TestCompile_Schemas(t *testing.T) {
+	users := &testModel{name: "users", fields: []Field{&UUIDField{ColumnName: "id", PrimaryKey: true}}}
+	lines := &schemaModel{
+		testModel: testModel{
+			name: "invoice_lines",
+			fields: []Field{
+				&UUIDField{ColumnName: "id", PrimaryKey: true},
+				&ForeignKeyField{ColumnName: "invoice_id", ReferenceTable: "invoices", ReferenceField: "id"},
+			},
+		},
+		schema: "billing",
+	}
+	invoices := &schemaModel{
+		testModel: testModel{
+			name: "invoices",
+			fields: []Field{
+				&UUIDField{ColumnName: "id", PrimaryKey: true},
+				&ForeignKeyField{ColumnName: "user_id", ReferenceTable: "users", ReferenceField: "id", CustomType: "uuid", Index: true},
+				&TextField{ColumnName: "number", Comment: "Invoice number"},
+			},
+			indexes: []Index{{Columns: []string{"number"}, Unique: true}},
+		},
+		schema: "billing",
+	}
+	schema := mustCompile(t, lines, invoices, users)
+
+	if got := schema.SchemaNames(); len(got) != 1 || got[0] != "billing" {
+		t.Errorf("Schema.SchemaNames() = %v, want [billing]", got)
+	}
+	if schema.Table("billing.invoices") == nil {
+		t.Error(`Schema.Table("billing.invoices") = nil, want table`)
+	}
+
+	got, err := schema.Statements()
+	if err != nil {
+		t.Fatalf("Schema.Statements() error = %v", err)
+	}
+	assertStatements(t, got, []string{
+		`CREATE SCHEMA IF NOT EXISTS "billing";`,
+		`CREATE TABLE IF NOT EXISTS "users" ("id" uuid NOT NULL PRIMARY KEY);`,
+		`CREATE TABLE IF NOT EXISTS "billing"."invoices" ("id" uuid NOT NULL PRIMARY KEY, "user_id" uuid NOT NULL, "number" TEXT NOT NULL, ` +
+			`CONSTRAINT "invoices_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "users"("id"));`,
+		`CREATE TABLE IF NOT EXISTS "billing"."invoice_lines" ("id" uuid NOT NULL PRIMARY KEY, "invoice_id" uuid NOT NULL, ` +
+			`CONSTRAINT "invoice_lines_invoice_id_fkey" FOREIGN KEY ("invoice_id") REFERENCES "billing"."invoices"("id"));`,
+		`COMMENT ON COLUMN "billing"."invoices"."number" IS 'Invoice number';`,
+		`CREATE INDEX "invoices_user_id_idx" ON "billing"."invoices" ("user_id");`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS "invoices_number_idx" ON "billing"."invoices" ("number");`,
+	})
+}
+
+func TestCompile_CrossSchemaReference(t *testing.T) {
+	invoices := &schemaModel{testModel: testModel{name: "invoices", fields: []Field{&UUIDField{ColumnName: "id", PrimaryKey: true}}}, schema: "billing"}
+	payments := &schemaModel{
+		testModel: testModel{
+			name: "payments",
+			fields: []Field{
+				&UUIDField{ColumnName: "id", PrimaryKey: true},
+				&ForeignKeyField{ColumnName: "invoice_id", ReferenceTable: "billing.invoices", ReferenceField: "id"},
+			},
+		},
+		schema: "payments",
+	}
+
+	got, err := mustCompile(t, payments, invoices).Statements()
+	if err != nil {
+		t.Fatalf("Schema.Statements() error = %v", err)
+	}
+	assertStatements(t, got, []string{
+		`CREATE SCHEMA IF NOT EXISTS "payments";`,
+		`CREATE SCHEMA IF NOT EXISTS "billing";`,
+		`CREATE TABLE IF NOT EXISTS "billing"."invoices" ("id" uuid NOT NULL PRIMARY KEY);`,
+		`CREATE TABLE IF NOT EXISTS "payments"."payments" ("id" uuid NOT NULL PRIMARY KEY, "invoice_id" uuid NOT NULL, ` +
+			`CONSTRAINT "payments_invoice_id_fkey" FOREIGN KEY ("invoice_id") REFERENCES "billing"."invoices"("id"));`,
+	})
+}
+
+func TestCompile_DefaultSchemaName(t *testing.T) {
+	DefaultSchemaName = "app"
+	t.Cleanup(func() { DefaultSchemaName = "" })
+
+	users := &testModel{name: "users", fields: []Field{&UUIDField{ColumnName: "id", PrimaryKey: true}}}
+	audits := &testModel{name: "audits", fields: []Field{
+		&UUIDField{ColumnName: "id", PrimaryKey: true},
+		&ForeignKeyField{ColumnName: "user_id", ReferenceTable: "users", ReferenceField: "id"},
+	}}
+
+	got, err := mustCompile(t, users, audits).Statements()
+	if err != nil {
+		t.Fatalf("Schema.Statements() error = %v", err)
+	}
+	assertStatements(t, got, []string{
+		`CREATE SCHEMA IF NOT EXISTS "app";`,
+		`CREATE TABLE IF NOT EXISTS "app"."users" ("id" uuid NOT NULL PRIMARY KEY);`,
+		`CREATE TABLE IF NOT EXISTS "app"."audits" ("id" uuid NOT NULL PRIMARY KEY, "user_id" uuid NOT NULL, ` +
+			`CONSTRAINT "audits_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "app"."users"("id"));`,
+	})
+}
+
+func TestCompile_SchemaErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		model Model
+	}{
+		{"Dotted table name", &testModel{name: "billing.invoices", fields: []Field{&UUIDField{ColumnName: "id"}}}},
+		{"Blank schema", &schemaModel{testModel: testModel{name: "invoices", fields: []Field{&UUIDField{ColumnName: "id"}}}, schema: " "}},
+		{"Reference to another schema's table", &schemaModel{
+			testModel: testModel{
+				name: "payments",
+				fields: []Field{
+					&UUIDField{ColumnName: "id"},
+					&ForeignKeyField{ColumnName: "invoice_id", ReferenceTable: "invoices", ReferenceField: "id"},
+				},
+			},
+			schema: "payments",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invoices := &schemaModel{testModel: testModel{name: "invoices", fields: []Field{&UUIDField{ColumnName: "id", PrimaryKey: true}}}, schema: "billing"}
+			if _, err := Compile(tt.model, invoices); err == nil {
+				t.Error("Compile() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestDiff_Schemas(t *testing.T) {
+	from := mustCompile(t, &schemaModel{
+		testModel: testModel{
+			name:    "invoices",
+			fields:  []Field{&UUIDField{ColumnName: "id", PrimaryKey: true}},
+			indexes: []Index{{Columns: []string{"id"}, Unique: true}},
+		},
+		schema: "billing",
+	})
+	to := mustCompile(t,
+		&schemaModel{
+			testModel: testModel{
+				name: "invoices",
+				fields: []Field{
+					&UUIDField{ColumnName: "id", PrimaryKey: true},
+					&BooleanField{ColumnName: "paid"},
+				},
+			},
+			schema: "billing",
+		},
+		&schemaModel{testModel: testModel{name: "ledger", fields: []Field{&UUIDField{ColumnName: "id", PrimaryKey: true}}}, schema: "accounting"},
+	)
+
+	got, err := Diff(from, to)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	assertStatements(t, got, []string{
+		`CREATE SCHEMA IF NOT EXISTS "accounting";`,
+		`DROP INDEX IF EXISTS "billing"."invoices_id_idx";`,
+		`CREATE TABLE IF NOT EXISTS "accounting"."ledger" ("id" uuid NOT NULL PRIMARY KEY);`,
+		`ALTER TABLE "billing"."invoices" ADD COLUMN "paid" BOOLEAN NOT NULL DEFAULT FALSE;`,
+	})
+}
+
+func TestCompile_SchemaEnums(t *testing.T) {
+	model := &schemaModel{
+		testModel: testModel{
+			name: "payments",
+			fields: []Field{
+				&EnumField{ColumnName: "status", TypeName: "payment_status", Values: []string{"pending", "paid"}, Default: "pending"},
+				&ArrayField{ColumnName: "history", Element: &EnumField{TypeName: "payment_status", Values: []string{"pending", "paid"}}, Nullable: true},
+				&EnumField{ColumnName: "currency", TypeName: "currency", TypeSchema: "shared", Values: []string{"USD", "EUR"}},
+			},
+		},
+		schema: "billing",
+	}
+	from := mustCompile(t, model)
+
+	if got := from.SchemaNames(); len(got) != 2 || got[0] != "billing" || got[1] != "shared" {
+		t.Errorf("Schema.SchemaNames() = %v, want [billing shared]", got)
+	}
+	if _, ok := from.Enum("billing.payment_status"); !ok {
+		t.Error(`Schema.Enum("billing.payment_status") = false, want true`)
+	}
+	if got := ColumnOf(from.Table("billing.payments").Field("history")).Type; got != `"billing"."payment_status"[]` {
+		t.Errorf("ColumnOf() type = %v, want the qualified array type", got)
+	}
+
+	created, err := Diff(mustCompile(t), from)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	assertStatements(t, created, []string{
+		`CREATE SCHEMA IF NOT EXISTS "billing";`,
+		`CREATE SCHEMA IF NOT EXISTS "shared";`,
+		`CREATE TYPE "billing"."payment_status" AS ENUM ('pending', 'paid');`,
+		`CREATE TYPE "shared"."currency" AS ENUM ('USD', 'EUR');`,
+		`CREATE TABLE IF NOT EXISTS "billing"."payments" ("status" "billing"."payment_status" NOT NULL DEFAULT 'pending', ` +
+			`"history" "billing"."payment_status"[], "currency" "shared"."currency" NOT NULL);`,
+	})
+
+	refunded := []string{"pending", "paid", "refunded"}
+	altered, err := Diff(from, mustCompile(t, &schemaModel{
+		testModel: testModel{
+			name: "payments",
+			fields: []Field{
+				&EnumField{ColumnName: "status", TypeName: "payment_status", Values: refunded, Default: "pending"},
+				&ArrayField{ColumnName: "history", Element: &EnumField{TypeName: "payment_status", Values: refunded}, Nullable: true},
+				&EnumField{ColumnName: "currency", TypeName: "currency", TypeSchema: "shared", Values: []string{"USD", "EUR"}},
+			},
+		},
+		schema: "billing",
+	}))
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	assertStatements(t, altered, []string{`ALTER TYPE "billing"."payment_status" ADD VALUE IF NOT EXISTS 'refunded' AFTER 'paid';`})
+
+	dropped, err := Diff(from, mustCompile(t))
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	assertStatements(t, dropped, []string{
+		`DROP TABLE IF EXISTS "billing"."payments";`,
+		`DROP TYPE IF EXISTS "billing"."payment_status";`,
+		`DROP TYPE IF EXISTS "shared"."currency";`,
+	})
+
+	if got := model.fields[0].(*EnumField).TypeSchema; got != "" {
+		t.Errorf("Compile() changed the TypeSchema of the model field to %q", got)
+	}
+}
+
+func TestQuery_Schemas(t *testing.T) {
+	users := &schemaModel{testModel: testModel{name: "users", fields: queryUsers.fields}, schema: "auth"}
+	organizations := &schemaModel{testModel: testModel{name: "organizations", fields: queryOrganizations.fields}, schema: "auth"}
+
+	tests := []struct {
+		name  string
+		query interface {
+			Build() (string, []any, error)
+		}
+		want string
+	}{
+		{
+			"Select with join",
+			Select(users, "id", "organizations.name").Join(organizations),
+			`SELECT "users"."id", "organizations"."name" FROM "auth"."users" JOIN "auth"."organizations" ON "users"."organization_id" = "organizations"."id"`,
+		},
+		{"Insert", Insert(organizations).Columns("id", "name").Values(1, "Acme"), `INSERT INTO "auth"."organizations" ("id", "name") VALUES ($1, $2)`},
+		{"Update", Update(users).Set("age", 30), `UPDATE "auth"."users" SET "age" = $1`},
+		{"Delete", Delete(users), `DELETE FROM "auth"."users"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := tt.query.Build()
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
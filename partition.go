@@ -0,0 +1,273 @@
+package trenovaorm
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// PartitionStrategy is the way the rows of a partitioned table are assigned
+// to its partitions.
+type PartitionStrategy string
+
+const (
+	PartitionRange PartitionStrategy = "RANGE" // By ranges of the key, e.g. one partition per month
+	PartitionList  PartitionStrategy = "LIST"  // By values of the key, e.g. one partition per organization
+)
+
+// PartitionedModel is implemented by models whose table is partitioned. It is
+// optional, so models of plain tables need not implement it.
+type PartitionedModel interface {
+	Partitioning() Partitioning
+}
+
+// ModelPartitioning returns the partitioning of a model, or the zero
+// Partitioning if the model does not implement PartitionedModel.
+func ModelPartitioning(model Model) Partitioning {
+	if m, ok := model.(PartitionedModel); ok {
+		return m.Partitioning()
+	}
+	return Partitioning{}
+}
+
+// Partitioning declares a table partitioned by a single key column:
+//
+//	Partitioning{
+//		Strategy:   PartitionRange,
+//		Column:     "created_at",
+//		Partitions: MonthlyPartitions("shipment_events", start, 12),
+//	}
+//
+// The primary key and every unique constraint and index of the table must
+// include the key column, and every exclusion constraint must compare it with
+// =, since PostgreSQL enforces them per partition. Partitioned tables cannot have storage parameters or be unlogged.
+type Partitioning struct {
+	Strategy   PartitionStrategy
+	Column     string
+	Partitions []Partition // Partitions created with the table
+}
+
+// IsZero reports whether the table is not partitioned.
+func (p Partitioning) IsZero() bool {
+	return p.Strategy == ""
+}
+
+// clause returns the PARTITION BY clause of CREATE TABLE.
+func (p Partitioning) clause() string {
+	return fmt.Sprintf("PARTITION BY %s (%s)", p.Strategy, QuoteIdentifier(p.Column))
+}
+
+// Validate checks the strategy, the key and the partitions.
+func (p Partitioning) Validate() error {
+	if p.Strategy != PartitionRange && p.Strategy != PartitionList {
+		return fmt.Errorf("invalid partition strategy %q", p.Strategy)
+	}
+	if p.Column == "" {
+		return errors.New("partition key column cannot be empty")
+	}
+
+	names := make(map[string]bool, len(p.Partitions))
+	defaults := 0
+	for _, part := range p.Partitions {
+		if err := part.validate(p.Strategy); err != nil {
+			return err
+		}
+		if names[part.Name] {
+			return fmt.Errorf("partition %s is defined more than once", part.Name)
+		}
+		names[part.Name] = true
+		if part.Default {
+			defaults++
+		}
+	}
+	if defaults > 1 {
+		return errors.New("only one partition can be the default")
+	}
+	return nil
+}
+
+// Partition is a child table of a partitioned table, holding the rows whose
+// key lies in [From, To) for range partitioning, or in Values for list
+// partitioning. Bounds are literal values, as accepted by Literal, or
+// Expressions.
+type Partition struct {
+	Name    string // Table name of the partition
+	From    any    // Inclusive lower bound of a range partition
+	To      any    // Exclusive upper bound of a range partition
+	Values  []any  // Key values of a list partition
+	Default bool   // Holds the rows that fit no other partition
+}
+
+// SQL generates the CREATE TABLE statement creating the partition of the
+// given parent table, in the schema of the parent.
+func (p Partition) SQL(parent string) string {
+	name := QualifyName(schemaOf(parent), p.Name)
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s PARTITION OF %s %s;", QuoteQualifiedName(name), QuoteQualifiedName(parent), p.bound())
+}
+
+// bound returns the partition bound clause.
+func (p Partition) bound() string {
+	if p.Default {
+		return "DEFAULT"
+	}
+	if len(p.Values) > 0 {
+		values := make([]string, len(p.Values))
+		for i, v := range p.Values {
+			values[i], _ = boundSQL(v)
+		}
+		return fmt.Sprintf("FOR VALUES IN (%s)", strings.Join(values, ", "))
+	}
+	from, _ := boundSQL(p.From)
+	to, _ := boundSQL(p.To)
+	return fmt.Sprintf("FOR VALUES FROM (%s) TO (%s)", from, to)
+}
+
+// validate checks that the partition has the bounds of the strategy.
+func (p Partition) validate(strategy PartitionStrategy) error {
+	if p.Name == "" {
+		return errors.New("partition name cannot be empty")
+	}
+	if strings.Contains(p.Name, ".") {
+		return fmt.Errorf("partition %s: partitions are created in the schema of their table and cannot be qualified", p.Name)
+	}
+	if p.Default {
+		if p.From != nil || p.To != nil || len(p.Values) > 0 {
+			return fmt.Errorf("partition %s: the default partition cannot have bounds", p.Name)
+		}
+		return nil
+	}
+
+	var bounds []any
+	switch strategy {
+	case PartitionRange:
+		if p.From == nil || p.To == nil || len(p.Values) > 0 {
+			return fmt.Errorf("partition %s: range partitions need From and To only", p.Name)
+		}
+		bounds = []any{p.From, p.To}
+	case PartitionList:
+		if len(p.Values) == 0 || p.From != nil || p.To != nil {
+			return fmt.Errorf("partition %s: list partitions need Values only", p.Name)
+		}
+		bounds = p.Values
+	}
+	for _, v := range bounds {
+		if _, ok := boundSQL(v); !ok {
+			return fmt.Errorf("partition %s: unsupported bound %v of type %T", p.Name, v, v)
+		}
+	}
+	return nil
+}
+
+// boundSQL renders a partition bound, or returns false if its type is not supported.
+func boundSQL(v any) (string, bool) {
+	if expr, ok := v.(Expression); ok {
+		return expr.Expression(), validateExpression(expr) == nil
+	}
+	return literalSQL(v)
+}
+
+// MonthlyPartitions plans count range partitions of table, one per calendar
+// month in the location of start, beginning with the month of start. They
+// are named <table>_<yyyy>_<mm>.
+func MonthlyPartitions(table string, start time.Time, count int) []Partition {
+	partitions := make([]Partition, 0, count)
+	month := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, start.Location())
+	for i := 0; i < count; i++ {
+		next := month.AddDate(0, 1, 0)
+		partitions = append(partitions, Partition{
+			Name: fmt.Sprintf("%s_%04d_%02d", table, month.Year(), int(month.Month())),
+			From: month,
+			To:   next,
+		})
+		month = next
+	}
+	return partitions
+}
+
+// ListPartitions plans one list partition of table per value. They are named
+// <table>_<value>, with the value lower-cased and every character other than
+// a letter or digit replaced by an underscore.
+func ListPartitions(table string, values ...any) []Partition {
+	partitions := make([]Partition, 0, len(values))
+	for _, v := range values {
+		suffix := strings.Map(func(r rune) rune {
+			if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+				return r
+			}
+			return '_'
+		}, strings.ToLower(fmt.Sprint(v)))
+		partitions = append(partitions, Partition{Name: table + "_" + suffix, Values: []any{v}})
+	}
+	return partitions
+}
+
+// UpcomingPartitions returns the statements creating the monthly partitions of
+// a range-partitioned model for the month of now and the given number of
+// months after it. The statements use IF NOT EXISTS, so a scheduled job can
+// run them repeatedly to create partitions ahead of the rows that need them.
+func UpcomingPartitions(model Model, now time.Time, ahead int) ([]Statement, error) {
+	table, err := compileTable(model)
+	if err != nil {
+		return nil, err
+	}
+	if table.Partitioning.Strategy != PartitionRange {
+		return nil, fmt.Errorf("table %s is not partitioned by range", table.Name)
+	}
+
+	var stmts []Statement
+	for _, part := range MonthlyPartitions(unqualifiedName(table.Name), now, ahead+1) {
+		stmts = append(stmts, Statement{Kind: StatementCreateTable, Table: table.Name, SQL: part.SQL(table.Name)})
+	}
+	return stmts, nil
+}
+
+// validatePartitioning checks the partitioning of a table against its columns,
+// options, keys and indexes.
+func validatePartitioning(table *Table, columns map[string]bool) error {
+	p := table.Partitioning
+	if err := p.Validate(); err != nil {
+		return fmt.Errorf("table %s: %w", table.Name, err)
+	}
+	key := p.Column
+	if !columns[key] {
+		return fmt.Errorf("table %s: partition key references unknown column %s", table.Name, key)
+	}
+	if ColumnOf(table.Field(key)).Generated != "" {
+		return fmt.Errorf("table %s: partition key %s cannot be a generated column", table.Name, key)
+	}
+	if len(table.Options.Storage) > 0 || table.Options.Unlogged {
+		return fmt.Errorf("table %s: partitioned tables cannot have storage parameters or be unlogged", table.Name)
+	}
+
+	if pk := table.PrimaryKey(); len(pk) > 0 && !slices.Contains(pk, key) {
+		return fmt.Errorf("table %s: primary key (%s) must include partition key %s", table.Name, strings.Join(pk, ", "), key)
+	}
+	for _, field := range table.Fields {
+		if col := ColumnOf(field); col.Unique && col.Name != key {
+			return fmt.Errorf("table %s: unique column %s must be part of a unique constraint that includes partition key %s", table.Name, col.Name, key)
+		}
+	}
+	for _, c := range table.Constraints {
+		if u, ok := asUnique(c); ok && !slices.Contains(u.Columns, key) {
+			return fmt.Errorf("table %s: unique constraint %s must include partition key %s", table.Name, u.ConstraintName(table.Name), key)
+		}
+		if x, ok := asExclude(c); ok && !slices.Contains(x.Elements, ExcludeElement{Column: key, Operator: "="}) {
+			return fmt.Errorf("table %s: exclusion constraint %s must compare partition key %s with =", table.Name, x.ConstraintName(table.Name), key)
+		}
+	}
+	for i := range table.Indexes {
+		idx := &table.Indexes[i]
+		if idx.Concurrently {
+			return fmt.Errorf("table %s: indexes of partitioned tables cannot be built concurrently", table.Name)
+		}
+		if !idx.Unique {
+			continue
+		}
+		if columns, ok := idx.keyColumns(); !ok || !slices.Contains(columns, key) {
+			return fmt.Errorf("table %s: unique index %s must include partition key %s as a column", table.Name, idx.generateName(table.Name), key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+package trenovaorm
+
+import (
+	"testing"
+	"time"
+)
+
+type partitionedModel struct {
+	testModel
+	partitioning Partitioning
+	unlogged     bool
+}
+
+func (m *partitionedModel) Partitioning() Partitioning {
+	return m.partitioning
+}
+
+func (m *partitionedModel) Unlogged() bool {
+	return m.unlogged
+}
+
+type constrainedPartitionedModel struct {
+	partitionedModel
+	constraints []TableConstraint
+}
+
+func (m *constrainedPartitionedModel) Constraints() []TableConstraint {
+	return m.constraints
+}
+
+func TestCompile_Partitioning(t *testing.T) {
+	start := time.Date(2024, time.December, 15, 0, 0, 0, 0, time.UTC)
+	schema := mustCompile(t, &partitionedModel{
+		testModel: testModel{
+			name: "shipment_events",
+			fields: []Field{
+				&UUIDField{ColumnName: "id"},
+				&TimestampField{ColumnName: "created_at"},
+			},
+			indexes: []Index{{Columns: []string{"id", "created_at"}, Unique: true}},
+		},
+		partitioning: Partitioning{
+			Strategy:   PartitionRange,
+			Column:     "created_at",
+			Partitions: MonthlyPartitions("shipment_events", start, 2),
+		},
+	})
+
+	got, err := schema.Statements()
+	if err != nil {
+		t.Fatalf("Schema.Statements() error = %v", err)
+	}
+	assertStatements(t, got, []string{
+		`CREATE TABLE IF NOT EXISTS "shipment_events" ("id" uuid NOT NULL, "created_at" TIMESTAMP NOT NULL) PARTITION BY RANGE ("created_at");`,
+		`CREATE TABLE IF NOT EXISTS "shipment_events_2024_12" PARTITION OF "shipment_events" FOR VALUES FROM ('2024-12-01T00:00:00Z') TO ('2025-01-01T00:00:00Z');`,
+		`CREATE TABLE IF NOT EXISTS "shipment_events_2025_01" PARTITION OF "shipment_events" FOR VALUES FROM ('2025-01-01T00:00:00Z') TO ('2025-02-01T00:00:00Z');`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS "shipment_events_id_created_at_idx" ON "shipment_events" ("id", "created_at");`,
+	})
+}
+
+func TestCompile_ListPartitions(t *testing.T) {
+	schema := mustCompile(t, &partitionedModel{
+		testModel: testModel{
+			name: "orders",
+			fields: []Field{
+				&UUIDField{ColumnName: "id"},
+				&TextField{ColumnName: "region"},
+			},
+		},
+		partitioning: Partitioning{
+			Strategy:   PartitionList,
+			Column:     "region",
+			Partitions: append(ListPartitions("orders", "EU-West", "us"), Partition{Name: "orders_other", Default: true}),
+		},
+	})
+
+	got, err := schema.Statements()
+	if err != nil {
+		t.Fatalf("Schema.Statements() error = %v", err)
+	}
+	assertStatements(t, got, []string{
+		`CREATE TABLE IF NOT EXISTS "orders" ("id" uuid NOT NULL, "region" TEXT NOT NULL) PARTITION BY LIST ("region");`,
+		`CREATE TABLE IF NOT EXISTS "orders_eu_west" PARTITION OF "orders" FOR VALUES IN ('EU-West');`,
+		`CREATE TABLE IF NOT EXISTS "orders_us" PARTITION OF "orders" FOR VALUES IN ('us');`,
+		`CREATE TABLE IF NOT EXISTS "orders_other" PARTITION OF "orders" DEFAULT;`,
+	})
+}
+
+func TestUpcomingPartitions(t *testing.T) {
+	now := time.Date(2025, time.March, 31, 12, 0, 0, 0, time.UTC)
+	events := &partitionedModel{
+		testModel: testModel{
+			name: "shipment_events",
+			fields: []Field{
+				&UUIDField{ColumnName: "id"},
+				&TimestampField{ColumnName: "created_at"},
+			},
+		},
+		partitioning: Partitioning{Strategy: PartitionRange, Column: "created_at"},
+	}
+
+	got, err := UpcomingPartitions(events, now, 1)
+	if err != nil {
+		t.Fatalf("UpcomingPartitions() error = %v", err)
+	}
+	assertStatements(t, got, []string{
+		`CREATE TABLE IF NOT EXISTS "shipment_events_2025_03" PARTITION OF "shipment_events" FOR VALUES FROM ('2025-03-01T00:00:00Z') TO ('2025-04-01T00:00:00Z');`,
+		`CREATE TABLE IF NOT EXISTS "shipment_events_2025_04" PARTITION OF "shipment_events" FOR VALUES FROM ('2025-04-01T00:00:00Z') TO ('2025-05-01T00:00:00Z');`,
+	})
+
+	if _, err := UpcomingPartitions(&testModel{name: "users", fields: []Field{&UUIDField{ColumnName: "id", PrimaryKey: true}}}, now, 1); err == nil {
+		t.Error("UpcomingPartitions() of a plain table error = nil, want error")
+	}
+}
+
+func TestCompile_PartitioningErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		field        Field
+		index        *Index
+		unlogged     bool
+		partitioning Partitioning
+	}{
+		{name: "Invalid strategy", partitioning: Partitioning{Strategy: "HASH", Column: "created_at"}},
+		{name: "Unknown key column", partitioning: Partitioning{Strategy: PartitionRange, Column: "shipped_at"}},
+		{
+			name:         "Primary key without the key",
+			field:        &BigIntegerField{ColumnName: "seq", PrimaryKey: true},
+			partitioning: Partitioning{Strategy: PartitionRange, Column: "created_at"},
+		},
+		{
+			name:         "Unique column",
+			field:        &TextField{ColumnName: "reference", Unique: true},
+			partitioning: Partitioning{Strategy: PartitionRange, Column: "created_at"},
+		},
+		{name: "Missing range bound", partitioning: Partitioning{Strategy: PartitionRange, Column: "created_at", Partitions: []Partition{
+			{Name: "shipment_events_old", To: "2024-01-01"},
+		}}},
+		{name: "List values on a range partition", partitioning: Partitioning{Strategy: PartitionRange, Column: "created_at", Partitions: []Partition{
+			{Name: "shipment_events_x", Values: []any{"x"}},
+		}}},
+		{name: "Unsupported bound", partitioning: Partitioning{Strategy: PartitionRange, Column: "created_at", Partitions: []Partition{
+			{Name: "shipment_events_x", From: struct{}{}, To: "2024-01-01"},
+		}}},
+		{name: "Bounds on the default partition", partitioning: Partitioning{Strategy: PartitionRange, Column: "created_at", Partitions: []Partition{
+			{Name: "shipment_events_x", Default: true, From: "2024-01-01"},
+		}}},
+		{name: "Duplicate partition", partitioning: Partitioning{Strategy: PartitionRange, Column: "created_at", Partitions: []Partition{
+			{Name: "shipment_events_x", From: "2024-01-01", To: "2024-02-01"},
+			{Name: "shipment_events_x", From: "2024-02-01", To: "2024-03-01"},
+		}}},
+		{name: "Two default partitions", partitioning: Partitioning{Strategy: PartitionRange, Column: "created_at", Partitions: []Partition{
+			{Name: "shipment_events_a", Default: true},
+			{Name: "shipment_events_b", Default: true},
+		}}},
+		{
+			name:         "Unique index without the key",
+			index:        &Index{Columns: []string{"id"}, Unique: true},
+			partitioning: Partitioning{Strategy: PartitionRange, Column: "created_at"},
+		},
+		{
+			name:         "Unique expression index",
+			index:        &Index{Expressions: []Expression{DateTrunc{Field: "day", Operand: Ref{Column: "created_at"}}}, Unique: true},
+			partitioning: Partitioning{Strategy: PartitionRange, Column: "created_at"},
+		},
+		{
+			name:         "Concurrent index",
+			index:        &Index{Columns: []string{"created_at"}, Concurrently: true},
+			partitioning: Partitioning{Strategy: PartitionRange, Column: "created_at"},
+		},
+		{
+			name:         "Unlogged",
+			unlogged:     true,
+			partitioning: Partitioning{Strategy: PartitionRange, Column: "created_at"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := &partitionedModel{
+				testModel: testModel{
+					name: "shipment_events",
+					fields: []Field{
+						&UUIDField{ColumnName: "id"},
+						&TimestampField{ColumnName: "created_at"},
+					},
+				},
+				unlogged:     tt.unlogged,
+				partitioning: tt.partitioning,
+			}
+			if tt.field != nil {
+				model.fields = append(model.fields, tt.field)
+			}
+			if tt.index != nil {
+				model.indexes = []Index{*tt.index}
+			}
+			if _, err := Compile(model); err == nil {
+				t.Error("Compile() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestCompile_PartitionedExclusion(t *testing.T) {
+	bookings := func(elements ...ExcludeElement) Model {
+		return &constrainedPartitionedModel{
+			partitionedModel: partitionedModel{
+				testModel: testModel{name: "bookings", fields: []Field{
+					&TextField{ColumnName: "region"},
+					&TextField{ColumnName: "during", CustomType: "tstzrange"},
+				}},
+				partitioning: Partitioning{Strategy: PartitionList, Column: "region"},
+			},
+			constraints: []TableConstraint{Exclude{Elements: elements}},
+		}
+	}
+
+	mustCompile(t, bookings(ExcludeElement{Column: "region", Operator: "="}, ExcludeElement{Column: "during", Operator: "&&"}))
+	if _, err := Compile(bookings(ExcludeElement{Column: "during", Operator: "&&"})); err == nil {
+		t.Error("Compile() with an exclusion constraint without the partition key error = nil, want error")
+	}
+	if _, err := Compile(bookings(ExcludeElement{Column: "region", Operator: "<>"}, ExcludeElement{Column: "during", Operator: "&&"})); err == nil {
+		t.Error("Compile() with an exclusion constraint not comparing the partition key with = error = nil, want error")
+	}
+}
+
+func TestDiff_Partitions(t *testing.T) {
+	fields := []Field{
+		&UUIDField{ColumnName: "id"},
+		&TimestampField{ColumnName: "created_at"},
+	}
+	from := mustCompile(t, &partitionedModel{
+		testModel: testModel{name: "shipment_events", fields: fields},
+		partitioning: Partitioning{
+			Strategy:   PartitionRange,
+			Column:     "created_at",
+			Partitions: []Partition{{Name: "shipment_events_2025_01", From: "2025-01-01", To: "2025-02-01"}},
+		},
+	})
+	to := mustCompile(t, &partitionedModel{
+		testModel: testModel{name: "shipment_events", fields: fields},
+		partitioning: Partitioning{
+			Strategy:   PartitionRange,
+			Column:     "created_at",
+			Partitions: []Partition{{Name: "shipment_events_2025_02", From: "2025-02-01", To: "2025-03-01"}},
+		},
+	})
+
+	got, err := Diff(from, to)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	assertStatements(t, got, []string{
+		`ALTER TABLE "shipment_events" DETACH PARTITION "shipment_events_2025_01";`,
+		`CREATE TABLE IF NOT EXISTS "shipment_events_2025_02" PARTITION OF "shipment_events" FOR VALUES FROM ('2025-02-01') TO ('2025-03-01');`,
+	})
+
+	list := mustCompile(t, &partitionedModel{testModel: testModel{name: "shipment_events", fields: fields}, partitioning: Partitioning{Strategy: PartitionList, Column: "created_at"}})
+	if _, err := Diff(from, list); err == nil {
+		t.Error("Diff() changing the partition strategy error = nil, want error")
+	}
+	plain := mustCompile(t, &testModel{name: "shipment_events", fields: fields})
+	if _, err := Diff(plain, from); err == nil {
+		t.Error("Diff() partitioning an existing table error = nil, want error")
+	}
+}
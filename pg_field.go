@@ -1,8 +1,12 @@
 package trenovaorm
 
 import (
+	"database/sql"
 	"database/sql/driver"
 	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/bytedance/sonic"
@@ -15,8 +19,16 @@ type TimeOnly struct {
 
 // Scan implements the Scanner interface.
 func (t *TimeOnly) Scan(value any) error {
-	str, ok := value.(string)
-	if !ok {
+	var str string
+	switch v := value.(type) {
+	case string:
+		str = v
+	case []byte:
+		str = string(v)
+	case time.Time:
+		t.Time = time.Date(0, 1, 1, v.Hour(), v.Minute(), v.Second(), 0, time.UTC)
+		return nil
+	default:
 		return fmt.Errorf("unsupported type %T, expected string", value)
 	}
 	parsedTime, err := time.Parse("15:04:05", str) // PostgreSQL 'time' format
@@ -60,3 +72,383 @@ func (t TimeOnly) Value() (driver.Value, error) {
 	}
 	return t.Time.Format("15:04:05"), nil // PostgreSQL 'time' format
 }
+
+// Array is a one-dimensional PostgreSQL array of T, as declared by an
+// ArrayField. Elements are converted with their own Scan and Value methods when
+// they have them, so Array[uuid.UUID] and Array[TimeOnly] work as expected, and
+// otherwise by their kind, so named string types such as generated enums work too.
+// A nil Array is stored as NULL and NULL elements are scanned as the zero value.
+type Array[T any] []T
+
+// Scan implements the Scanner interface.
+func (a *Array[T]) Scan(value any) error {
+	var str string
+	switch v := value.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case string:
+		str = v
+	case []byte:
+		str = string(v)
+	default:
+		return fmt.Errorf("unsupported type %T, expected string", value)
+	}
+
+	elems, nulls, err := parseArray(str)
+	if err != nil {
+		return err
+	}
+	arr := make(Array[T], len(elems))
+	for i, elem := range elems {
+		if nulls[i] {
+			continue
+		}
+		if err := scanArrayElement(&arr[i], elem); err != nil {
+			return fmt.Errorf("array element %d: %w", i, err)
+		}
+	}
+	*a = arr
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (a Array[T]) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, elem := range a {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		text, null, err := arrayElementText(elem)
+		if err != nil {
+			return nil, fmt.Errorf("array element %d: %w", i, err)
+		}
+		if null {
+			b.WriteString("NULL")
+			continue
+		}
+		b.WriteString(quoteArrayElement(text))
+	}
+	b.WriteByte('}')
+	return b.String(), nil
+}
+
+// parseArray splits a one-dimensional array in the PostgreSQL text format into
+// its elements, reporting which of them are NULL.
+func parseArray(s string) ([]string, []bool, error) {
+	if strings.HasPrefix(s, "[") {
+		// Skip a dimension decoration such as [0:1]=.
+		if i := strings.Index(s, "="); i > 0 {
+			s = s[i+1:]
+		}
+	}
+	if len(s) < 2 || s[0] != '{' || s[len(s)-1] != '}' {
+		return nil, nil, fmt.Errorf("malformed array literal %q", s)
+	}
+	body := s[1 : len(s)-1]
+	elems, nulls := []string{}, []bool{}
+	if strings.TrimSpace(body) == "" {
+		return elems, nulls, nil
+	}
+
+	for i := 0; ; {
+		for i < len(body) && body[i] == ' ' {
+			i++
+		}
+		var elem strings.Builder
+		quoted := i < len(body) && body[i] == '"'
+		if quoted {
+			for i++; i < len(body) && body[i] != '"'; i++ {
+				if body[i] == '\\' {
+					i++
+				}
+				if i < len(body) {
+					elem.WriteByte(body[i])
+				}
+			}
+			if i >= len(body) {
+				return nil, nil, fmt.Errorf("malformed array literal %q: unterminated element", s)
+			}
+			i++
+			for i < len(body) && body[i] == ' ' {
+				i++
+			}
+		} else {
+			for ; i < len(body) && body[i] != ','; i++ {
+				switch body[i] {
+				case '{', '}', '"':
+					return nil, nil, fmt.Errorf("malformed array literal %q: multidimensional arrays are not supported", s)
+				case '\\':
+					i++
+					if i == len(body) {
+						return nil, nil, fmt.Errorf("malformed array literal %q: trailing backslash", s)
+					}
+				}
+				elem.WriteByte(body[i])
+			}
+		}
+
+		text := elem.String()
+		if !quoted {
+			text = strings.TrimSpace(text)
+		}
+		elems = append(elems, text)
+		nulls = append(nulls, !quoted && strings.EqualFold(text, "NULL"))
+
+		if i == len(body) {
+			return elems, nulls, nil
+		}
+		if body[i] != ',' {
+			return nil, nil, fmt.Errorf("malformed array literal %q", s)
+		}
+		i++
+	}
+}
+
+// scanArrayElement converts the text of an array element into dst.
+func scanArrayElement(dst any, text string) error {
+	switch d := dst.(type) {
+	case sql.Scanner:
+		return d.Scan(text)
+	case *time.Time:
+		for _, layout := range []string{"2006-01-02 15:04:05.999999999Z07", "2006-01-02 15:04:05.999999999", "2006-01-02"} {
+			if t, err := time.Parse(layout, text); err == nil {
+				*d = t
+				return nil
+			}
+		}
+		return fmt.Errorf("parse time %q", text)
+	}
+
+	v := reflect.ValueOf(dst).Elem()
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(text)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(text, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(text, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(text, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported element type %s", v.Type())
+	}
+	return nil
+}
+
+// arrayElementText returns the text of an array element, or reports that it is NULL.
+func arrayElementText(elem any) (string, bool, error) {
+	if valuer, ok := elem.(driver.Valuer); ok {
+		value, err := valuer.Value()
+		if err != nil {
+			return "", false, err
+		}
+		if value == nil {
+			return "", true, nil
+		}
+		elem = value
+	}
+
+	switch e := elem.(type) {
+	case []byte:
+		return string(e), false, nil
+	case time.Time:
+		return e.Format(time.RFC3339Nano), false, nil
+	}
+
+	v := reflect.ValueOf(elem)
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), false, nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), false, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), false, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), false, nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), false, nil
+	default:
+		return "", false, fmt.Errorf("unsupported element type %T", elem)
+	}
+}
+
+// Interval wraps a time.Duration to scan and store PostgreSQL intervals. Months
+// and years are converted with the 30-day month and 365.25-day year that
+// PostgreSQL uses to compute the epoch of an interval.
+type Interval struct {
+	Duration time.Duration
+}
+
+// Scan implements the Scanner interface.
+func (i *Interval) Scan(value any) error {
+	var str string
+	switch v := value.(type) {
+	case string:
+		str = v
+	case []byte:
+		str = string(v)
+	default:
+		return fmt.Errorf("unsupported type %T, expected string", value)
+	}
+	d, err := parseInterval(str)
+	if err != nil {
+		return err
+	}
+	i.Duration = d
+	return nil
+}
+
+// MarshalJSON converts the Interval object to JSON.
+func (i Interval) MarshalJSON() ([]byte, error) {
+	return sonic.Marshal(formatInterval(i.Duration))
+}
+
+// UnmarshalJSON converts JSON data to an Interval object.
+func (i *Interval) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := sonic.Unmarshal(data, &str); err != nil {
+		return err
+	}
+	d, err := parseInterval(str)
+	if err != nil {
+		return err
+	}
+	i.Duration = d
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (i Interval) Value() (driver.Value, error) {
+	return formatInterval(i.Duration), nil
+}
+
+// intervalUnits maps the units of the PostgreSQL interval output format to their length.
+var intervalUnits = map[string]time.Duration{
+	"year":  time.Duration(365.25 * 24 * float64(time.Hour)),
+	"mon":   30 * 24 * time.Hour,
+	"month": 30 * 24 * time.Hour,
+	"week":  7 * 24 * time.Hour,
+	"day":   24 * time.Hour,
+	"hour":  time.Hour,
+	"min":   time.Minute,
+	"sec":   time.Second,
+}
+
+// formatInterval renders d as a PostgreSQL interval such as 26:03:04.5 or
+// -00:00:01, truncated to microseconds.
+func formatInterval(d time.Duration) string {
+	sign := ""
+	us := uint64(d / time.Microsecond)
+	if d < 0 {
+		sign = "-"
+		us = -us
+	}
+	secs := us / 1e6
+	out := fmt.Sprintf("%s%02d:%02d:%02d", sign, secs/3600, secs/60%60, secs%60)
+	if frac := us % 1e6; frac > 0 {
+		out += strings.TrimRight(fmt.Sprintf(".%06d", frac), "0")
+	}
+	return out
+}
+
+// parseInterval parses an interval in the PostgreSQL output format, such as
+// "1 year 2 mons -3 days +04:05:06.789".
+func parseInterval(s string) (time.Duration, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("invalid interval %q", s)
+	}
+	var total time.Duration
+	for i := 0; i < len(fields); i++ {
+		if strings.Contains(fields[i], ":") {
+			d, err := parseIntervalTime(fields[i])
+			if err != nil {
+				return 0, fmt.Errorf("invalid interval %q: %w", s, err)
+			}
+			total += d
+			continue
+		}
+		if i+1 == len(fields) {
+			return 0, fmt.Errorf("invalid interval %q: %s has no unit", s, fields[i])
+		}
+		n, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid interval %q: %w", s, err)
+		}
+		i++
+		unit, ok := intervalUnits[strings.TrimSuffix(strings.ToLower(fields[i]), "s")]
+		if !ok {
+			return 0, fmt.Errorf("invalid interval %q: unknown unit %s", s, fields[i])
+		}
+		total += time.Duration(n * float64(unit))
+	}
+	return total, nil
+}
+
+// parseIntervalTime parses the [+-]hh:mm[:ss[.ffffff]] part of an interval.
+func parseIntervalTime(s string) (time.Duration, error) {
+	sign := time.Duration(1)
+	if s[0] == '-' || s[0] == '+' {
+		if s[0] == '-' {
+			sign = -1
+		}
+		s = s[1:]
+	}
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("malformed time %q", s)
+	}
+	var d time.Duration
+	for i, unit := range []time.Duration{time.Hour, time.Minute} {
+		if i >= len(parts) {
+			break
+		}
+		n, err := strconv.ParseInt(parts[i], 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		d += time.Duration(n) * unit
+	}
+	if len(parts) == 3 {
+		secs, frac, _ := strings.Cut(parts[2], ".")
+		n, err := strconv.ParseInt(secs, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		d += time.Duration(n) * time.Second
+		if frac != "" {
+			if len(frac) > 9 {
+				frac = frac[:9]
+			}
+			nanos, err := strconv.ParseInt(frac+strings.Repeat("0", 9-len(frac)), 10, 64)
+			if err != nil {
+				return 0, err
+			}
+			d += time.Duration(nanos)
+		}
+	}
+	return sign * d, nil
+}
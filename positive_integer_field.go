@@ -8,6 +8,7 @@ import (
 // PositiveIntegerField represents a positive integer field in the database.
 type PositiveIntegerField struct {
 	ColumnName  string
+	PrimaryKey  bool
 	Nullable    bool
 	Unique      bool
 	Default     int
@@ -24,12 +25,16 @@ func (f *PositiveIntegerField) Definition() string {
 	if f.CustomType != "" {
 		typ = f.CustomType
 	}
-	def := fmt.Sprintf(`"%s" %s`, f.ColumnName, typ)
+	def := fmt.Sprintf("%s %s", QuoteIdentifier(f.ColumnName), typ)
 
 	if !f.Nullable {
 		def += " NOT NULL"
 	}
 
+	if f.PrimaryKey {
+		def += " PRIMARY KEY"
+	}
+
 	if f.Unique {
 		def += " UNIQUE"
 	}
@@ -58,7 +63,7 @@ func (f *PositiveIntegerField) CommentSQL(tableName string) string {
 	if f.Comment == "" {
 		return ""
 	}
-	return fmt.Sprintf(`COMMENT ON COLUMN "%s"."%s" IS '%s';`, tableName, f.ColumnName, f.Comment)
+	return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;", QuoteQualifiedName(tableName), QuoteIdentifier(f.ColumnName), QuoteLiteral(f.Comment))
 }
 
 // Validate checks if the field's configuration is valid.
@@ -71,6 +76,10 @@ func (f *PositiveIntegerField) Validate() error {
 		return fmt.Errorf("default value for positive integer field must be positive")
 	}
 
+	if f.PrimaryKey && f.Nullable {
+		return fmt.Errorf("primary key field cannot be nullable")
+	}
+
 	return nil
 }
 
@@ -91,6 +100,11 @@ func (f *PositiveIntegerField) IndexSQL(tableName string) string {
 	if f.Unique {
 		indexType = "UNIQUE INDEX"
 	}
-	indexName := fmt.Sprintf("%s_%s_idx", tableName, f.ColumnName)
-	return fmt.Sprintf(`CREATE %s "%s" ON "%s" ("%s");`, indexType, indexName, tableName, f.ColumnName)
+	indexName := f.IndexName(tableName)
+	return fmt.Sprintf("CREATE %s %s ON %s (%s);", indexType, QuoteIdentifier(indexName), QuoteQualifiedName(tableName), QuoteIdentifier(f.ColumnName))
+}
+
+// IndexName returns the name of the index generated by IndexSQL.
+func (f *PositiveIntegerField) IndexName(tableName string) string {
+	return fmt.Sprintf("%s_%s_idx", unqualifiedName(tableName), f.ColumnName)
 }
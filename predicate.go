@@ -0,0 +1,197 @@
+package trenovaorm
+
+import (
+	"errors"
+	"reflect"
+)
+
+// Predicate is a condition of a WHERE or HAVING clause. Values are always
+// passed as positional arguments and columns are validated against the model.
+type Predicate interface {
+	appendSQL(b *queryBuilder)
+}
+
+// comparison compares a column with a value.
+type comparison struct {
+	column string
+	op     string
+	value  any
+}
+
+func (c comparison) appendSQL(b *queryBuilder) {
+	b.column(c.column)
+	b.write(" " + c.op + " ")
+	b.arg(c.value)
+}
+
+// Eq matches rows where column equals value. A nil value, including a nil
+// pointer, matches rows where column is NULL, since = NULL never holds.
+func Eq(column string, value any) Predicate {
+	if isNil(value) {
+		return IsNull(column)
+	}
+	return comparison{column: column, op: "=", value: value}
+}
+
+// Ne matches rows where column does not equal value. A nil value, including a
+// nil pointer, matches rows where column is not NULL.
+func Ne(column string, value any) Predicate {
+	if isNil(value) {
+		return IsNotNull(column)
+	}
+	return comparison{column: column, op: "<>", value: value}
+}
+
+// isNil reports whether value is nil or a nil pointer.
+func isNil(value any) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	return v.Kind() == reflect.Pointer && v.IsNil()
+}
+
+// Gt matches rows where column is greater than value.
+func Gt(column string, value any) Predicate {
+	return comparison{column: column, op: ">", value: value}
+}
+
+// Ge matches rows where column is greater than or equal to value.
+func Ge(column string, value any) Predicate {
+	return comparison{column: column, op: ">=", value: value}
+}
+
+// Lt matches rows where column is less than value.
+func Lt(column string, value any) Predicate {
+	return comparison{column: column, op: "<", value: value}
+}
+
+// Le matches rows where column is less than or equal to value.
+func Le(column string, value any) Predicate {
+	return comparison{column: column, op: "<=", value: value}
+}
+
+// Like matches rows where column matches the LIKE pattern.
+func Like(column, pattern string) Predicate {
+	return comparison{column: column, op: "LIKE", value: pattern}
+}
+
+// ILike matches rows where column matches the pattern, ignoring case.
+func ILike(column, pattern string) Predicate {
+	return comparison{column: column, op: "ILIKE", value: pattern}
+}
+
+// inPredicate matches a column against a list of values.
+type inPredicate struct {
+	column string
+	values []any
+}
+
+func (p inPredicate) appendSQL(b *queryBuilder) {
+	if len(p.values) == 0 {
+		// IN () is a syntax error; an empty list matches nothing.
+		if _, err := b.scope.column(p.column); err != nil {
+			b.fail(err)
+		}
+		b.write("FALSE")
+		return
+	}
+	b.column(p.column)
+	b.write(" IN (")
+	for i, v := range p.values {
+		if i > 0 {
+			b.write(", ")
+		}
+		b.arg(v)
+	}
+	b.write(")")
+}
+
+// In matches rows where column equals one of values. An empty list matches no rows.
+func In(column string, values ...any) Predicate {
+	return inPredicate{column: column, values: values}
+}
+
+// between matches a column against an inclusive range.
+type between struct {
+	column    string
+	low, high any
+}
+
+func (p between) appendSQL(b *queryBuilder) {
+	b.column(p.column)
+	b.write(" BETWEEN ")
+	b.arg(p.low)
+	b.write(" AND ")
+	b.arg(p.high)
+}
+
+// Between matches rows where column lies between low and high, inclusive.
+func Between(column string, low, high any) Predicate {
+	return between{column: column, low: low, high: high}
+}
+
+// nullCheck matches a column against NULL.
+type nullCheck struct {
+	column string
+	not    bool
+}
+
+func (p nullCheck) appendSQL(b *queryBuilder) {
+	b.column(p.column)
+	if p.not {
+		b.write(" IS NOT NULL")
+		return
+	}
+	b.write(" IS NULL")
+}
+
+// IsNull matches rows where column is NULL.
+func IsNull(column string) Predicate {
+	return nullCheck{column: column}
+}
+
+// IsNotNull matches rows where column is not NULL.
+func IsNotNull(column string) Predicate {
+	return nullCheck{column: column, not: true}
+}
+
+// logicalPredicate combines predicates with AND or OR.
+type logicalPredicate struct {
+	op    string
+	preds []Predicate
+}
+
+func (p logicalPredicate) appendSQL(b *queryBuilder) {
+	if len(p.preds) == 0 {
+		b.fail(errors.New(p.op + " requires at least one predicate"))
+		return
+	}
+	b.predicates(p.op, p.preds)
+}
+
+// And matches rows that satisfy all predicates.
+func And(preds ...Predicate) Predicate {
+	return logicalPredicate{op: "AND", preds: preds}
+}
+
+// Or matches rows that satisfy at least one of the predicates.
+func Or(preds ...Predicate) Predicate {
+	return logicalPredicate{op: "OR", preds: preds}
+}
+
+// not negates a predicate.
+type not struct {
+	pred Predicate
+}
+
+func (p not) appendSQL(b *queryBuilder) {
+	b.write("NOT (")
+	p.pred.appendSQL(b)
+	b.write(")")
+}
+
+// Not matches rows that do not satisfy pred.
+func Not(pred Predicate) Predicate {
+	return not{pred: pred}
+}
@@ -0,0 +1,197 @@
+package trenovaorm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// aggregates are the functions accepted in column references, such as count(*) or sum(amount).
+var aggregates = map[string]bool{
+	"count": true,
+	"sum":   true,
+	"avg":   true,
+	"min":   true,
+	"max":   true,
+}
+
+// queryTable is a table a query reads from, with the columns defined by its model.
+// Columns of other tables are referenced by the unqualified table name.
+type queryTable struct {
+	name    string
+	schema  string
+	columns []string
+}
+
+func newQueryTable(model Model) queryTable {
+	t := queryTable{name: model.TableName(), schema: ModelSchemaName(model)}
+	for _, field := range ModelFields(model) {
+		t.columns = append(t.columns, field.Name())
+	}
+	return t
+}
+
+// sql returns the quoted name of the table, qualified with its schema.
+func (t queryTable) sql() string {
+	return QuoteQualifiedName(QualifyName(t.schema, t.name))
+}
+
+func (t queryTable) has(column string) bool {
+	for _, c := range t.columns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// queryScope resolves the column references of a query against the tables it reads.
+// The first table is the table of the query; columns of other tables are referenced
+// as "table.column". Aggregates are written as count(*) or sum(column).
+type queryScope struct {
+	tables []queryTable
+}
+
+// table returns the table with the given name.
+func (s *queryScope) table(name string) (queryTable, bool) {
+	for _, t := range s.tables {
+		if t.name == name {
+			return t, true
+		}
+	}
+	return queryTable{}, false
+}
+
+// column validates a column reference and returns it as quoted SQL.
+func (s *queryScope) column(ref string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	if open := strings.IndexByte(ref, '('); open > 0 && strings.HasSuffix(ref, ")") {
+		fn := strings.ToLower(ref[:open])
+		if !aggregates[fn] {
+			return "", fmt.Errorf("unknown aggregate %q", ref[:open])
+		}
+		arg := strings.TrimSpace(ref[open+1 : len(ref)-1])
+		if arg == "*" && fn == "count" {
+			return "COUNT(*)", nil
+		}
+		col, err := s.column(arg)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s(%s)", strings.ToUpper(fn), col), nil
+	}
+
+	table := s.tables[0]
+	column := ref
+	if dot := strings.IndexByte(ref, '.'); dot >= 0 {
+		var ok bool
+		if table, ok = s.table(ref[:dot]); !ok {
+			return "", fmt.Errorf("table %q is not part of the query", ref[:dot])
+		}
+		column = ref[dot+1:]
+	}
+	if !table.has(column) {
+		return "", fmt.Errorf("column %q does not exist on table %q", column, table.name)
+	}
+	if len(s.tables) > 1 {
+		return QuoteIdentifier(table.name) + "." + QuoteIdentifier(column), nil
+	}
+	return QuoteIdentifier(column), nil
+}
+
+// queryBuilder accumulates the SQL text and the positional arguments of a query.
+// The first error is kept and later writes are ignored.
+type queryBuilder struct {
+	scope *queryScope
+	sql   strings.Builder
+	args  []any
+	err   error
+	// inline renders values as literals instead of placeholders, for the
+	// queries of views, which cannot have parameters.
+	inline bool
+}
+
+// write appends raw SQL.
+func (b *queryBuilder) write(s string) {
+	b.sql.WriteString(s)
+}
+
+// arg appends a placeholder for value, or value itself as a literal.
+func (b *queryBuilder) arg(value any) {
+	if b.inline {
+		literal, ok := literalSQL(value)
+		if !ok {
+			b.fail(fmt.Errorf("value %v of type %T cannot be written as a literal", value, value))
+			return
+		}
+		b.sql.WriteString(literal)
+		return
+	}
+	b.args = append(b.args, value)
+	b.sql.WriteString("$" + strconv.Itoa(len(b.args)))
+}
+
+// column appends a validated, quoted column reference.
+func (b *queryBuilder) column(ref string) {
+	col, err := b.scope.column(ref)
+	if err != nil {
+		b.fail(err)
+		return
+	}
+	b.sql.WriteString(col)
+}
+
+// columns appends a comma-separated list of validated column references.
+func (b *queryBuilder) columns(refs []string) {
+	for i, ref := range refs {
+		if i > 0 {
+			b.write(", ")
+		}
+		b.column(ref)
+	}
+}
+
+// returning appends a RETURNING clause when columns are given.
+func (b *queryBuilder) returning(columns []string) {
+	if len(columns) == 0 {
+		return
+	}
+	for _, c := range columns {
+		if strings.Contains(c, "(") {
+			b.fail(fmt.Errorf("RETURNING does not accept aggregate %q", c))
+		}
+	}
+	b.write(" RETURNING ")
+	b.columns(columns)
+}
+
+// fail records err unless an earlier error was recorded.
+func (b *queryBuilder) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// predicates appends conditions joined by op, parenthesizing compound conditions.
+func (b *queryBuilder) predicates(op string, preds []Predicate) {
+	for i, p := range preds {
+		if i > 0 {
+			b.write(" " + op + " ")
+		}
+		if _, compound := p.(logicalPredicate); compound && len(preds) > 1 {
+			b.write("(")
+			p.appendSQL(b)
+			b.write(")")
+			continue
+		}
+		p.appendSQL(b)
+	}
+}
+
+// build returns the SQL text and arguments, or the first error recorded.
+func (b *queryBuilder) build() (string, []any, error) {
+	if b.err != nil {
+		return "", nil, b.err
+	}
+	return b.sql.String(), b.args, nil
+}
@@ -0,0 +1,23 @@
+package trenovaorm
+
+import "sync"
+
+var registry struct {
+	mu     sync.Mutex
+	models []Model
+}
+
+// Register adds models to the global registry used by the command-line tool.
+// Schema packages typically call it from a generated init function.
+func Register(models ...Model) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.models = append(registry.models, models...)
+}
+
+// Models returns the registered models in registration order.
+func Models() []Model {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	return append([]Model{}, registry.models...)
+}
@@ -0,0 +1,873 @@
+package trenovaorm
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// StatementKind identifies the kind of DDL statement in a compiled plan.
+type StatementKind string
+
+const (
+	StatementCreateExtension StatementKind = "CREATE EXTENSION"
+	StatementCreateSchema    StatementKind = "CREATE SCHEMA"
+	StatementCreateTable     StatementKind = "CREATE TABLE"
+	StatementComment         StatementKind = "COMMENT"
+	StatementCreateIndex     StatementKind = "CREATE INDEX"
+	StatementAlterTable      StatementKind = "ALTER TABLE"
+	StatementDropTable       StatementKind = "DROP TABLE"
+	StatementDropIndex       StatementKind = "DROP INDEX"
+	StatementCreateType      StatementKind = "CREATE TYPE"
+	StatementAlterType       StatementKind = "ALTER TYPE"
+	StatementDropType        StatementKind = "DROP TYPE"
+	StatementCreateView      StatementKind = "CREATE VIEW"
+	StatementDropView        StatementKind = "DROP VIEW"
+	StatementCreateSequence  StatementKind = "CREATE SEQUENCE"
+	StatementAlterSequence   StatementKind = "ALTER SEQUENCE"
+	StatementDropSequence    StatementKind = "DROP SEQUENCE"
+)
+
+// Statement is a single SQL statement produced by the schema compiler.
+type Statement struct {
+	Kind  StatementKind
+	Table string
+	SQL   string
+
+	// Index is the name of the index a CREATE INDEX or DROP INDEX statement
+	// builds or drops, qualified with its schema.
+	Index string
+
+	// Concurrent reports whether the statement builds or drops an index
+	// concurrently. PostgreSQL refuses such a statement inside a transaction
+	// block, so it must run on its own.
+	Concurrent bool
+}
+
+// String returns the SQL text of the statement.
+func (s Statement) String() string {
+	return s.SQL
+}
+
+// indexedField is implemented by fields that can emit their own index statement.
+type indexedField interface {
+	IndexSQL(tableName string) string
+	IndexName(tableName string) string
+}
+
+// Table is the compiled representation of a single Model.
+type Table struct {
+	// Name is the table name, qualified with the schema of the model if it
+	// has one, e.g. billing.invoices.
+	Name    string
+	Model   Model
+	Fields  []Field
+	Indexes []Index
+	// ForeignKeys holds the constraints of the ForeignKeyFields followed by
+	// the ForeignKey table constraints, which are not repeated in Constraints.
+	ForeignKeys []ForeignKey
+	Constraints []TableConstraint
+	Options     TableOptions
+	// Partitioning is the zero value unless the table is partitioned.
+	Partitioning Partitioning
+}
+
+// Field returns the field with the given column name, or nil if the table has no such column.
+func (t *Table) Field(name string) Field {
+	for _, field := range t.Fields {
+		if field.Name() == name {
+			return field
+		}
+	}
+	return nil
+}
+
+// PrimaryKey returns the names of the table's primary key columns: those of
+// its PrimaryKey constraint, or else the key columns in declaration order.
+func (t *Table) PrimaryKey() []string {
+	for _, c := range t.Constraints {
+		if pk, ok := asPrimaryKey(c); ok {
+			return pk.Columns
+		}
+	}
+	var columns []string
+	for _, field := range t.Fields {
+		if ColumnOf(field).PrimaryKey {
+			columns = append(columns, field.Name())
+		}
+	}
+	return columns
+}
+
+// CreateSQL generates the CREATE TABLE statement for the table.
+func (t *Table) CreateSQL() string {
+	return t.createSQL(nil)
+}
+
+// createSQL generates the CREATE TABLE statement for the table without the
+// given foreign keys, which are added separately with addForeignKeySQL.
+func (t *Table) createSQL(deferred []ForeignKey) string {
+	skip := make(map[string]bool, len(deferred))
+	for _, fk := range deferred {
+		skip[fk.ConstraintName(t.Name)] = true
+	}
+
+	definitions := make([]string, 0, len(t.Fields)+len(t.ForeignKeys)+len(t.Constraints))
+	for _, field := range t.Fields {
+		definitions = append(definitions, field.Definition())
+	}
+	for _, c := range t.Constraints {
+		definitions = append(definitions, constraintClause(t.Name, c))
+	}
+	for _, fk := range t.ForeignKeys {
+		if !skip[fk.ConstraintName(t.Name)] {
+			definitions = append(definitions, constraintClause(t.Name, fk))
+		}
+	}
+
+	unlogged := ""
+	if t.Options.Unlogged {
+		unlogged = "UNLOGGED "
+	}
+	sql := fmt.Sprintf("CREATE %sTABLE IF NOT EXISTS %s (%s)", unlogged, QuoteQualifiedName(t.Name), strings.Join(definitions, ", "))
+	if !t.Partitioning.IsZero() {
+		sql += " " + t.Partitioning.clause()
+	}
+	if len(t.Options.Storage) > 0 {
+		sql += fmt.Sprintf(" WITH (%s)", storageList(t.Options.Storage))
+	}
+	if t.Options.Tablespace != "" {
+		sql += fmt.Sprintf(" TABLESPACE %s", QuoteIdentifier(t.Options.Tablespace))
+	}
+	return sql + ";"
+}
+
+// PartitionSQL generates the CREATE TABLE statements for the table's partitions.
+func (t *Table) PartitionSQL() []string {
+	stmts := make([]string, len(t.Partitioning.Partitions))
+	for i, part := range t.Partitioning.Partitions {
+		stmts[i] = part.SQL(t.Name)
+	}
+	return stmts
+}
+
+// addForeignKeySQL generates the ALTER TABLE statement adding a foreign key to the table.
+func (t *Table) addForeignKeySQL(fk ForeignKey) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD %s;", QuoteQualifiedName(t.Name), constraintClause(t.Name, fk))
+}
+
+// CommentSQL generates the COMMENT ON statements for the table and its fields.
+func (t *Table) CommentSQL() []string {
+	var comments []string
+	if t.Options.Comment != "" {
+		comments = append(comments, tableCommentSQL(t.Name, t.Options.Comment))
+	}
+	for _, field := range t.Fields {
+		if sql := field.CommentSQL(t.Name); sql != "" {
+			comments = append(comments, sql)
+		}
+	}
+	return comments
+}
+
+// tableCommentSQL generates the COMMENT ON TABLE statement setting the comment
+// of a table, or removing it if comment is empty.
+func tableCommentSQL(table, comment string) string {
+	literal := "NULL"
+	if comment != "" {
+		literal = QuoteLiteral(comment)
+	}
+	return fmt.Sprintf("COMMENT ON TABLE %s IS %s;", QuoteQualifiedName(table), literal)
+}
+
+// IndexSQL generates the CREATE INDEX statements for field-level and model-level indexes.
+func (t *Table) IndexSQL() ([]string, error) {
+	defs, err := t.indexDefinitions()
+	if err != nil {
+		return nil, err
+	}
+	indexes := make([]string, len(defs))
+	for i, def := range defs {
+		indexes[i] = def.SQL
+	}
+	return indexes, nil
+}
+
+// indexDefinition is a compiled index of a table: its name, the schema it is
+// created in and the statement that creates it.
+type indexDefinition struct {
+	Name       string
+	Schema     string
+	SQL        string
+	Concurrent bool
+}
+
+// createStatement returns the statement creating the index on table.
+func (d indexDefinition) createStatement(table string) Statement {
+	return Statement{Kind: StatementCreateIndex, Table: table, SQL: d.SQL, Index: QualifyName(d.Schema, d.Name), Concurrent: d.Concurrent}
+}
+
+// indexDefinitions returns the table's field-level and model-level indexes in
+// declaration order.
+func (t *Table) indexDefinitions() ([]indexDefinition, error) {
+	var defs []indexDefinition
+	for _, field := range t.Fields {
+		if f, ok := field.(indexedField); ok {
+			if sql := f.IndexSQL(t.Name); sql != "" {
+				defs = append(defs, indexDefinition{Name: f.IndexName(t.Name), Schema: schemaOf(t.Name), SQL: sql})
+			}
+		}
+	}
+	for i := range t.Indexes {
+		sql, err := t.Indexes[i].SQL(t.Name)
+		if err != nil {
+			return nil, fmt.Errorf("index on %s: %w", t.Name, err)
+		}
+		defs = append(defs, indexDefinition{
+			Name:       t.Indexes[i].generateName(t.Name),
+			Schema:     schemaOf(t.Name),
+			SQL:        sql,
+			Concurrent: t.Indexes[i].Concurrently,
+		})
+	}
+	return defs, nil
+}
+
+// Schema is the compiled, validated form of a set of models.
+type Schema struct {
+	Tables []*Table
+	Views  []*CompiledView // views and materialized views, in declaration order
+	Enums  []EnumType      // enum types used by the tables, in order of first use
+}
+
+// Subset returns the part of the schema whose tables, views and enum types are
+// in one of the given PostgreSQL schemas, or unqualified for an empty name.
+// Foreign keys to tables outside the subset are kept.
+func (s *Schema) Subset(names ...string) *Schema {
+	subset := &Schema{}
+	for _, enum := range s.Enums {
+		if slices.Contains(names, enum.Schema) {
+			subset.Enums = append(subset.Enums, enum)
+		}
+	}
+	for _, table := range s.Tables {
+		if slices.Contains(names, schemaOf(table.Name)) {
+			subset.Tables = append(subset.Tables, table)
+		}
+	}
+	for _, view := range s.Views {
+		if slices.Contains(names, schemaOf(view.Name)) {
+			subset.Views = append(subset.Views, view)
+		}
+	}
+	return subset
+}
+
+// Extensions returns the extensions the tables and the indexes of materialized
+// views require, in order of first use.
+func (s *Schema) Extensions() []Extension {
+	var extensions []Extension
+	for _, table := range s.Tables {
+		extensions = append(extensions, table.Extensions()...)
+	}
+	for _, view := range s.Views {
+		extensions = append(extensions, indexExtensions(view.Indexes)...)
+	}
+	return uniqueExtensions(extensions)
+}
+
+// SchemaNames returns the PostgreSQL schemas of the tables and views, in order
+// of first use.
+func (s *Schema) SchemaNames() []string {
+	var names []string
+	for _, table := range s.Tables {
+		if schema := schemaOf(table.Name); schema != "" && !slices.Contains(names, schema) {
+			names = append(names, schema)
+		}
+	}
+	for _, view := range s.Views {
+		if schema := schemaOf(view.Name); schema != "" && !slices.Contains(names, schema) {
+			names = append(names, schema)
+		}
+	}
+	for _, enum := range s.Enums {
+		if enum.Schema != "" && !slices.Contains(names, enum.Schema) {
+			names = append(names, enum.Schema)
+		}
+	}
+	return names
+}
+
+// Compile validates the given models and compiles them into a Schema.
+func Compile(models ...Model) (*Schema, error) {
+	schema := &Schema{}
+	seen := make(map[string]bool, len(models))
+
+	for _, model := range models {
+		if IsView(model) {
+			view, err := compileView(model)
+			if err != nil {
+				return nil, err
+			}
+			if seen[view.Name] {
+				return nil, fmt.Errorf("table %s is defined more than once", view.Name)
+			}
+			seen[view.Name] = true
+			schema.Views = append(schema.Views, view)
+			continue
+		}
+
+		table, err := compileTable(model)
+		if err != nil {
+			return nil, err
+		}
+		if seen[table.Name] {
+			return nil, fmt.Errorf("table %s is defined more than once", table.Name)
+		}
+		seen[table.Name] = true
+		schema.Tables = append(schema.Tables, table)
+
+		for _, field := range table.Fields {
+			enum, ok := fieldEnum(field)
+			if !ok {
+				continue
+			}
+			existing, found := schema.Enum(enum.QualifiedName())
+			if !found {
+				schema.Enums = append(schema.Enums, enum)
+				continue
+			}
+			if !slices.Equal(existing.Values, enum.Values) {
+				return nil, fmt.Errorf("table %s: field %s: enum type %s is declared with different values",
+					table.Name, field.Name(), enum.QualifiedName())
+			}
+		}
+	}
+
+	for _, table := range schema.Tables {
+		schema.resolveForeignKeys(table)
+	}
+	if err := schema.validateReferences(); err != nil {
+		return nil, err
+	}
+	if err := schema.validateViews(); err != nil {
+		return nil, err
+	}
+	for _, view := range schema.Views {
+		schema.resolveViewFields(view)
+	}
+
+	return schema, nil
+}
+
+// validateViews checks that the tables and views each view reads are in the
+// schema and that no view depends on itself.
+func (s *Schema) validateViews() error {
+	for _, view := range s.Views {
+		for _, name := range view.Reads {
+			if s.Table(name) == nil && s.View(name) == nil {
+				return fmt.Errorf("view %s reads %s, which is not in the schema", view.Name, name)
+			}
+		}
+	}
+	_, err := viewOrder(s.Views)
+	return err
+}
+
+// validateReferences checks every foreign key against the table it references:
+// the table and columns must exist in the schema, the columns must be its
+// primary key or a unique key, and the referencing columns must have the same
+// SQL types and declared Go types as the columns they reference. A logged table
+// cannot reference an unlogged one.
+func (s *Schema) validateReferences() error {
+	for _, table := range s.Tables {
+		for _, fk := range table.ForeignKeys {
+			if err := s.validateReference(table, fk); err != nil {
+				return fmt.Errorf("table %s: foreign key %s: %w", table.Name, fk.ConstraintName(table.Name), err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateReference checks a single foreign key of table.
+func (s *Schema) validateReference(table *Table, fk ForeignKey) error {
+	ref := s.referencedTable(table, fk.ReferenceTable)
+	if ref == nil {
+		return fmt.Errorf("referenced table %s does not exist", fk.ReferenceTable)
+	}
+	if ref.Options.Unlogged && !table.Options.Unlogged {
+		return fmt.Errorf("logged table cannot reference unlogged table %s", ref.Name)
+	}
+
+	for i, name := range fk.ReferenceColumns {
+		refField := ref.Field(name)
+		if refField == nil {
+			return fmt.Errorf("referenced column %s.%s does not exist", ref.Name, name)
+		}
+		field := table.Field(fk.Columns[i])
+
+		typ, refType := ColumnOf(field).Type, referenceType(ColumnOf(refField).Type)
+		if typ != "" && refType != "" && !strings.EqualFold(typ, refType) {
+			return fmt.Errorf("column %s of type %s cannot reference %s.%s of type %s", field.Name(), typ, ref.Name, name, refType)
+		}
+
+		// The Go type is only declared by the field of a single-column key.
+		f, ok := field.(*ForeignKeyField)
+		if !ok || s.referencedTable(table, f.ReferenceTable) != ref || f.ReferenceField != name || f.ReferencedType == "" {
+			continue
+		}
+		if goType := strings.TrimPrefix(refField.GoType(), "*"); goType != "" && goType != f.ReferencedType {
+			return fmt.Errorf("column %s declares Go type %s but %s.%s has Go type %s", field.Name(), f.ReferencedType, ref.Name, name, goType)
+		}
+	}
+
+	if !isUniqueKey(ref.Model, fk.ReferenceColumns) {
+		return fmt.Errorf("referenced columns (%s) are not the primary key or a unique key of %s", strings.Join(fk.ReferenceColumns, ", "), ref.Name)
+	}
+	return nil
+}
+
+// resolveForeignKeys fills in the SQL and Go types of foreign keys that leave
+// them unset from the column they reference, so that a key referencing a
+// BIGINT or uuid column is declared with the same type, and qualifies the
+// referenced tables with their schema. The fields of the model are not
+// modified; the table holds resolved copies.
+func (s *Schema) resolveForeignKeys(table *Table) {
+	var keys []ForeignKey
+	for i, field := range table.Fields {
+		fk, ok := field.(*ForeignKeyField)
+		if !ok {
+			continue
+		}
+		if fk.CustomType == "" || fk.ReferencedType == "" {
+			if ref := s.referencedField(table, fk); ref != nil {
+				resolved := *fk
+				if resolved.CustomType == "" {
+					resolved.CustomType = referenceType(ColumnOf(ref).Type)
+				}
+				if resolved.ReferencedType == "" {
+					resolved.ReferencedType = strings.TrimPrefix(ref.GoType(), "*")
+				}
+				fk = &resolved
+				table.Fields[i] = fk
+			}
+		}
+		keys = append(keys, fk.ForeignKey())
+	}
+	table.ForeignKeys = append(keys, table.ForeignKeys...)
+
+	for i, fk := range table.ForeignKeys {
+		if ref := s.referencedTable(table, fk.ReferenceTable); ref != nil {
+			table.ForeignKeys[i].ReferenceTable = ref.Name
+		}
+	}
+}
+
+// resolveViewFields gives the view columns that read foreign keys the
+// resolved copies held by the tables the view reads, directly or through
+// other views, so that they have the SQL and Go types of the key.
+func (s *Schema) resolveViewFields(view *CompiledView) {
+	for _, field := range view.Fields {
+		vf, ok := field.(*ViewField)
+		if !ok {
+			continue
+		}
+		if fk, ok := vf.Source.(*ForeignKeyField); ok && (fk.CustomType == "" || fk.ReferencedType == "") {
+			if resolved := s.readForeignKey(view.Reads, fk); resolved != nil {
+				vf.Source = resolved
+			}
+		}
+	}
+}
+
+// readForeignKey returns the compiled copy of fk held by one of the named
+// tables, or by the tables the named views read, or nil if none holds it.
+func (s *Schema) readForeignKey(names []string, fk *ForeignKeyField) *ForeignKeyField {
+	for _, name := range names {
+		if table := s.Table(name); table != nil {
+			resolved, ok := table.Field(fk.ColumnName).(*ForeignKeyField)
+			if ok && resolved.ReferenceTable == fk.ReferenceTable && resolved.ReferenceField == fk.ReferenceField {
+				return resolved
+			}
+		}
+		if view := s.View(name); view != nil {
+			if resolved := s.readForeignKey(view.Reads, fk); resolved != nil {
+				return resolved
+			}
+		}
+	}
+	return nil
+}
+
+// referencedTable returns the table a foreign key of table references, or nil
+// if the schema does not contain it. An unqualified name refers to the table
+// of that name in the schema of table or, failing that, to the unqualified
+// table of that name.
+func (s *Schema) referencedTable(table *Table, name string) *Table {
+	if schemaOf(name) == "" {
+		if ref := s.Table(QualifyName(schemaOf(table.Name), name)); ref != nil {
+			return ref
+		}
+	}
+	return s.Table(name)
+}
+
+// referencedField returns the field referenced by a foreign key field of
+// table, or nil if the schema does not contain it.
+func (s *Schema) referencedField(table *Table, fk *ForeignKeyField) Field {
+	ref := s.referencedTable(table, fk.ReferenceTable)
+	if ref == nil {
+		return nil
+	}
+	return ref.Field(fk.ReferenceField)
+}
+
+// referenceType returns the type of a column referencing a column of type typ:
+// the serial types reference through their underlying integer types.
+func referenceType(typ string) string {
+	switch strings.ToUpper(typ) {
+	case "SERIAL":
+		return "INTEGER"
+	case "BIGSERIAL":
+		return "BIGINT"
+	case "SMALLSERIAL":
+		return "SMALLINT"
+	default:
+		return typ
+	}
+}
+
+// enumSchemaFields returns fields with the enum types that have no schema
+// placed in schema, the schema of their table. Fields are copied, not changed.
+func enumSchemaFields(fields []Field, schema string) []Field {
+	if schema == "" {
+		return fields
+	}
+	qualified := make([]Field, len(fields))
+	for i, field := range fields {
+		qualified[i] = enumSchemaField(field, schema)
+	}
+	return qualified
+}
+
+// enumSchemaField returns field with its enum type placed in schema if it has
+// none, or field itself.
+func enumSchemaField(field Field, schema string) Field {
+	switch f := field.(type) {
+	case *EnumField:
+		if f.TypeSchema == "" {
+			qualified := *f
+			qualified.TypeSchema = schema
+			return &qualified
+		}
+	case *ArrayField:
+		if element := enumSchemaField(f.Element, schema); element != f.Element {
+			qualified := *f
+			qualified.Element = element
+			return &qualified
+		}
+	case *GeneratedField:
+		if inner := enumSchemaField(f.Field, schema); inner != f.Field {
+			qualified := *f
+			qualified.Field = inner
+			return &qualified
+		}
+	}
+	return field
+}
+
+// fieldEnum returns the enum type used by an EnumField or an ArrayField of one,
+// generated or not.
+func fieldEnum(field Field) (EnumType, bool) {
+	switch f := field.(type) {
+	case *EnumField:
+		return f.Enum(), true
+	case *ArrayField:
+		return f.Enum()
+	case *GeneratedField:
+		return fieldEnum(f.Field)
+	}
+	return EnumType{}, false
+}
+
+// compileTable merges the model's fields with its mixin fields and validates the result.
+func compileTable(model Model) (*Table, error) {
+	name := model.TableName()
+	if name == "" {
+		return nil, errors.New("table name cannot be empty")
+	}
+	schema := ModelSchemaName(model)
+	if err := validateTableName(schema, name); err != nil {
+		return nil, err
+	}
+	name = QualifyName(schema, name)
+
+	table := &Table{
+		Name:         name,
+		Model:        model,
+		Fields:       enumSchemaFields(ModelFields(model), schema),
+		Indexes:      model.Indexes(),
+		Constraints:  ModelConstraints(model),
+		Options:      ModelTableOptions(model),
+		Partitioning: ModelPartitioning(model),
+	}
+
+	if err := table.Options.Validate(); err != nil {
+		return nil, fmt.Errorf("table %s: %w", name, err)
+	}
+	for _, ext := range ModelExtensions(model) {
+		if err := ext.Validate(); err != nil {
+			return nil, fmt.Errorf("table %s: %w", name, err)
+		}
+	}
+
+	columns := make(map[string]bool, len(table.Fields))
+	for _, field := range table.Fields {
+		if err := field.Validate(); err != nil {
+			return nil, fmt.Errorf("table %s: field %s: %w", name, field.Name(), err)
+		}
+		if columns[field.Name()] {
+			return nil, fmt.Errorf("table %s: column %s is defined more than once", name, field.Name())
+		}
+		columns[field.Name()] = true
+	}
+
+	for _, field := range table.Fields {
+		generated, ok := field.(*GeneratedField)
+		if !ok {
+			continue
+		}
+		for _, col := range generated.Expression.ColumnNames() {
+			if !columns[col] {
+				return nil, fmt.Errorf("table %s: generated column %s references unknown column %s", name, field.Name(), col)
+			}
+			if ref := table.Field(col); ref != nil && ColumnOf(ref).Generated != "" {
+				return nil, fmt.Errorf("table %s: generated column %s references generated column %s", name, field.Name(), col)
+			}
+		}
+	}
+
+	for i := range table.Indexes {
+		if err := table.Indexes[i].Validate(); err != nil {
+			return nil, fmt.Errorf("table %s: index: %w", name, err)
+		}
+		for _, col := range table.Indexes[i].columnNames() {
+			if !columns[col] {
+				return nil, fmt.Errorf("table %s: index references unknown column %s", name, col)
+			}
+		}
+	}
+
+	if err := validateConstraints(table, columns); err != nil {
+		return nil, err
+	}
+
+	if !table.Partitioning.IsZero() {
+		if err := validatePartitioning(table, columns); err != nil {
+			return nil, err
+		}
+	}
+
+	// Foreign keys are rendered and diffed apart from the other constraints,
+	// so table-level ones are kept with those of the fields.
+	constraints := table.Constraints[:0:0]
+	for _, c := range table.Constraints {
+		if fk, ok := asForeignKey(c); ok {
+			table.ForeignKeys = append(table.ForeignKeys, fk)
+		} else {
+			constraints = append(constraints, c)
+		}
+	}
+	table.Constraints = constraints
+
+	return table, nil
+}
+
+// validateConstraints checks the table constraints, that the columns they
+// refer to exist, that their names are distinct and that the table has at most
+// one primary key. A composite key is declared by a PrimaryKey constraint, not
+// by the PrimaryKey flag of several fields.
+func validateConstraints(table *Table, columns map[string]bool) error {
+	names := make(map[string]bool, len(table.Constraints))
+	var columnKey []string
+	for _, field := range table.Fields {
+		if ColumnOf(field).PrimaryKey {
+			columnKey = append(columnKey, field.Name())
+		}
+		if fk, ok := field.(*ForeignKeyField); ok {
+			names[fk.ConstraintName(table.Name)] = true
+		}
+	}
+	primaryKeys := 0
+
+	for _, c := range table.Constraints {
+		if c == nil {
+			return fmt.Errorf("table %s: nil constraint", table.Name)
+		}
+		name := c.ConstraintName(table.Name)
+		if err := c.Validate(); err != nil {
+			return fmt.Errorf("table %s: constraint %s: %w", table.Name, name, err)
+		}
+		if names[name] {
+			return fmt.Errorf("table %s: constraint %s is defined more than once", table.Name, name)
+		}
+		names[name] = true
+		for _, col := range c.ColumnNames() {
+			if !columns[col] {
+				return fmt.Errorf("table %s: constraint %s references unknown column %s", table.Name, name, col)
+			}
+		}
+		if _, ok := asPrimaryKey(c); ok {
+			primaryKeys++
+		}
+	}
+
+	if len(columnKey) > 1 {
+		return fmt.Errorf("table %s: columns %s are each declared as the primary key; declare a PrimaryKey constraint for a composite key",
+			table.Name, strings.Join(columnKey, ", "))
+	}
+	if primaryKeys > 1 || (primaryKeys == 1 && len(columnKey) > 0) {
+		return fmt.Errorf("table %s has more than one primary key", table.Name)
+	}
+	return nil
+}
+
+// Table returns the compiled table with the given name, qualified with its
+// schema if it has one, or nil if the schema has no such table.
+func (s *Schema) Table(name string) *Table {
+	for _, table := range s.Tables {
+		if table.Name == name {
+			return table
+		}
+	}
+	return nil
+}
+
+// View returns the compiled view with the given name, qualified with its
+// schema if it has one, or nil if the schema has no such view.
+func (s *Schema) View(name string) *CompiledView {
+	for _, view := range s.Views {
+		if view.Name == name {
+			return view
+		}
+	}
+	return nil
+}
+
+// Enum returns the enum type with the given name, qualified with its schema
+// if it has one.
+func (s *Schema) Enum(name string) (EnumType, bool) {
+	for _, enum := range s.Enums {
+		if enum.QualifiedName() == name {
+			return enum, true
+		}
+	}
+	return EnumType{}, false
+}
+
+// Statements returns the ordered DDL plan for the schema: every CREATE
+// EXTENSION the tables require, followed by every CREATE SCHEMA, followed by
+// every CREATE TYPE, followed by every CREATE TABLE in dependency order, each
+// followed by the partitions of the table, followed by the foreign keys that
+// close a cycle, followed by comments, followed by indexes, followed by every
+// view in dependency order, followed by the comments and indexes of views.
+func (s *Schema) Statements() ([]Statement, error) {
+	var stmts []Statement
+
+	for _, ext := range s.Extensions() {
+		stmts = append(stmts, Statement{Kind: StatementCreateExtension, SQL: ext.CreateSQL()})
+	}
+
+	for _, schema := range s.SchemaNames() {
+		stmts = append(stmts, Statement{Kind: StatementCreateSchema, SQL: createSchemaSQL(schema)})
+	}
+
+	for _, enum := range s.Enums {
+		stmts = append(stmts, Statement{Kind: StatementCreateType, SQL: enum.CreateSQL()})
+	}
+
+	tables, deferred := createOrder(s.Tables)
+	for _, table := range tables {
+		stmts = append(stmts, Statement{Kind: StatementCreateTable, Table: table.Name, SQL: table.createSQL(deferred[table.Name])})
+		for _, sql := range table.PartitionSQL() {
+			stmts = append(stmts, Statement{Kind: StatementCreateTable, Table: table.Name, SQL: sql})
+		}
+	}
+	for _, table := range tables {
+		for _, fk := range deferred[table.Name] {
+			stmts = append(stmts, Statement{Kind: StatementAlterTable, Table: table.Name, SQL: table.addForeignKeySQL(fk)})
+		}
+	}
+
+	for _, table := range s.Tables {
+		for _, sql := range table.CommentSQL() {
+			stmts = append(stmts, Statement{Kind: StatementComment, Table: table.Name, SQL: sql})
+		}
+	}
+
+	for _, table := range s.Tables {
+		indexes, err := table.indexDefinitions()
+		if err != nil {
+			return nil, err
+		}
+		for _, idx := range indexes {
+			stmts = append(stmts, idx.createStatement(table.Name))
+		}
+	}
+
+	views, err := viewOrder(s.Views)
+	if err != nil {
+		return nil, err
+	}
+	for _, view := range views {
+		stmts = append(stmts, Statement{Kind: StatementCreateView, Table: view.Name, SQL: view.CreateSQL()})
+	}
+	for _, view := range views {
+		for _, sql := range view.CommentSQL() {
+			stmts = append(stmts, Statement{Kind: StatementComment, Table: view.Name, SQL: sql})
+		}
+	}
+	for _, view := range views {
+		indexes, err := view.table().indexDefinitions()
+		if err != nil {
+			return nil, err
+		}
+		for _, idx := range indexes {
+			stmts = append(stmts, idx.createStatement(view.Name))
+		}
+	}
+
+	return stmts, nil
+}
+
+// SQL renders the schema's DDL plan as a single script with one statement per line.
+func (s *Schema) SQL() (string, error) {
+	stmts, err := s.Statements()
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for _, stmt := range stmts {
+		b.WriteString(stmt.SQL)
+		b.WriteString("\n")
+	}
+	return b.String(), nil
+}
+
+// ModelFields returns the model's own fields followed by the fields of each of
+// its mixins. The fields of a view model are the columns its query selects, or
+// nil if the query is invalid.
+func ModelFields(model Model) []Field {
+	if view, _, ok := modelView(model); ok {
+		fields, _ := view.fields()
+		return fields
+	}
+	fields := append([]Field{}, model.Fields()...)
+	for _, mixin := range model.Mixins() {
+		fields = append(fields, mixin.Fields()...)
+	}
+	return fields
+}
@@ -0,0 +1,299 @@
+package trenovaorm
+
+import "testing"
+
+type testModel struct {
+	BaseModel
+	name    string
+	fields  []Field
+	indexes []Index
+	mixins  []Mixin
+}
+
+func (m *testModel) TableName() string {
+	return m.name
+}
+
+func (m *testModel) Fields() []Field {
+	return m.fields
+}
+
+func (m *testModel) Indexes() []Index {
+	return m.indexes
+}
+
+func (m *testModel) Mixins() []Mixin {
+	return m.mixins
+}
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name    string
+		models  []Model
+		wantErr bool
+	}{
+		{
+			name: "Valid model",
+			models: []Model{&testModel{
+				name:   "users",
+				fields: []Field{&UUIDField{ColumnName: "id", PrimaryKey: true}},
+			}},
+			wantErr: false,
+		},
+		{
+			name: "Invalid field",
+			models: []Model{&testModel{
+				name:   "users",
+				fields: []Field{&CharField{ColumnName: "name"}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "Duplicate column from mixin",
+			models: []Model{&testModel{
+				name:   "users",
+				fields: []Field{&DateField{ColumnName: "created_at"}},
+				mixins: []Mixin{TimestampedMixin{}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "Index on unknown column",
+			models: []Model{&testModel{
+				name:    "users",
+				fields:  []Field{&UUIDField{ColumnName: "id"}},
+				indexes: []Index{{Columns: []string{"email"}}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "Enum type declared with different values",
+			models: []Model{
+				&testModel{name: "users", fields: []Field{
+					&EnumField{ColumnName: "status", TypeName: "status", Values: []string{"active", "inactive"}},
+				}},
+				&testModel{name: "roles", fields: []Field{
+					&EnumField{ColumnName: "status", TypeName: "status", Values: []string{"active"}},
+				}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Enum array declared with different values",
+			models: []Model{
+				&testModel{name: "users", fields: []Field{
+					&EnumField{ColumnName: "status", TypeName: "status", Values: []string{"active", "inactive"}},
+				}},
+				&testModel{name: "roles", fields: []Field{
+					&ArrayField{ColumnName: "statuses", Element: &EnumField{TypeName: "status", Values: []string{"active"}}},
+				}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Reference to unknown table",
+			models: []Model{&testModel{name: "users", fields: []Field{
+				&ForeignKeyField{ColumnName: "role_id", ReferenceTable: "roles", ReferenceField: "id"},
+			}}},
+			wantErr: true,
+		},
+		{
+			name: "Reference to unknown column",
+			models: []Model{
+				&testModel{name: "roles", fields: []Field{&IntegerField{ColumnName: "id", PrimaryKey: true}}},
+				&testModel{name: "users", fields: []Field{
+					&ForeignKeyField{ColumnName: "role_id", ReferenceTable: "roles", ReferenceField: "uuid"},
+				}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Reference to a column that is not unique",
+			models: []Model{
+				&testModel{name: "roles", fields: []Field{
+					&IntegerField{ColumnName: "id", PrimaryKey: true},
+					&IntegerField{ColumnName: "rank"},
+				}},
+				&testModel{name: "users", fields: []Field{
+					&ForeignKeyField{ColumnName: "role_rank", ReferenceTable: "roles", ReferenceField: "rank"},
+				}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Reference with a different type",
+			models: []Model{
+				&testModel{name: "roles", fields: []Field{&BigIntegerField{ColumnName: "id", PrimaryKey: true}}},
+				&testModel{name: "users", fields: []Field{
+					&ForeignKeyField{ColumnName: "role_id", ReferenceTable: "roles", ReferenceField: "id", CustomType: "INTEGER"},
+				}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Reference with a different Go type",
+			models: []Model{
+				&testModel{name: "roles", fields: []Field{&UUIDField{ColumnName: "id", PrimaryKey: true}}},
+				&testModel{name: "users", fields: []Field{
+					&ForeignKeyField{ColumnName: "role_id", ReferenceTable: "roles", ReferenceField: "id", ReferencedType: "string"},
+				}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Reference to a unique key",
+			models: []Model{
+				&testModel{name: "roles", fields: []Field{
+					&IntegerField{ColumnName: "id", PrimaryKey: true},
+					&CharField{ColumnName: "code", MaxLength: 10, Unique: true},
+				}},
+				&testModel{name: "users", fields: []Field{
+					&ForeignKeyField{ColumnName: "role_code", ReferenceTable: "roles", ReferenceField: "code"},
+				}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Duplicate table",
+			models: []Model{
+				&testModel{name: "users", fields: []Field{&UUIDField{ColumnName: "id"}}},
+				&testModel{name: "users", fields: []Field{&UUIDField{ColumnName: "id"}}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Compile(tt.models...); (err != nil) != tt.wantErr {
+				t.Errorf("Compile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSchema_Statements(t *testing.T) {
+	model := &testModel{
+		name: "users",
+		fields: []Field{
+			&UUIDField{ColumnName: "id", PrimaryKey: true, Comment: "Identifier"},
+			&CharField{ColumnName: "email", MaxLength: 255, Index: true},
+			&ForeignKeyField{
+				ColumnName:     "role_id",
+				ReferenceTable: "roles",
+				ReferenceField: "id",
+				Annotations:    Annotation{OnDelete: OnDeleteCascade},
+			},
+		},
+		indexes: []Index{{Name: "users_email_key", Columns: []string{"email"}, Unique: true}},
+	}
+	roles := &testModel{name: "roles", fields: []Field{&IntegerField{ColumnName: "id", PrimaryKey: true}}}
+
+	schema, err := Compile(model, roles)
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+
+	got, err := schema.Statements()
+	if err != nil {
+		t.Fatalf("Schema.Statements() error = %v", err)
+	}
+
+	want := []Statement{
+		{
+			Kind:  StatementCreateTable,
+			Table: "roles",
+			SQL:   `CREATE TABLE IF NOT EXISTS "roles" ("id" INTEGER NOT NULL PRIMARY KEY);`,
+		},
+		{
+			Kind:  StatementCreateTable,
+			Table: "users",
+			SQL:   `CREATE TABLE IF NOT EXISTS "users" ("id" uuid NOT NULL PRIMARY KEY, "email" VARCHAR(255) NOT NULL, "role_id" INTEGER NOT NULL, CONSTRAINT "users_role_id_fkey" FOREIGN KEY ("role_id") REFERENCES "roles"("id") ON DELETE CASCADE);`,
+		},
+		{
+			Kind:  StatementComment,
+			Table: "users",
+			SQL:   `COMMENT ON COLUMN "users"."id" IS 'Identifier';`,
+		},
+		{
+			Kind:  StatementCreateIndex,
+			Table: "users",
+			SQL:   `CREATE INDEX "users_email_idx" ON "users" ("email");`,
+			Index: "users_email_idx",
+		},
+		{
+			Kind:  StatementCreateIndex,
+			Table: "users",
+			SQL:   `CREATE UNIQUE INDEX IF NOT EXISTS "users_email_key" ON "users" ("email");`,
+			Index: "users_email_key",
+		},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("Schema.Statements() returned %d statements, want %d: %v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Schema.Statements()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestCompile_ForeignKeyTypes(t *testing.T) {
+	accounts := &testModel{name: "accounts", fields: []Field{
+		&BigIntegerField{ColumnName: "id", PrimaryKey: true, Serial: true},
+		&UUIDField{ColumnName: "uuid", Unique: true},
+	}}
+	fk := &ForeignKeyField{ColumnName: "account_id", ReferenceTable: "accounts", ReferenceField: "id"}
+	sessions := &testModel{name: "sessions", fields: []Field{
+		fk,
+		&ForeignKeyField{ColumnName: "account_uuid", ReferenceTable: "accounts", ReferenceField: "uuid"},
+		&ForeignKeyField{ColumnName: "owner_id", ReferenceTable: "accounts", ReferenceField: "id", CustomType: "bigint"},
+	}}
+
+	schema := mustCompile(t, accounts, sessions)
+	table := schema.Table("sessions")
+
+	want := []struct{ def, goType string }{
+		{`"account_id" BIGINT NOT NULL`, "int64"},
+		{`"account_uuid" uuid NOT NULL`, "uuid.UUID"},
+		{`"owner_id" bigint NOT NULL`, "int64"},
+	}
+	for i, w := range want {
+		if got := table.Fields[i].Definition(); got != w.def {
+			t.Errorf("field %d Definition() = %v, want %v", i, got, w.def)
+		}
+		if got := table.Fields[i].GoType(); got != w.goType {
+			t.Errorf("field %d GoType() = %v, want %v", i, got, w.goType)
+		}
+		if got := table.ForeignKeys[i].Columns; len(got) != 1 || got[0] != table.Fields[i].Name() {
+			t.Errorf("ForeignKeys[%d].Columns = %v, want [%v]", i, got, table.Fields[i].Name())
+		}
+	}
+	if fk.CustomType != "" || fk.ReferencedType != "" {
+		t.Errorf("Compile() modified the model's field: %+v", fk)
+	}
+}
+
+func TestModelFields(t *testing.T) {
+	model := &testModel{
+		name:   "users",
+		fields: []Field{&UUIDField{ColumnName: "id"}},
+		mixins: []Mixin{TimestampedMixin{}},
+	}
+
+	var got []string
+	for _, field := range ModelFields(model) {
+		got = append(got, field.Name())
+	}
+
+	want := []string{"id", "created_at", "updated_at"}
+	if len(got) != len(want) {
+		t.Fatalf("ModelFields() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("ModelFields()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package trenovaorm
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// SelectQuery builds a parameterized SELECT statement over the table of a model.
+// Column references are validated against the models of the query when the
+// statement is built: plain names refer to the model's table, "table.column"
+// to a joined table, and count(*), sum(column), avg, min and max to aggregates.
+type SelectQuery struct {
+	models  []Model // the model of the query followed by the joined models
+	scope   queryScope
+	columns []string
+	joins   []join
+	where   []Predicate
+	groupBy []string
+	having  []Predicate
+	orderBy []order
+	limit   *int
+	offset  *int
+	err     error
+}
+
+// join is a join of another table along a foreign key.
+type join struct {
+	kind  string
+	table queryTable
+	on    [][2]string // the joined column pairs, as "table.column" references
+}
+
+// order is a column of the ORDER BY clause.
+type order struct {
+	column string
+	desc   bool
+}
+
+// Select starts a query on the table of model. Without columns, every column
+// of the model, including its mixin columns, is selected.
+func Select(model Model, columns ...string) *SelectQuery {
+	return &SelectQuery{
+		models:  []Model{model},
+		scope:   queryScope{tables: []queryTable{newQueryTable(model)}},
+		columns: columns,
+	}
+}
+
+// Where adds predicates to the WHERE clause. All predicates must hold.
+func (q *SelectQuery) Where(preds ...Predicate) *SelectQuery {
+	q.where = append(q.where, preds...)
+	return q
+}
+
+// Join adds an INNER JOIN of model along the only foreign key between it and
+// the tables already in the query. Foreign keys are those of ForeignKeyFields
+// and of ForeignKey table constraints; a composite key joins on all of its
+// columns.
+func (q *SelectQuery) Join(model Model) *SelectQuery {
+	return q.addJoin("JOIN", model, "")
+}
+
+// LeftJoin adds a LEFT JOIN of model along the only foreign key between it and
+// the tables already in the query.
+func (q *SelectQuery) LeftJoin(model Model) *SelectQuery {
+	return q.addJoin("LEFT JOIN", model, "")
+}
+
+// JoinOn adds an INNER JOIN of model along the foreign key holding column,
+// which may be declared by model or by a table already in the query.
+func (q *SelectQuery) JoinOn(model Model, column string) *SelectQuery {
+	return q.addJoin("JOIN", model, column)
+}
+
+// LeftJoinOn adds a LEFT JOIN of model along the foreign key holding column,
+// which may be declared by model or by a table already in the query.
+func (q *SelectQuery) LeftJoinOn(model Model, column string) *SelectQuery {
+	return q.addJoin("LEFT JOIN", model, column)
+}
+
+// GroupBy adds columns to the GROUP BY clause.
+func (q *SelectQuery) GroupBy(columns ...string) *SelectQuery {
+	q.groupBy = append(q.groupBy, columns...)
+	return q
+}
+
+// Having adds predicates to the HAVING clause. All predicates must hold.
+func (q *SelectQuery) Having(preds ...Predicate) *SelectQuery {
+	q.having = append(q.having, preds...)
+	return q
+}
+
+// OrderBy adds an ascending column to the ORDER BY clause.
+func (q *SelectQuery) OrderBy(column string) *SelectQuery {
+	q.orderBy = append(q.orderBy, order{column: column})
+	return q
+}
+
+// OrderByDesc adds a descending column to the ORDER BY clause.
+func (q *SelectQuery) OrderByDesc(column string) *SelectQuery {
+	q.orderBy = append(q.orderBy, order{column: column, desc: true})
+	return q
+}
+
+// Limit sets the maximum number of rows returned.
+func (q *SelectQuery) Limit(n int) *SelectQuery {
+	q.limit = &n
+	return q
+}
+
+// Offset sets the number of rows skipped.
+func (q *SelectQuery) Offset(n int) *SelectQuery {
+	q.offset = &n
+	return q
+}
+
+// Build returns the SELECT statement with $n placeholders and its arguments,
+// or an error if the query references unknown tables or columns.
+func (q *SelectQuery) Build() (string, []any, error) {
+	return q.build(&queryBuilder{scope: &q.scope})
+}
+
+// build writes the SELECT statement with b.
+func (q *SelectQuery) build(b *queryBuilder) (string, []any, error) {
+	if q.err != nil {
+		return "", nil, q.err
+	}
+
+	b.write("SELECT ")
+	columns := q.columns
+	if len(columns) == 0 {
+		base := q.scope.tables[0]
+		for _, c := range base.columns {
+			columns = append(columns, base.name+"."+c)
+		}
+	}
+	b.columns(columns)
+
+	b.write(" FROM " + q.scope.tables[0].sql())
+	for _, j := range q.joins {
+		b.write(fmt.Sprintf(" %s %s ON ", j.kind, j.table.sql()))
+		for i, pair := range j.on {
+			if i > 0 {
+				b.write(" AND ")
+			}
+			b.column(pair[0])
+			b.write(" = ")
+			b.column(pair[1])
+		}
+	}
+
+	if len(q.where) > 0 {
+		b.write(" WHERE ")
+		b.predicates("AND", q.where)
+	}
+	if len(q.groupBy) > 0 {
+		b.write(" GROUP BY ")
+		b.columns(q.groupBy)
+	}
+	if len(q.having) > 0 {
+		if len(q.groupBy) == 0 {
+			b.fail(errors.New("HAVING requires GROUP BY"))
+		}
+		b.write(" HAVING ")
+		b.predicates("AND", q.having)
+	}
+	if len(q.orderBy) > 0 {
+		b.write(" ORDER BY ")
+		for i, o := range q.orderBy {
+			if i > 0 {
+				b.write(", ")
+			}
+			b.column(o.column)
+			if o.desc {
+				b.write(" DESC")
+			}
+		}
+	}
+	if q.limit != nil {
+		b.write(" LIMIT ")
+		b.arg(*q.limit)
+	}
+	if q.offset != nil {
+		b.write(" OFFSET ")
+		b.arg(*q.offset)
+	}
+	return b.build()
+}
+
+// addJoin resolves the foreign key between model and the tables of the query.
+func (q *SelectQuery) addJoin(kind string, model Model, column string) *SelectQuery {
+	if q.err != nil {
+		return q
+	}
+
+	target := newQueryTable(model)
+	if _, ok := q.scope.table(target.name); ok {
+		q.err = fmt.Errorf("table %q is already part of the query", target.name)
+		return q
+	}
+
+	var candidates []join
+	// Foreign keys from the joined model to a table of the query.
+	for _, fk := range ModelForeignKeys(model) {
+		if column != "" && !slices.Contains(fk.Columns, column) {
+			continue
+		}
+		if ref, ok := q.scope.table(unqualifiedName(fk.ReferenceTable)); ok {
+			candidates = append(candidates, join{kind: kind, table: target, on: joinColumnPairs(target.name, fk.Columns, ref.name, fk.ReferenceColumns)})
+		}
+	}
+	// Foreign keys from a table of the query to the joined model.
+	for _, fk := range q.foreignKeys() {
+		if unqualifiedName(fk.key.ReferenceTable) != target.name || (column != "" && !slices.Contains(fk.key.Columns, column)) {
+			continue
+		}
+		candidates = append(candidates, join{kind: kind, table: target, on: joinColumnPairs(fk.table, fk.key.Columns, target.name, fk.key.ReferenceColumns)})
+	}
+
+	switch {
+	case len(candidates) == 0 && column != "":
+		q.err = fmt.Errorf("no foreign key %q between table %q and the query", column, target.name)
+	case len(candidates) == 0:
+		q.err = fmt.Errorf("no foreign key between table %q and the query", target.name)
+	case len(candidates) > 1:
+		q.err = fmt.Errorf("several foreign keys between table %q and the query; use JoinOn", target.name)
+	default:
+		q.models = append(q.models, model)
+		q.scope.tables = append(q.scope.tables, target)
+		q.joins = append(q.joins, candidates[0])
+	}
+	return q
+}
+
+// tableForeignKey is a foreign key declared by a table of the query.
+type tableForeignKey struct {
+	table string
+	key   ForeignKey
+}
+
+// foreignKeys returns the foreign keys declared by the models already in the query.
+func (q *SelectQuery) foreignKeys() []tableForeignKey {
+	var fks []tableForeignKey
+	for _, model := range q.models {
+		for _, fk := range ModelForeignKeys(model) {
+			fks = append(fks, tableForeignKey{table: model.TableName(), key: fk})
+		}
+	}
+	return fks
+}
+
+// joinColumnPairs pairs the columns of a foreign key with the columns they
+// reference, as "table.column" references.
+func joinColumnPairs(table string, columns []string, refTable string, refColumns []string) [][2]string {
+	pairs := make([][2]string, len(columns))
+	for i, col := range columns {
+		pairs[i] = [2]string{table + "." + col, refTable + "." + refColumns[i]}
+	}
+	return pairs
+}
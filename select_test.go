@@ -0,0 +1,172 @@
+package trenovaorm
+
+import (
+	"reflect"
+	"testing"
+)
+
+var (
+	queryOrganizations = &testModel{
+		name: "organizations",
+		fields: []Field{
+			&UUIDField{ColumnName: "id", PrimaryKey: true},
+			&CharField{ColumnName: "name", MaxLength: 100},
+		},
+	}
+	queryUsers = &testModel{
+		name: "users",
+		fields: []Field{
+			&UUIDField{ColumnName: "id", PrimaryKey: true},
+			&CharField{ColumnName: "email", MaxLength: 255},
+			&IntegerField{ColumnName: "age", Nullable: true},
+			&ForeignKeyField{ColumnName: "organization_id", ReferenceTable: "organizations", ReferenceField: "id"},
+		},
+	}
+	queryShipments = &testModel{
+		name: "shipments",
+		fields: []Field{
+			&UUIDField{ColumnName: "id", PrimaryKey: true},
+			&ForeignKeyField{ColumnName: "created_by", ReferenceTable: "users", ReferenceField: "id"},
+			&ForeignKeyField{ColumnName: "updated_by", ReferenceTable: "users", ReferenceField: "id", Nullable: true},
+		},
+	}
+)
+
+func TestSelectQuery_Build(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    *SelectQuery
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:    "All columns",
+			query:   Select(queryUsers),
+			wantSQL: `SELECT "id", "email", "age", "organization_id" FROM "users"`,
+		},
+		{
+			name: "Predicates",
+			query: Select(queryUsers, "id", "email").Where(
+				Eq("email", "a@example.com"),
+				In("id", 1, 2),
+				Between("age", 18, 65),
+				Or(IsNull("age"), ILike("email", "%@example.com")),
+				Not(IsNotNull("organization_id")),
+			),
+			wantSQL: `SELECT "id", "email" FROM "users" WHERE "email" = $1 AND "id" IN ($2, $3) AND "age" BETWEEN $4 AND $5 ` +
+				`AND ("age" IS NULL OR "email" ILIKE $6) AND NOT ("organization_id" IS NOT NULL)`,
+			wantArgs: []any{"a@example.com", 1, 2, 18, 65, "%@example.com"},
+		},
+		{
+			name: "Nil comparisons",
+			query: Select(queryUsers, "id").Where(
+				Eq("age", nil),
+				Ne("organization_id", (*string)(nil)),
+				Ne("email", "a@example.com"),
+			),
+			wantSQL:  `SELECT "id" FROM "users" WHERE "age" IS NULL AND "organization_id" IS NOT NULL AND "email" <> $1`,
+			wantArgs: []any{"a@example.com"},
+		},
+		{
+			name:    "Empty IN list",
+			query:   Select(queryUsers, "id").Where(In("id")),
+			wantSQL: `SELECT "id" FROM "users" WHERE FALSE`,
+		},
+		{
+			name:     "Order, limit and offset",
+			query:    Select(queryUsers, "id").OrderByDesc("age").OrderBy("email").Limit(10).Offset(20),
+			wantSQL:  `SELECT "id" FROM "users" ORDER BY "age" DESC, "email" LIMIT $1 OFFSET $2`,
+			wantArgs: []any{10, 20},
+		},
+		{
+			name: "Join along the referencing model",
+			query: Select(queryUsers, "email", "organizations.name").
+				Join(queryOrganizations).
+				Where(Eq("organizations.name", "Acme")),
+			wantSQL: `SELECT "users"."email", "organizations"."name" FROM "users" ` +
+				`JOIN "organizations" ON "users"."organization_id" = "organizations"."id" WHERE "organizations"."name" = $1`,
+			wantArgs: []any{"Acme"},
+		},
+		{
+			name:  "Join along the referenced model",
+			query: Select(queryOrganizations, "name").LeftJoin(queryUsers),
+			wantSQL: `SELECT "organizations"."name" FROM "organizations" ` +
+				`LEFT JOIN "users" ON "users"."organization_id" = "organizations"."id"`,
+		},
+		{
+			name:  "Join on a named foreign key",
+			query: Select(queryShipments, "id").JoinOn(queryUsers, "updated_by"),
+			wantSQL: `SELECT "shipments"."id" FROM "shipments" ` +
+				`JOIN "users" ON "shipments"."updated_by" = "users"."id"`,
+		},
+		{
+			name: "Join along a composite foreign key",
+			query: Select(queryUsers, "email").Join(&constrainedModel{
+				testModel: testModel{
+					name: "memberships",
+					fields: []Field{
+						&UUIDField{ColumnName: "user_id"},
+						&UUIDField{ColumnName: "organization_id"},
+					},
+				},
+				constraints: []TableConstraint{ForeignKey{
+					Columns:          []string{"user_id", "organization_id"},
+					ReferenceTable:   "users",
+					ReferenceColumns: []string{"id", "organization_id"},
+				}},
+			}),
+			wantSQL: `SELECT "users"."email" FROM "users" JOIN "memberships" ` +
+				`ON "memberships"."user_id" = "users"."id" AND "memberships"."organization_id" = "users"."organization_id"`,
+		},
+		{
+			name: "Group by and having",
+			query: Select(queryUsers, "organization_id", "count(*)").
+				GroupBy("organization_id").
+				Having(Gt("count(*)", 5), Le("max(age)", 90)),
+			wantSQL: `SELECT "organization_id", COUNT(*) FROM "users" GROUP BY "organization_id" ` +
+				`HAVING COUNT(*) > $1 AND MAX("age") <= $2`,
+			wantArgs: []any{5, 90},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := tt.query.Build()
+			if err != nil {
+				t.Fatalf("SelectQuery.Build() error = %v", err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("SelectQuery.Build() sql = %v, want %v", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("SelectQuery.Build() args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestSelectQuery_BuildErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query *SelectQuery
+	}{
+		{name: "Unknown column", query: Select(queryUsers, "name")},
+		{name: "Unknown predicate column", query: Select(queryUsers).Where(Eq("name", "x"))},
+		{name: "Unknown order column", query: Select(queryUsers).OrderBy("created_at")},
+		{name: "Column of a table outside the query", query: Select(queryUsers, "organizations.name")},
+		{name: "Unknown aggregate", query: Select(queryUsers, "median(age)").GroupBy("id")},
+		{name: "No foreign key", query: Select(queryOrganizations).Join(queryShipments)},
+		{name: "Ambiguous foreign key", query: Select(queryShipments).Join(queryUsers)},
+		{name: "Table joined twice", query: Select(queryUsers).Join(queryOrganizations).Join(queryOrganizations)},
+		{name: "Having without group by", query: Select(queryUsers).Having(Gt("count(*)", 1))},
+		{name: "Empty OR", query: Select(queryUsers).Where(Or())},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := tt.query.Build(); err == nil {
+				t.Error("SelectQuery.Build() error = nil, want error")
+			}
+		})
+	}
+}
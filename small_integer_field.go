@@ -0,0 +1,109 @@
+package trenovaorm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SmallIntegerField represents a smallint field in the database.
+type SmallIntegerField struct {
+	ColumnName  string
+	PrimaryKey  bool
+	Serial      bool     // Use SMALLSERIAL, backed by a sequence
+	Identity    Identity // Declare the column GENERATED ... AS IDENTITY
+	Nullable    bool
+	Unique      bool
+	Default     int16
+	Index       bool
+	Comment     string
+	CustomType  string
+	Constraints []string
+	StructTag   string
+}
+
+// Definition generates the SQL definition for the SmallIntegerField.
+func (f *SmallIntegerField) Definition() string {
+	def := fmt.Sprintf("%s %s", QuoteIdentifier(f.ColumnName), f.sqlType())
+
+	if !f.Nullable {
+		def += fmt.Sprintf(" %s", ConstraintNotNull.String())
+	}
+
+	if identity := f.Identity.SQL(); identity != "" {
+		def += " " + identity
+	}
+
+	if f.PrimaryKey {
+		def += fmt.Sprintf(" %s", ConstraintPrimaryKey.String())
+	}
+
+	if f.Unique {
+		def += fmt.Sprintf(" %s", ConstraintUnqiue.String())
+	}
+
+	if f.Default != 0 {
+		def += fmt.Sprintf(" DEFAULT %d", f.Default)
+	}
+
+	if len(f.Constraints) > 0 {
+		def += " " + strings.Join(f.Constraints, " ")
+	}
+
+	return def
+}
+
+// Name returns the column name for the SmallIntegerField.
+func (f *SmallIntegerField) Name() string {
+	return f.ColumnName
+}
+
+// CommentSQL generates the SQL statement for adding a comment to the SmallIntegerField.
+func (f *SmallIntegerField) CommentSQL(tableName string) string {
+	if f.Comment == "" {
+		return ""
+	}
+	return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;", QuoteQualifiedName(tableName), QuoteIdentifier(f.ColumnName), QuoteLiteral(f.Comment))
+}
+
+// Validate checks if the field's configuration is valid.
+func (f *SmallIntegerField) Validate() error {
+	if f.ColumnName == "" {
+		return fmt.Errorf("column name cannot be empty")
+	}
+
+	return validateInteger(f.Nullable, f.PrimaryKey, f.Serial, f.Default != 0, f.Identity)
+}
+
+// GoType returns the Go type for the SmallIntegerField.
+func (f *SmallIntegerField) GoType() string {
+	if f.Nullable {
+		return "*int16"
+	}
+	return "int16"
+}
+
+// IndexSQL generates the SQL statement for creating an index if Index is true.
+func (f *SmallIntegerField) IndexSQL(tableName string) string {
+	if !f.Index {
+		return ""
+	}
+	indexName := f.IndexName(tableName)
+	return fmt.Sprintf("CREATE INDEX %s ON %s (%s);", QuoteIdentifier(indexName), QuoteQualifiedName(tableName), QuoteIdentifier(f.ColumnName))
+}
+
+// IndexName returns the name of the index generated by IndexSQL.
+func (f *SmallIntegerField) IndexName(tableName string) string {
+	return fmt.Sprintf("idx_%s_%s", unqualifiedName(tableName), f.ColumnName)
+}
+
+// sqlType returns the SQL type of the column.
+func (f *SmallIntegerField) sqlType() string {
+	switch {
+	case f.CustomType != "":
+		return f.CustomType
+	case f.Serial:
+		return "SMALLSERIAL"
+	default:
+		return "SMALLINT"
+	}
+}
@@ -0,0 +1,345 @@
+package trenovaorm
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// TableConstraint is a constraint declared on a table rather than on a single
+// column, such as a composite primary key.
+type TableConstraint interface {
+	// ConstraintName returns the name of the constraint on the given table.
+	ConstraintName(tableName string) string
+	// Definition returns the constraint clause without its CONSTRAINT name prefix.
+	Definition() string
+	// ColumnNames returns the columns of the table the constraint refers to.
+	ColumnNames() []string
+	Validate() error
+}
+
+// ConstraintModel is implemented by models that declare table-level constraints.
+// It is optional, so models without constraints need not implement it.
+type ConstraintModel interface {
+	Constraints() []TableConstraint
+}
+
+// ModelConstraints returns the table-level constraints of a model, or nil if
+// the model does not implement ConstraintModel.
+func ModelConstraints(model Model) []TableConstraint {
+	if m, ok := model.(ConstraintModel); ok {
+		return m.Constraints()
+	}
+	return nil
+}
+
+// ModelPrimaryKey returns the primary key columns of a model, declared either
+// by a PrimaryKey table constraint or by the columns themselves.
+func ModelPrimaryKey(model Model) []string {
+	for _, c := range ModelConstraints(model) {
+		if pk, ok := asPrimaryKey(c); ok {
+			return pk.Columns
+		}
+	}
+	var columns []string
+	for _, field := range ModelFields(model) {
+		if ColumnOf(field).PrimaryKey {
+			columns = append(columns, field.Name())
+		}
+	}
+	return columns
+}
+
+// ModelForeignKeys returns the foreign keys of a model, declared either by
+// ForeignKeyFields or by ForeignKey table constraints, in that order.
+func ModelForeignKeys(model Model) []ForeignKey {
+	var keys []ForeignKey
+	for _, field := range ModelFields(model) {
+		if fk, ok := field.(*ForeignKeyField); ok {
+			keys = append(keys, fk.ForeignKey())
+		}
+	}
+	for _, c := range ModelConstraints(model) {
+		if fk, ok := asForeignKey(c); ok {
+			keys = append(keys, fk)
+		}
+	}
+	return keys
+}
+
+// constraintClause renders a table constraint as it appears in CREATE TABLE
+// and ALTER TABLE ... ADD.
+func constraintClause(tableName string, c TableConstraint) string {
+	return fmt.Sprintf("CONSTRAINT %s %s", QuoteIdentifier(c.ConstraintName(tableName)), c.Definition())
+}
+
+// asPrimaryKey returns the constraint as a PrimaryKey if it is one.
+func asPrimaryKey(c TableConstraint) (PrimaryKey, bool) {
+	switch pk := c.(type) {
+	case PrimaryKey:
+		return pk, true
+	case *PrimaryKey:
+		return *pk, true
+	}
+	return PrimaryKey{}, false
+}
+
+// asUnique returns the constraint as a Unique if it is one.
+func asUnique(c TableConstraint) (Unique, bool) {
+	switch u := c.(type) {
+	case Unique:
+		return u, true
+	case *Unique:
+		return *u, true
+	}
+	return Unique{}, false
+}
+
+// asForeignKey returns the constraint as a ForeignKey if it is one.
+func asForeignKey(c TableConstraint) (ForeignKey, bool) {
+	switch fk := c.(type) {
+	case ForeignKey:
+		return fk, true
+	case *ForeignKey:
+		return *fk, true
+	}
+	return ForeignKey{}, false
+}
+
+// asExclude returns the constraint as an Exclude if it is one.
+func asExclude(c TableConstraint) (Exclude, bool) {
+	switch x := c.(type) {
+	case Exclude:
+		return x, true
+	case *Exclude:
+		return *x, true
+	}
+	return Exclude{}, false
+}
+
+// validateColumns checks that a constraint lists distinct, non-empty columns.
+func validateColumns(columns []string) error {
+	if len(columns) == 0 {
+		return errors.New("at least one column must be specified")
+	}
+	seen := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		if col == "" {
+			return errors.New("column name cannot be empty")
+		}
+		if seen[col] {
+			return fmt.Errorf("column %s is listed more than once", col)
+		}
+		seen[col] = true
+	}
+	return nil
+}
+
+// PrimaryKey is a primary key over one or more columns, e.g. the composite key
+// of a join table. A table can have only one primary key.
+type PrimaryKey struct {
+	Name    string // Constraint name, defaults to <table>_pkey
+	Columns []string
+}
+
+// ConstraintName returns the name of the constraint on the given table.
+func (c PrimaryKey) ConstraintName(tableName string) string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return unqualifiedName(tableName) + "_pkey"
+}
+
+// Definition returns the PRIMARY KEY clause.
+func (c PrimaryKey) Definition() string {
+	return fmt.Sprintf("PRIMARY KEY (%s)", joinColumns(c.Columns))
+}
+
+// ColumnNames returns the key columns.
+func (c PrimaryKey) ColumnNames() []string {
+	return c.Columns
+}
+
+// Validate checks the key columns.
+func (c PrimaryKey) Validate() error {
+	if err := validateColumns(c.Columns); err != nil {
+		return fmt.Errorf("primary key: %w", err)
+	}
+	return nil
+}
+
+// Unique requires the combination of the columns to be unique.
+type Unique struct {
+	Name    string // Constraint name, defaults to <table>_<columns>_key
+	Columns []string
+	// NullsNotDistinct treats NULLs as equal, so that only one row may have
+	// NULL in the columns. It requires PostgreSQL 15.
+	NullsNotDistinct bool
+}
+
+// ConstraintName returns the name of the constraint on the given table.
+func (c Unique) ConstraintName(tableName string) string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return fmt.Sprintf("%s_%s_key", unqualifiedName(tableName), strings.Join(c.Columns, "_"))
+}
+
+// Definition returns the UNIQUE clause.
+func (c Unique) Definition() string {
+	def := "UNIQUE"
+	if c.NullsNotDistinct {
+		def += " NULLS NOT DISTINCT"
+	}
+	return fmt.Sprintf("%s (%s)", def, joinColumns(c.Columns))
+}
+
+// ColumnNames returns the unique columns.
+func (c Unique) ColumnNames() []string {
+	return c.Columns
+}
+
+// Validate checks the unique columns.
+func (c Unique) Validate() error {
+	if err := validateColumns(c.Columns); err != nil {
+		return fmt.Errorf("unique constraint: %w", err)
+	}
+	return nil
+}
+
+// Check requires a boolean SQL expression to hold for every row. The
+// expression is either built as Condition, whose columns are checked to exist,
+// or written as Expression.
+type Check struct {
+	Name       string     // Constraint name, defaults to <table>_<columns>_check
+	Expression string     // SQL expression, used verbatim
+	Condition  Expression // Used instead of Expression when set
+	Columns    []string   // Columns used by Expression, checked to exist on the table
+}
+
+// ConstraintName returns the name of the constraint on the given table.
+func (c Check) ConstraintName(tableName string) string {
+	if c.Name != "" {
+		return c.Name
+	}
+	columns := c.ColumnNames()
+	if len(columns) == 0 {
+		return unqualifiedName(tableName) + "_check"
+	}
+	return fmt.Sprintf("%s_%s_check", unqualifiedName(tableName), strings.Join(columns, "_"))
+}
+
+// Definition returns the CHECK clause.
+func (c Check) Definition() string {
+	if c.Condition != nil {
+		return fmt.Sprintf("CHECK (%s)", c.Condition.Expression())
+	}
+	return fmt.Sprintf("CHECK (%s)", c.Expression)
+}
+
+// ColumnNames returns the columns used by the expression.
+func (c Check) ColumnNames() []string {
+	if c.Condition != nil {
+		columns := slices.Clone(c.Columns)
+		for _, col := range c.Condition.ColumnNames() {
+			if !slices.Contains(columns, col) {
+				columns = append(columns, col)
+			}
+		}
+		return columns
+	}
+	return c.Columns
+}
+
+// Validate checks that the constraint has exactly one valid expression.
+func (c Check) Validate() error {
+	if c.Condition != nil {
+		if c.Expression != "" {
+			return errors.New("check constraint: expression and condition cannot both be set")
+		}
+		if err := validateExpression(c.Condition); err != nil {
+			return fmt.Errorf("check constraint: %w", err)
+		}
+		return nil
+	}
+	if strings.TrimSpace(c.Expression) == "" {
+		return errors.New("check constraint: expression cannot be empty")
+	}
+	return nil
+}
+
+// ExcludeElement is a column of an exclusion constraint and the operator
+// compared across rows, e.g. {Column: "during", Operator: "&&"}.
+type ExcludeElement struct {
+	Column   string
+	Operator string
+}
+
+// Exclude ensures that no two rows satisfy all the element operators at once,
+// e.g. that two bookings of the same room do not overlap.
+type Exclude struct {
+	Name     string // Constraint name, defaults to <table>_<columns>_excl
+	Using    string // Index access method, defaults to gist
+	Elements []ExcludeElement
+	Where    string // Optional SQL predicate limiting the constraint to some rows
+}
+
+var (
+	exclusionOperator = regexp.MustCompile(`^[-+*/<>=~!@#%^&|` + "`" + `?]+$`)
+	accessMethod      = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+)
+
+// ConstraintName returns the name of the constraint on the given table.
+func (c Exclude) ConstraintName(tableName string) string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return fmt.Sprintf("%s_%s_excl", unqualifiedName(tableName), strings.Join(c.ColumnNames(), "_"))
+}
+
+// Definition returns the EXCLUDE clause.
+func (c Exclude) Definition() string {
+	using := c.Using
+	if using == "" {
+		using = "gist"
+	}
+	elements := make([]string, len(c.Elements))
+	for i, e := range c.Elements {
+		elements[i] = fmt.Sprintf("%s WITH %s", QuoteIdentifier(e.Column), e.Operator)
+	}
+	def := fmt.Sprintf("EXCLUDE USING %s (%s)", using, strings.Join(elements, ", "))
+	if c.Where != "" {
+		def += fmt.Sprintf(" WHERE (%s)", c.Where)
+	}
+	return def
+}
+
+// ColumnNames returns the columns of the elements.
+func (c Exclude) ColumnNames() []string {
+	columns := make([]string, len(c.Elements))
+	for i, e := range c.Elements {
+		columns[i] = e.Column
+	}
+	return columns
+}
+
+// Validate checks the access method and the elements.
+func (c Exclude) Validate() error {
+	if c.Using != "" && !accessMethod.MatchString(c.Using) {
+		return fmt.Errorf("exclusion constraint: invalid access method %q", c.Using)
+	}
+	if len(c.Elements) == 0 {
+		return errors.New("exclusion constraint: at least one element must be specified")
+	}
+	for _, e := range c.Elements {
+		if e.Column == "" {
+			return errors.New("exclusion constraint: column name cannot be empty")
+		}
+		if !exclusionOperator.MatchString(e.Operator) {
+			return fmt.Errorf("exclusion constraint: invalid operator %q", e.Operator)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,339 @@
+package trenovaorm
+
+import "testing"
+
+type constrainedModel struct {
+	testModel
+	constraints []TableConstraint
+}
+
+func (m *constrainedModel) Constraints() []TableConstraint {
+	return m.constraints
+}
+
+func TestDiff_AddTableConstraint(t *testing.T) {
+	fields := []Field{
+		&UUIDField{ColumnName: "user_id"},
+		&UUIDField{ColumnName: "role_id"},
+		&IntegerField{ColumnName: "position"},
+		&JSONField{ColumnName: "flags"},
+		&TextField{ColumnName: "during", CustomType: "tstzrange"},
+	}
+	tests := []struct {
+		name       string
+		constraint TableConstraint
+		want       []string
+	}{
+		{
+			name:       "Composite primary key",
+			constraint: PrimaryKey{Columns: []string{"user_id", "role_id"}},
+			want:       []string{`ALTER TABLE "user_roles" ADD CONSTRAINT "user_roles_pkey" PRIMARY KEY ("user_id", "role_id");`},
+		},
+		{
+			name:       "Unique",
+			constraint: Unique{Columns: []string{"role_id", "position"}},
+			want:       []string{`ALTER TABLE "user_roles" ADD CONSTRAINT "user_roles_role_id_position_key" UNIQUE ("role_id", "position");`},
+		},
+		{
+			name:       "Unique nulls not distinct",
+			constraint: &Unique{Name: "one_null", Columns: []string{"position"}, NullsNotDistinct: true},
+			want:       []string{`ALTER TABLE "user_roles" ADD CONSTRAINT "one_null" UNIQUE NULLS NOT DISTINCT ("position");`},
+		},
+		{
+			name:       "Check",
+			constraint: Check{Expression: `"position" >= 0`, Columns: []string{"position"}},
+			want:       []string{`ALTER TABLE "user_roles" ADD CONSTRAINT "user_roles_position_check" CHECK ("position" >= 0);`},
+		},
+		{
+			name:       "Check without columns",
+			constraint: Check{Expression: "true"},
+			want:       []string{`ALTER TABLE "user_roles" ADD CONSTRAINT "user_roles_check" CHECK (true);`},
+		},
+		{
+			name: "Check with condition",
+			constraint: Check{Condition: AnyOf{Operands: []Expression{
+				Binary{Left: Ref{Column: "position"}, Operator: ">=", Right: Literal{Value: 0}},
+				JSONContains{Operand: Ref{Column: "flags"}, Value: Literal{Value: `{"pinned": true}`, Type: "jsonb"}},
+			}}},
+			want: []string{`ALTER TABLE "user_roles" ADD CONSTRAINT "user_roles_position_flags_check" CHECK ((("position" >= 0) OR ("flags" @> '{"pinned": true}'::jsonb)));`},
+		},
+		{
+			name: "Exclude",
+			constraint: Exclude{
+				Elements: []ExcludeElement{{Column: "role_id", Operator: "="}, {Column: "during", Operator: "&&"}},
+				Where:    "NOT cancelled",
+			},
+			want: []string{
+				`CREATE EXTENSION IF NOT EXISTS "btree_gist";`,
+				`ALTER TABLE "user_roles" ADD CONSTRAINT "user_roles_role_id_during_excl" EXCLUDE USING gist ("role_id" WITH =, "during" WITH &&) WHERE (NOT cancelled);`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from := mustCompile(t, &testModel{name: "user_roles", fields: fields})
+			to := mustCompile(t, &constrainedModel{testModel: testModel{name: "user_roles", fields: fields}, constraints: []TableConstraint{tt.constraint}})
+
+			got, err := Diff(from, to)
+			if err != nil {
+				t.Fatalf("Diff() error = %v", err)
+			}
+			assertStatements(t, got, tt.want)
+		})
+	}
+}
+
+func TestTableConstraint_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		constraint TableConstraint
+		wantErr    bool
+	}{
+		{"Valid primary key", PrimaryKey{Columns: []string{"a", "b"}}, false},
+		{"Primary key without columns", PrimaryKey{}, true},
+		{"Unique with duplicate column", Unique{Columns: []string{"a", "a"}}, true},
+		{"Unique with empty column", Unique{Columns: []string{""}}, true},
+		{"Check without expression", Check{Expression: " "}, true},
+		{"Check with expression and condition", Check{Expression: "true", Condition: Ref{Column: "active"}}, true},
+		{"Check with invalid condition", Check{Condition: Binary{Left: Ref{Column: "a"}, Operator: "=="}}, true},
+		{"Valid check condition", Check{Condition: Ref{Column: "active"}}, false},
+		{"Exclude without elements", Exclude{}, true},
+		{"Exclude with invalid operator", Exclude{Elements: []ExcludeElement{{Column: "a", Operator: "= 1; DROP"}}}, true},
+		{"Exclude with invalid access method", Exclude{Using: "gist;", Elements: []ExcludeElement{{Column: "a", Operator: "="}}}, true},
+		{"Valid exclude", Exclude{Using: "gist", Elements: []ExcludeElement{{Column: "a", Operator: "&&"}}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.constraint.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("TableConstraint.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCompile_Constraints(t *testing.T) {
+	model := &constrainedModel{
+		testModel: testModel{
+			name: "user_roles",
+			fields: []Field{
+				&UUIDField{ColumnName: "user_id"},
+				&UUIDField{ColumnName: "role_id"},
+				&IntegerField{ColumnName: "position"},
+			},
+		},
+		constraints: []TableConstraint{
+			PrimaryKey{Columns: []string{"user_id", "role_id"}},
+			Check{Name: "position_positive", Expression: `"position" >= 0`, Columns: []string{"position"}},
+		},
+	}
+	schema := mustCompile(t, model)
+
+	got, err := schema.Statements()
+	if err != nil {
+		t.Fatalf("Schema.Statements() error = %v", err)
+	}
+	assertStatements(t, got, []string{
+		`CREATE TABLE IF NOT EXISTS "user_roles" ("user_id" uuid NOT NULL, "role_id" uuid NOT NULL, "position" INTEGER NOT NULL, ` +
+			`CONSTRAINT "user_roles_pkey" PRIMARY KEY ("user_id", "role_id"), ` +
+			`CONSTRAINT "position_positive" CHECK ("position" >= 0));`,
+	})
+	if got := schema.Table("user_roles").PrimaryKey(); len(got) != 2 || got[0] != "user_id" || got[1] != "role_id" {
+		t.Errorf("Table.PrimaryKey() = %v, want [user_id role_id]", got)
+	}
+}
+
+func TestCompile_ConstraintErrors(t *testing.T) {
+	fields := []Field{
+		&UUIDField{ColumnName: "user_id"},
+		&UUIDField{ColumnName: "role_id"},
+		&IntegerField{ColumnName: "position"},
+	}
+	tests := []struct {
+		name  string
+		model Model
+	}{
+		{"Unknown column", &constrainedModel{testModel: testModel{name: "user_roles", fields: fields}, constraints: []TableConstraint{Unique{Columns: []string{"user_id", "missing"}}}}},
+		{"Invalid constraint", &constrainedModel{testModel: testModel{name: "user_roles", fields: fields}, constraints: []TableConstraint{Check{}}}},
+		{"Nil constraint", &constrainedModel{testModel: testModel{name: "user_roles", fields: fields}, constraints: []TableConstraint{nil}}},
+		{"Unknown condition column", &constrainedModel{
+			testModel: testModel{name: "user_roles", fields: fields},
+			constraints: []TableConstraint{
+				Check{Condition: Binary{Left: Ref{Column: "missing"}, Operator: ">", Right: Literal{Value: 0}}},
+			},
+		}},
+		{"Duplicate name", &constrainedModel{
+			testModel: testModel{name: "user_roles", fields: fields},
+			constraints: []TableConstraint{
+				Unique{Name: "dup", Columns: []string{"user_id"}},
+				Unique{Name: "dup", Columns: []string{"role_id"}},
+			},
+		}},
+		{"Constraint named like a foreign key field", &constrainedModel{
+			testModel: testModel{
+				name:   "orders",
+				fields: []Field{&ForeignKeyField{ColumnName: "user_id", ReferenceTable: "users", ReferenceField: "id"}},
+			},
+			constraints: []TableConstraint{Check{Name: "orders_user_id_fkey", Expression: "true"}},
+		}},
+		{"Two primary keys", &constrainedModel{
+			testModel: testModel{name: "user_roles", fields: fields},
+			constraints: []TableConstraint{
+				PrimaryKey{Columns: []string{"user_id"}},
+				PrimaryKey{Name: "other_pkey", Columns: []string{"role_id"}},
+			},
+		}},
+		{"Primary key constraint and column", &constrainedModel{
+			testModel: testModel{
+				name:   "users",
+				fields: []Field{&UUIDField{ColumnName: "id", PrimaryKey: true}},
+			},
+			constraints: []TableConstraint{PrimaryKey{Columns: []string{"id"}}},
+		}},
+		{"Primary key on two columns", &testModel{
+			name: "user_roles",
+			fields: []Field{
+				&BigIntegerField{ColumnName: "user_id", PrimaryKey: true},
+				&BigIntegerField{ColumnName: "role_id", PrimaryKey: true},
+			},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Compile(tt.model); err == nil {
+				t.Error("Compile() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestDiff_Constraints(t *testing.T) {
+	fields := []Field{
+		&UUIDField{ColumnName: "user_id"},
+		&UUIDField{ColumnName: "role_id"},
+		&IntegerField{ColumnName: "position"},
+	}
+	from := mustCompile(t, &constrainedModel{
+		testModel: testModel{name: "user_roles", fields: fields},
+		constraints: []TableConstraint{
+			PrimaryKey{Columns: []string{"user_id", "role_id"}},
+			Unique{Columns: []string{"role_id", "position"}},
+			Check{Name: "position_positive", Expression: `"position" >= 0`},
+		},
+	})
+	to := mustCompile(t, &constrainedModel{
+		testModel: testModel{name: "user_roles", fields: fields},
+		constraints: []TableConstraint{
+			PrimaryKey{Columns: []string{"user_id", "role_id"}},
+			Check{Name: "position_positive", Expression: `"position" > 0`},
+			Unique{Columns: []string{"user_id", "position"}, NullsNotDistinct: true},
+		},
+	})
+
+	got, err := Diff(from, to)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	assertStatements(t, got, []string{
+		`ALTER TABLE "user_roles" DROP CONSTRAINT IF EXISTS "user_roles_role_id_position_key";`,
+		`ALTER TABLE "user_roles" DROP CONSTRAINT IF EXISTS "position_positive";`,
+		`ALTER TABLE "user_roles" ADD CONSTRAINT "position_positive" CHECK ("position" > 0);`,
+		`ALTER TABLE "user_roles" ADD CONSTRAINT "user_roles_user_id_position_key" UNIQUE NULLS NOT DISTINCT ("user_id", "position");`,
+	})
+}
+
+func TestInsertQuery_ConstraintConflictTarget(t *testing.T) {
+	model := &constrainedModel{
+		testModel: testModel{
+			name: "user_roles",
+			fields: []Field{
+				&UUIDField{ColumnName: "user_id"},
+				&UUIDField{ColumnName: "role_id"},
+				&IntegerField{ColumnName: "position"},
+			},
+		},
+		constraints: []TableConstraint{
+			PrimaryKey{Columns: []string{"user_id", "role_id"}},
+			Unique{Columns: []string{"role_id", "position"}},
+		},
+	}
+
+	for _, target := range [][]string{{"role_id", "user_id"}, {"position", "role_id"}} {
+		query := Insert(model).Columns("user_id", "role_id", "position").Values("u", "r", 1).OnConflict(target...).DoNothing()
+		if _, _, err := query.Build(); err != nil {
+			t.Errorf("InsertQuery.Build() with conflict target %v error = %v", target, err)
+		}
+	}
+
+	query := Insert(model).Columns("user_id", "role_id", "position").Values("u", "r", 1).OnConflict("user_id").DoNothing()
+	if _, _, err := query.Build(); err == nil {
+		t.Error("InsertQuery.Build() with non-unique conflict target error = nil, want error")
+	}
+}
+
+func TestCompile_ForeignKeyConstraint(t *testing.T) {
+	userRoles := &constrainedModel{
+		testModel: testModel{
+			name: "user_roles",
+			fields: []Field{
+				&UUIDField{ColumnName: "user_id"},
+				&UUIDField{ColumnName: "role_id"},
+			},
+		},
+		constraints: []TableConstraint{PrimaryKey{Columns: []string{"user_id", "role_id"}}},
+	}
+	fields := []Field{
+		&UUIDField{ColumnName: "id", PrimaryKey: true},
+		&UUIDField{ColumnName: "user_id"},
+		&UUIDField{ColumnName: "role_id"},
+	}
+
+	// The assignments are listed first, so the foreign key must order the tables.
+	schema := mustCompile(t, &constrainedModel{
+		testModel: testModel{name: "assignments", fields: fields},
+		constraints: []TableConstraint{
+			ForeignKey{
+				Name:             "assignments_user_role_fkey",
+				Columns:          []string{"user_id", "role_id"},
+				ReferenceTable:   "user_roles",
+				ReferenceColumns: []string{"user_id", "role_id"},
+				Annotations:      Annotation{Deferrable: true, InitiallyDeferred: true},
+			},
+		},
+	}, userRoles)
+	if table := schema.Table("assignments"); len(table.Constraints) != 0 || len(table.ForeignKeys) != 1 {
+		t.Fatalf("Constraints = %v, ForeignKeys = %v, want the foreign key only in ForeignKeys", table.Constraints, table.ForeignKeys)
+	}
+
+	created, err := schema.Statements()
+	if err != nil {
+		t.Fatalf("Schema.Statements() error = %v", err)
+	}
+	assertStatements(t, created, []string{
+		`CREATE TABLE IF NOT EXISTS "user_roles" ("user_id" uuid NOT NULL, "role_id" uuid NOT NULL, CONSTRAINT "user_roles_pkey" PRIMARY KEY ("user_id", "role_id"));`,
+		`CREATE TABLE IF NOT EXISTS "assignments" ("id" uuid NOT NULL PRIMARY KEY, "user_id" uuid NOT NULL, "role_id" uuid NOT NULL, ` +
+			`CONSTRAINT "assignments_user_role_fkey" FOREIGN KEY ("user_id", "role_id") REFERENCES "user_roles"("user_id", "role_id") DEFERRABLE INITIALLY DEFERRED);`,
+	})
+
+	to := mustCompile(t, userRoles, &constrainedModel{
+		testModel: testModel{name: "assignments", fields: fields},
+		constraints: []TableConstraint{
+			&ForeignKey{
+				Name:             "assignments_user_role_fkey",
+				Columns:          []string{"user_id", "role_id"},
+				ReferenceTable:   "user_roles",
+				ReferenceColumns: []string{"user_id", "role_id"},
+			},
+		},
+	})
+	got, err := Diff(schema, to)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	assertStatements(t, got, []string{
+		`ALTER TABLE "assignments" DROP CONSTRAINT IF EXISTS "assignments_user_role_fkey";`,
+		`ALTER TABLE "assignments" ADD CONSTRAINT "assignments_user_role_fkey" FOREIGN KEY ("user_id", "role_id") REFERENCES "user_roles"("user_id", "role_id");`,
+	})
+}
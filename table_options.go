@@ -0,0 +1,98 @@
+package trenovaorm
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CommentedModel is implemented by models that document their table. It is
+// optional, like the other table option interfaces below.
+type CommentedModel interface {
+	TableComment() string
+}
+
+// StorageModel is implemented by models that set storage parameters on their
+// table, such as fillfactor or autovacuum_vacuum_scale_factor.
+type StorageModel interface {
+	StorageParameters() map[string]string
+}
+
+// TablespaceModel is implemented by models whose table is stored outside the
+// default tablespace.
+type TablespaceModel interface {
+	Tablespace() string
+}
+
+// UnloggedModel is implemented by models whose table may be unlogged. Unlogged
+// tables are faster to write but are not crash-safe, and are emptied after a
+// crash, which suits caches and staging data.
+type UnloggedModel interface {
+	Unlogged() bool
+}
+
+// TableOptions are the table-wide settings a model declares through the
+// optional table option interfaces.
+type TableOptions struct {
+	Comment    string
+	Storage    map[string]string // Storage parameters, e.g. {"fillfactor": "70"}
+	Tablespace string
+	Unlogged   bool
+}
+
+// ModelTableOptions returns the table options of a model, leaving out those of
+// the option interfaces it does not implement.
+func ModelTableOptions(model Model) TableOptions {
+	var opts TableOptions
+	if m, ok := model.(CommentedModel); ok {
+		opts.Comment = m.TableComment()
+	}
+	if m, ok := model.(StorageModel); ok {
+		opts.Storage = m.StorageParameters()
+	}
+	if m, ok := model.(TablespaceModel); ok {
+		opts.Tablespace = m.Tablespace()
+	}
+	if m, ok := model.(UnloggedModel); ok {
+		opts.Unlogged = m.Unlogged()
+	}
+	return opts
+}
+
+// Validate checks the storage parameters and the tablespace name.
+func (o TableOptions) Validate() error {
+	for name, value := range o.Storage {
+		if !qualifiedName.MatchString(name) {
+			return fmt.Errorf("invalid storage parameter %q", name)
+		}
+		if !storageValue.MatchString(value) {
+			return fmt.Errorf("invalid value %q for storage parameter %s", value, name)
+		}
+	}
+	if o.Tablespace != "" && strings.TrimSpace(o.Tablespace) == "" {
+		return errors.New("tablespace name cannot be blank")
+	}
+	return nil
+}
+
+// storageNames returns the names of the storage parameters in sorted order.
+func storageNames(params map[string]string) []string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// storageList renders storage parameters as name = value pairs sorted by name,
+// as they appear in WITH (...) and SET (...).
+func storageList(params map[string]string) string {
+	names := storageNames(params)
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = fmt.Sprintf("%s = %s", name, params[name])
+	}
+	return strings.Join(pairs, ", ")
+}
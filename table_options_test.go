@@ -0,0 +1,118 @@
+package trenovaorm
+
+import "testing"
+
+type optionsModel struct {
+	testModel
+	comment    string
+	storage    map[string]string
+	tablespace string
+	unlogged   bool
+}
+
+func (m *optionsModel) TableComment() string {
+	return m.comment
+}
+
+func (m *optionsModel) StorageParameters() map[string]string {
+	return m.storage
+}
+
+func (m *optionsModel) Tablespace() string {
+	return m.tablespace
+}
+
+func (m *optionsModel) Unlogged() bool {
+	return m.unlogged
+}
+
+func TestCompile_TableOptions(t *testing.T) {
+	schema := mustCompile(t, &optionsModel{
+		testModel: testModel{
+			name:   "sessions",
+			fields: []Field{&UUIDField{ColumnName: "id", PrimaryKey: true}},
+		},
+		comment:    "Active user's sessions",
+		storage:    map[string]string{"fillfactor": "70", "autovacuum_vacuum_scale_factor": "0.05", "toast.autovacuum_enabled": "off"},
+		tablespace: "fast_ssd",
+		unlogged:   true,
+	})
+
+	got, err := schema.Statements()
+	if err != nil {
+		t.Fatalf("Schema.Statements() error = %v", err)
+	}
+	assertStatements(t, got, []string{
+		`CREATE UNLOGGED TABLE IF NOT EXISTS "sessions" ("id" uuid NOT NULL PRIMARY KEY) ` +
+			`WITH (autovacuum_vacuum_scale_factor = 0.05, fillfactor = 70, toast.autovacuum_enabled = off) TABLESPACE "fast_ssd";`,
+		`COMMENT ON TABLE "sessions" IS 'Active user''s sessions';`,
+	})
+}
+
+func TestCompile_TableOptionErrors(t *testing.T) {
+	fields := []Field{&UUIDField{ColumnName: "id", PrimaryKey: true}}
+	tests := []struct {
+		name  string
+		model *optionsModel
+	}{
+		{"Invalid parameter name", &optionsModel{testModel: testModel{name: "sessions", fields: fields}, storage: map[string]string{"fill factor": "70"}}},
+		{"Invalid parameter value", &optionsModel{testModel: testModel{name: "sessions", fields: fields}, storage: map[string]string{"fillfactor": "70); DROP TABLE x"}}},
+		{"Blank tablespace", &optionsModel{testModel: testModel{name: "sessions", fields: fields}, tablespace: " "}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Compile(tt.model); err == nil {
+				t.Error("Compile() error = nil, want error")
+			}
+		})
+	}
+
+	sessions := &optionsModel{testModel: testModel{name: "sessions", fields: fields}, unlogged: true}
+	audits := &testModel{name: "audits", fields: []Field{
+		&UUIDField{ColumnName: "id", PrimaryKey: true},
+		&ForeignKeyField{ColumnName: "session_id", ReferenceTable: "sessions", ReferenceField: "id"},
+	}}
+	if _, err := Compile(sessions, audits); err == nil {
+		t.Error("Compile() with a logged table referencing an unlogged one error = nil, want error")
+	}
+}
+
+func TestDiff_TableOptions(t *testing.T) {
+	fields := []Field{&UUIDField{ColumnName: "id", PrimaryKey: true}}
+	from := mustCompile(t, &optionsModel{
+		testModel: testModel{name: "sessions", fields: fields},
+		comment:   "Sessions",
+		storage:   map[string]string{"fillfactor": "70", "autovacuum_enabled": "off"},
+		unlogged:  true,
+	})
+	to := mustCompile(t, &optionsModel{
+		testModel:  testModel{name: "sessions", fields: fields},
+		storage:    map[string]string{"fillfactor": "90", "parallel_workers": "4"},
+		tablespace: "archive",
+	})
+
+	got, err := Diff(from, to)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	assertStatements(t, got, []string{
+		`ALTER TABLE "sessions" SET LOGGED;`,
+		`ALTER TABLE "sessions" SET (fillfactor = 90, parallel_workers = 4);`,
+		`ALTER TABLE "sessions" RESET (autovacuum_enabled);`,
+		`ALTER TABLE "sessions" SET TABLESPACE "archive";`,
+		`COMMENT ON TABLE "sessions" IS NULL;`,
+	})
+
+	back, err := Diff(to, from)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	assertStatements(t, back, []string{
+		`ALTER TABLE "sessions" SET UNLOGGED;`,
+		`ALTER TABLE "sessions" SET (autovacuum_enabled = off, fillfactor = 70);`,
+		`ALTER TABLE "sessions" RESET (parallel_workers);`,
+		`ALTER TABLE "sessions" SET TABLESPACE pg_default;`,
+		`COMMENT ON TABLE "sessions" IS 'Sessions';`,
+	})
+}
@@ -0,0 +1,104 @@
+package trenovaorm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TimestampField represents a timestamp field in the database.
+type TimestampField struct {
+	ColumnName   string
+	WithTimeZone bool // Use TIMESTAMPTZ instead of TIMESTAMP
+	Precision    int  // Fractional digits of the seconds, 1-6; 0 keeps the PostgreSQL default of 6
+	Nullable     bool
+	Unique       bool
+	Default      PSQLFunction
+	Index        bool
+	Comment      string
+	CustomType   string
+	Constraints  []string
+	StructTag    string
+}
+
+// Definition generates the SQL definition for the TimestampField.
+func (f *TimestampField) Definition() string {
+	def := fmt.Sprintf("%s %s", QuoteIdentifier(f.ColumnName), f.sqlType())
+
+	if !f.Nullable {
+		def += fmt.Sprintf(" %s", ConstraintNotNull.String())
+	}
+
+	if f.Unique {
+		def += fmt.Sprintf(" %s", ConstraintUnqiue.String())
+	}
+
+	if f.Default != "" {
+		def += fmt.Sprintf(" DEFAULT %s", f.Default.String())
+	}
+
+	if len(f.Constraints) > 0 {
+		def += " " + strings.Join(f.Constraints, " ")
+	}
+	return def
+}
+
+// Name returns the column name for the TimestampField.
+func (f *TimestampField) Name() string {
+	return f.ColumnName
+}
+
+// CommentSQL generates the SQL statement for adding a comment to the TimestampField.
+func (f *TimestampField) CommentSQL(tableName string) string {
+	if f.Comment == "" {
+		return ""
+	}
+	return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;", QuoteQualifiedName(tableName), QuoteIdentifier(f.ColumnName), QuoteLiteral(f.Comment))
+}
+
+// Validate checks if the field's configuration is valid.
+func (f *TimestampField) Validate() error {
+	if f.ColumnName == "" {
+		return fmt.Errorf("column name cannot be empty")
+	}
+	if f.Precision < 0 || f.Precision > 6 {
+		return fmt.Errorf("TimestampField %s precision must be between 0 and 6", f.ColumnName)
+	}
+	return nil
+}
+
+// GoType returns the Go type for the TimestampField.
+func (f *TimestampField) GoType() string {
+	if f.Nullable {
+		return "*time.Time"
+	}
+	return "time.Time"
+}
+
+// IndexSQL generates the SQL statement for creating an index if Index is true.
+func (f *TimestampField) IndexSQL(tableName string) string {
+	if !f.Index {
+		return ""
+	}
+	indexName := f.IndexName(tableName)
+	return fmt.Sprintf("CREATE INDEX %s ON %s (%s);", QuoteIdentifier(indexName), QuoteQualifiedName(tableName), QuoteIdentifier(f.ColumnName))
+}
+
+// IndexName returns the name of the index generated by IndexSQL.
+func (f *TimestampField) IndexName(tableName string) string {
+	return fmt.Sprintf("idx_%s_%s", unqualifiedName(tableName), f.ColumnName)
+}
+
+// sqlType returns the SQL type of the column.
+func (f *TimestampField) sqlType() string {
+	if f.CustomType != "" {
+		return f.CustomType
+	}
+	typ := "TIMESTAMP"
+	if f.WithTimeZone {
+		typ = "TIMESTAMPTZ"
+	}
+	if f.Precision > 0 {
+		typ += fmt.Sprintf("(%d)", f.Precision)
+	}
+	return typ
+}
@@ -0,0 +1,71 @@
+package trenovaorm
+
+import "testing"
+
+func TestTimestampField_Definition(t *testing.T) {
+	tests := []struct {
+		name     string
+		field    TimestampField
+		expected string
+	}{
+		{
+			name:     "Basic TimestampField",
+			field:    TimestampField{ColumnName: "created_at", Default: CurrentTimestamp},
+			expected: `"created_at" TIMESTAMP NOT NULL DEFAULT current_timestamp`,
+		},
+		{
+			name:     "TimestampField with time zone",
+			field:    TimestampField{ColumnName: "created_at", WithTimeZone: true, Nullable: true},
+			expected: `"created_at" TIMESTAMPTZ`,
+		},
+		{
+			name:     "TimestampField with precision",
+			field:    TimestampField{ColumnName: "created_at", WithTimeZone: true, Precision: 3, Unique: true},
+			expected: `"created_at" TIMESTAMPTZ(3) NOT NULL UNIQUE`,
+		},
+		{
+			name:     "TimestampField with Custom Type",
+			field:    TimestampField{ColumnName: "created_at", CustomType: "TIMESTAMP(0) WITH TIME ZONE", WithTimeZone: true},
+			expected: `"created_at" TIMESTAMP(0) WITH TIME ZONE NOT NULL`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.field.Definition(); got != tt.expected {
+				t.Errorf("TimestampField.Definition() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTimestampField_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   TimestampField
+		wantErr bool
+	}{
+		{name: "Valid", field: TimestampField{ColumnName: "created_at", Precision: 6}, wantErr: false},
+		{name: "Missing column name", field: TimestampField{}, wantErr: true},
+		{name: "Precision too large", field: TimestampField{ColumnName: "created_at", Precision: 7}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.field.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("TimestampField.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTimestampedMixin_Fields(t *testing.T) {
+	for _, field := range (TimestampedMixin{}).Fields() {
+		if got, want := ColumnOf(field).Type, "TIMESTAMPTZ"; got != want {
+			t.Errorf("%s type = %v, want %v", field.Name(), got, want)
+		}
+		if got, want := field.GoType(), "time.Time"; got != want {
+			t.Errorf("%s GoType() = %v, want %v", field.Name(), got, want)
+		}
+	}
+}
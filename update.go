@@ -0,0 +1,105 @@
+package trenovaorm
+
+import (
+	"errors"
+	"fmt"
+)
+
+// UpdateQuery builds a parameterized UPDATE statement for the table of a model.
+type UpdateQuery struct {
+	model     Model
+	scope     queryScope
+	set       []assignment
+	where     []Predicate
+	returning []string
+}
+
+// assignment is a column of the SET clause and its new value.
+type assignment struct {
+	column string
+	value  any
+}
+
+// Update starts an UPDATE statement for the table of model.
+func Update(model Model) *UpdateQuery {
+	return &UpdateQuery{
+		model: model,
+		scope: queryScope{tables: []queryTable{newQueryTable(model)}},
+	}
+}
+
+// Set assigns value to column.
+func (q *UpdateQuery) Set(column string, value any) *UpdateQuery {
+	q.set = append(q.set, assignment{column: column, value: value})
+	return q
+}
+
+// Where adds predicates to the WHERE clause. All predicates must hold.
+func (q *UpdateQuery) Where(preds ...Predicate) *UpdateQuery {
+	q.where = append(q.where, preds...)
+	return q
+}
+
+// Returning sets the columns returned for each updated row.
+func (q *UpdateQuery) Returning(columns ...string) *UpdateQuery {
+	q.returning = columns
+	return q
+}
+
+// Build returns the UPDATE statement with $n placeholders and its arguments, or
+// an error if a column is unknown, generated, an identity generated always or
+// a non-nullable column set to nil.
+func (q *UpdateQuery) Build() (string, []any, error) {
+	if IsView(q.model) {
+		return "", nil, fmt.Errorf("view %q is read-only", q.model.TableName())
+	}
+	if len(q.set) == 0 {
+		return "", nil, errors.New("update requires at least one column to set")
+	}
+
+	columns := make(map[string]Column)
+	for _, field := range ModelFields(q.model) {
+		col := ColumnOf(field)
+		columns[col.Name] = col
+	}
+
+	b := &queryBuilder{scope: &q.scope}
+	b.write(fmt.Sprintf("UPDATE %s SET ", QuoteQualifiedName(QualifiedTableName(q.model))))
+	for i, a := range q.set {
+		col, ok := columns[a.column]
+		if !ok {
+			return "", nil, fmt.Errorf("column %q does not exist on table %q", a.column, q.model.TableName())
+		}
+		if err := checkAssignable(col); err != nil {
+			return "", nil, err
+		}
+		if a.value == nil && !col.Nullable {
+			return "", nil, fmt.Errorf("column %q is not nullable", a.column)
+		}
+		if i > 0 {
+			b.write(", ")
+		}
+		b.write(QuoteIdentifier(a.column) + " = ")
+		b.arg(a.value)
+	}
+
+	if len(q.where) > 0 {
+		b.write(" WHERE ")
+		b.predicates("AND", q.where)
+	}
+	b.returning(q.returning)
+	return b.build()
+}
+
+// checkAssignable returns an error if the database computes the values of col,
+// being a generated column or an identity column generated always, so that a
+// statement cannot assign it.
+func checkAssignable(col Column) error {
+	if col.Identity.Generation == IdentityAlways {
+		return fmt.Errorf("column %q is generated always as identity", col.Name)
+	}
+	if col.Generated != "" {
+		return fmt.Errorf("column %q is a generated column", col.Name)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package trenovaorm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUpdateQuery_Build(t *testing.T) {
+	sql, args, err := Update(queryUsers).
+		Set("email", "b@example.com").
+		Set("age", nil).
+		Where(Eq("id", "1")).
+		Returning("id", "email").
+		Build()
+	if err != nil {
+		t.Fatalf("UpdateQuery.Build() error = %v", err)
+	}
+
+	wantSQL := `UPDATE "users" SET "email" = $1, "age" = $2 WHERE "id" = $3 RETURNING "id", "email"`
+	if sql != wantSQL {
+		t.Errorf("UpdateQuery.Build() sql = %v, want %v", sql, wantSQL)
+	}
+	wantArgs := []any{"b@example.com", nil, "1"}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("UpdateQuery.Build() args = %v, want %v", args, wantArgs)
+	}
+}
+
+func TestUpdateQuery_BuildErrors(t *testing.T) {
+	events := &testModel{name: "events", fields: []Field{
+		&BigIntegerField{ColumnName: "id", PrimaryKey: true, Identity: Identity{Generation: IdentityAlways}},
+		&TextField{ColumnName: "name"},
+		&GeneratedField{Field: &TextField{ColumnName: "slug"}, Expression: Func{Name: "lower", Args: []Expression{Ref{Column: "name"}}}},
+	}}
+
+	tests := []struct {
+		name  string
+		query *UpdateQuery
+	}{
+		{name: "Nothing to set", query: Update(queryUsers).Where(Eq("id", "1"))},
+		{name: "Unknown column", query: Update(queryUsers).Set("name", "x")},
+		{name: "Nil non-nullable column", query: Update(queryUsers).Set("email", nil)},
+		{name: "Unknown predicate column", query: Update(queryUsers).Set("email", "x").Where(Eq("name", "x"))},
+		{name: "Identity generated always", query: Update(events).Set("id", int64(1))},
+		{name: "Generated column", query: Update(events).Set("slug", "x")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := tt.query.Build(); err == nil {
+				t.Error("UpdateQuery.Build() error = nil, want error")
+			}
+		})
+	}
+}
@@ -1,24 +1,16 @@
 package trenovaorm
 
-import (
-	"fmt"
-	"strings"
-)
+import "strings"
 
 // Helper function to join and quote columns
 func joinColumns(columns []string) string {
 	quoted := make([]string, len(columns))
 	for i, col := range columns {
-		quoted[i] = quoteIdentifier(col)
+		quoted[i] = QuoteIdentifier(col)
 	}
 	return strings.Join(quoted, ", ")
 }
 
-// Helper function to quote PostgreSQL identifiers
-func quoteIdentifier(identifier string) string {
-	return fmt.Sprintf(`"%s"`, identifier)
-}
-
 // toSnakeCase converts a string to snake_case.
 func toSnakeCase(str string) string {
 	var result []rune
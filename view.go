@@ -0,0 +1,426 @@
+package trenovaorm
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// View defines a view over the tables and views of other models. Its columns
+// are those the query selects:
+//
+//	func (ActiveDrivers) View() View {
+//		return View{
+//			Query: Select(&Driver{}, "id", "name", "organizations.name").
+//				Join(&Organization{}).
+//				Where(Eq("status", "active")),
+//			Columns: []string{"id", "name", "organization_name"},
+//		}
+//	}
+//
+// A view has no parameters, so the values of the query are written into its
+// definition as literals.
+type View struct {
+	Query *SelectQuery
+	// Columns names the columns of the view in the order they are selected,
+	// defaulting to the names of the selected columns, e.g. email for
+	// users.email and count for count(*).
+	Columns []string
+}
+
+// MaterializedView defines a view whose rows are stored and only change when
+// it is refreshed, see RefreshMaterializedView. It can be indexed like a
+// table, through the Indexes of its model.
+type MaterializedView struct {
+	View
+	WithNoData bool // Creates the view empty, to be filled by the first refresh
+}
+
+// ViewModel is implemented by models that describe a view instead of a table.
+// Their columns come from the query, so they must not declare fields or
+// mixins, and their Indexes must be empty. The other optional model
+// interfaces do not apply, except SchemaModel and CommentedModel.
+type ViewModel interface {
+	View() View
+}
+
+// MaterializedViewModel is implemented by models that describe a materialized
+// view. Like ViewModel, it replaces the table of the model.
+type MaterializedViewModel interface {
+	MaterializedView() MaterializedView
+}
+
+// IsView reports whether a model describes a view or a materialized view.
+func IsView(model Model) bool {
+	_, _, ok := modelView(model)
+	return ok
+}
+
+// modelView returns the view a model describes and whether it is
+// materialized, or false if the model describes a table.
+func modelView(model Model) (view MaterializedView, materialized, ok bool) {
+	if m, ok := model.(MaterializedViewModel); ok {
+		return m.MaterializedView(), true, true
+	}
+	if m, ok := model.(ViewModel); ok {
+		return MaterializedView{View: m.View()}, false, true
+	}
+	return MaterializedView{}, false, false
+}
+
+// fields returns the columns of the view.
+func (v View) fields() ([]Field, error) {
+	if v.Query == nil {
+		return nil, errors.New("view has no query")
+	}
+	fields, err := v.Query.resultFields()
+	if err != nil {
+		return nil, err
+	}
+
+	if v.Columns != nil {
+		if len(v.Columns) != len(fields) {
+			return nil, fmt.Errorf("view names %d columns but its query selects %d", len(v.Columns), len(fields))
+		}
+		for i, name := range v.Columns {
+			fields[i].(*ViewField).ColumnName = name
+		}
+	}
+
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.Name()
+	}
+	if err := validateColumns(names); err != nil {
+		return nil, fmt.Errorf("%w; name the columns of the view with Columns", err)
+	}
+	return fields, nil
+}
+
+// resultFields returns a ViewField for every column the query selects.
+func (q *SelectQuery) resultFields() ([]Field, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	columns := q.columns
+	if len(columns) == 0 {
+		columns = q.scope.tables[0].columns
+	}
+
+	fields := make([]Field, 0, len(columns))
+	for _, ref := range columns {
+		field, err := q.resultField(strings.TrimSpace(ref))
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// resultField returns the ViewField of a column reference. Aggregates are
+// named after their function, as PostgreSQL does. Columns of left-joined
+// tables and the results of aggregates other than count may be NULL. Columns
+// keep the comment of the column they read.
+func (q *SelectQuery) resultField(ref string) (*ViewField, error) {
+	if open := strings.IndexByte(ref, '('); open > 0 && strings.HasSuffix(ref, ")") {
+		fn := strings.ToLower(ref[:open])
+		arg := strings.TrimSpace(ref[open+1 : len(ref)-1])
+		switch fn {
+		case "count":
+			return &ViewField{ColumnName: fn, Source: &BigIntegerField{ColumnName: fn}}, nil
+		case "sum", "avg":
+			field, err := q.resultField(arg)
+			if err != nil {
+				return nil, err
+			}
+			source, err := aggregateSource(fn, field)
+			if err != nil {
+				return nil, err
+			}
+			return &ViewField{ColumnName: fn, Source: source, Nullable: true}, nil
+		case "min", "max":
+			field, err := q.resultField(arg)
+			if err != nil {
+				return nil, err
+			}
+			return &ViewField{ColumnName: fn, Source: field.Source, Nullable: true}, nil
+		}
+		return nil, fmt.Errorf("unknown aggregate %q", ref[:open])
+	}
+
+	index, column := 0, ref
+	if dot := strings.IndexByte(ref, '.'); dot >= 0 {
+		index = slices.IndexFunc(q.scope.tables, func(t queryTable) bool { return t.name == ref[:dot] })
+		if index < 0 {
+			return nil, fmt.Errorf("table %q is not part of the query", ref[:dot])
+		}
+		column = ref[dot+1:]
+	}
+	for _, field := range ModelFields(q.models[index]) {
+		if field.Name() != column {
+			continue
+		}
+		if source, ok := field.(*ViewField); ok {
+			return &ViewField{ColumnName: column, Source: source.Source, Nullable: source.Nullable || q.leftJoined(index), Comment: source.Comment}, nil
+		}
+		return &ViewField{ColumnName: column, Source: field, Nullable: q.leftJoined(index), Comment: ColumnOf(field).Comment}, nil
+	}
+	return nil, fmt.Errorf("column %q does not exist on table %q", column, q.scope.tables[index].name)
+}
+
+// aggregateSource returns the field holding the result of sum or avg over a
+// column, typed as PostgreSQL types it: sum widens integers to bigint, and
+// bigint to numeric, while avg yields numeric for integers and double
+// precision for floating-point numbers.
+func aggregateSource(fn string, column *ViewField) (Field, error) {
+	typ := strings.ToUpper(ColumnOf(column.Source).Type)
+	switch {
+	case typ == "SMALLINT" || typ == "INTEGER" || typ == "INT" || typ == "INT2" || typ == "INT4":
+		if fn == "sum" {
+			return &BigIntegerField{ColumnName: fn}, nil
+		}
+		return &NumericField{ColumnName: fn, CustomType: "NUMERIC"}, nil
+	case typ == "BIGINT" || typ == "INT8" || strings.HasPrefix(typ, "NUMERIC") || strings.HasPrefix(typ, "DECIMAL"):
+		return &NumericField{ColumnName: fn, CustomType: "NUMERIC"}, nil
+	case typ == "REAL" || typ == "FLOAT4":
+		if fn == "sum" {
+			return &NumericField{ColumnName: fn, CustomType: "REAL"}, nil
+		}
+		return &NumericField{ColumnName: fn, CustomType: "DOUBLE PRECISION"}, nil
+	case typ == "DOUBLE PRECISION" || typ == "FLOAT8" || typ == "FLOAT":
+		return &NumericField{ColumnName: fn, CustomType: "DOUBLE PRECISION"}, nil
+	case strings.HasPrefix(typ, "INTERVAL"):
+		return column.Source, nil
+	}
+	return nil, fmt.Errorf("%s of column %s of type %s is not supported", fn, column.ColumnName, typ)
+}
+
+// leftJoined reports whether the table at index of the query is left joined.
+func (q *SelectQuery) leftJoined(index int) bool {
+	return index > 0 && q.joins[index-1].kind == "LEFT JOIN"
+}
+
+// CompiledView is the compiled representation of a view model.
+type CompiledView struct {
+	// Name is the view name, qualified with the schema of the model if it
+	// has one.
+	Name         string
+	Model        Model
+	Fields       []Field  // The columns of the view, as ViewFields
+	Query        string   // The SELECT statement, with its values as literals
+	Reads        []string // Names of the tables and views the query reads
+	Materialized bool
+	WithNoData   bool
+	Indexes      []Index // Indexes of a materialized view
+	Comment      string
+}
+
+// kind returns the kind of relation, as it appears in DDL statements.
+func (v *CompiledView) kind() string {
+	if v.Materialized {
+		return "MATERIALIZED VIEW"
+	}
+	return "VIEW"
+}
+
+// CreateSQL generates the statement creating the view. Plain views are
+// replaced if they exist; materialized views are left as they are.
+func (v *CompiledView) CreateSQL() string {
+	columns := make([]string, len(v.Fields))
+	for i, field := range v.Fields {
+		columns[i] = field.Name()
+	}
+	if !v.Materialized {
+		return fmt.Sprintf("CREATE OR REPLACE VIEW %s (%s) AS %s;", QuoteQualifiedName(v.Name), joinColumns(columns), v.Query)
+	}
+	sql := fmt.Sprintf("CREATE MATERIALIZED VIEW IF NOT EXISTS %s (%s) AS %s", QuoteQualifiedName(v.Name), joinColumns(columns), v.Query)
+	if v.WithNoData {
+		sql += " WITH NO DATA"
+	}
+	return sql + ";"
+}
+
+// DropSQL generates the statement dropping the view.
+func (v *CompiledView) DropSQL() string {
+	return fmt.Sprintf("DROP %s IF EXISTS %s;", v.kind(), QuoteQualifiedName(v.Name))
+}
+
+// CommentSQL generates the COMMENT ON statement for the view, if it has a comment.
+func (v *CompiledView) CommentSQL() []string {
+	if v.Comment == "" {
+		return nil
+	}
+	return []string{v.commentSQL(v.Comment)}
+}
+
+// commentSQL generates the statement setting the comment of the view, or
+// removing it if comment is empty.
+func (v *CompiledView) commentSQL(comment string) string {
+	literal := "NULL"
+	if comment != "" {
+		literal = QuoteLiteral(comment)
+	}
+	return fmt.Sprintf("COMMENT ON %s %s IS %s;", v.kind(), QuoteQualifiedName(v.Name), literal)
+}
+
+// IndexSQL generates the CREATE INDEX statements of a materialized view.
+func (v *CompiledView) IndexSQL() ([]string, error) {
+	return v.table().IndexSQL()
+}
+
+// table returns the view as a table holding its columns and indexes, so that
+// its indexes are rendered and diffed like those of tables.
+func (v *CompiledView) table() *Table {
+	return &Table{Name: v.Name, Model: v.Model, Fields: v.Fields, Indexes: v.Indexes}
+}
+
+// RefreshSQL generates the REFRESH MATERIALIZED VIEW statement of the view.
+// A concurrent refresh does not block reads of the view, but requires a
+// unique index on columns of the view, without a WHERE clause, and a view
+// that has been filled.
+func (v *CompiledView) RefreshSQL(concurrently bool) (string, error) {
+	if !v.Materialized {
+		return "", fmt.Errorf("view %s is not materialized", v.Name)
+	}
+	if !concurrently {
+		return fmt.Sprintf("REFRESH MATERIALIZED VIEW %s;", QuoteQualifiedName(v.Name)), nil
+	}
+	for i := range v.Indexes {
+		idx := &v.Indexes[i]
+		if _, ok := idx.keyColumns(); ok && idx.Unique && idx.predicate() == "" {
+			return fmt.Sprintf("REFRESH MATERIALIZED VIEW CONCURRENTLY %s;", QuoteQualifiedName(v.Name)), nil
+		}
+	}
+	return "", fmt.Errorf("view %s: refreshing concurrently requires a unique index on columns, without a WHERE clause", v.Name)
+}
+
+// RefreshMaterializedView generates the statement refreshing the materialized
+// view of a model, for a scheduled job to run:
+//
+//	sql, err := RefreshMaterializedView(&DailyRevenue{}, true)
+func RefreshMaterializedView(model Model, concurrently bool) (string, error) {
+	view, err := compileView(model)
+	if err != nil {
+		return "", err
+	}
+	return view.RefreshSQL(concurrently)
+}
+
+// compileView compiles the query of a view model and validates the view.
+func compileView(model Model) (*CompiledView, error) {
+	name := model.TableName()
+	if name == "" {
+		return nil, errors.New("view name cannot be empty")
+	}
+	schema := ModelSchemaName(model)
+	if err := validateTableName(schema, name); err != nil {
+		return nil, err
+	}
+	name = QualifyName(schema, name)
+
+	def, materialized, _ := modelView(model)
+	if _, ok := model.(ViewModel); ok && materialized {
+		return nil, fmt.Errorf("view %s cannot be both a view and a materialized view", name)
+	}
+	if len(model.Fields()) > 0 || len(model.Mixins()) > 0 {
+		return nil, fmt.Errorf("view %s: views take their columns from their query and cannot declare fields or mixins", name)
+	}
+	opts := ModelTableOptions(model)
+	if len(opts.Storage) > 0 || opts.Tablespace != "" || opts.Unlogged || len(ModelConstraints(model)) > 0 || !ModelPartitioning(model).IsZero() {
+		return nil, fmt.Errorf("view %s: views cannot have storage parameters, a tablespace, constraints or partitions, or be unlogged", name)
+	}
+
+	fields, err := def.fields()
+	if err != nil {
+		return nil, fmt.Errorf("view %s: %w", name, err)
+	}
+	query, _, err := def.Query.build(&queryBuilder{scope: &def.Query.scope, inline: true})
+	if err != nil {
+		return nil, fmt.Errorf("view %s: %w", name, err)
+	}
+
+	view := &CompiledView{
+		Name:         name,
+		Model:        model,
+		Fields:       fields,
+		Query:        query,
+		Materialized: materialized,
+		WithNoData:   def.WithNoData,
+		Indexes:      model.Indexes(),
+		Comment:      opts.Comment,
+	}
+	for _, m := range def.Query.models {
+		if read := QualifiedTableName(m); !slices.Contains(view.Reads, read) {
+			view.Reads = append(view.Reads, read)
+		}
+	}
+
+	if len(view.Indexes) > 0 && !materialized {
+		return nil, fmt.Errorf("view %s: only materialized views can be indexed", name)
+	}
+	columns := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		columns[field.Name()] = true
+	}
+	for i := range view.Indexes {
+		if err := view.Indexes[i].Validate(); err != nil {
+			return nil, fmt.Errorf("view %s: index: %w", name, err)
+		}
+		for _, col := range view.Indexes[i].columnNames() {
+			if !columns[col] {
+				return nil, fmt.Errorf("view %s: index references unknown column %s", name, col)
+			}
+		}
+	}
+	return view, nil
+}
+
+// ViewField is a read-only column of a view. It takes its type from Source,
+// the column of a table or view the query selects, or the result of an
+// aggregate.
+type ViewField struct {
+	ColumnName string
+	Source     Field
+	// Nullable reports whether the view may yield NULL where Source cannot,
+	// e.g. through a LEFT JOIN.
+	Nullable bool
+	Comment  string // Documents the column in generated structs
+}
+
+// Definition describes the column as it appears in the view.
+func (f *ViewField) Definition() string {
+	return fmt.Sprintf("%s %s", QuoteIdentifier(f.ColumnName), ColumnOf(f.Source).Type)
+}
+
+// Name returns the column name of the view.
+func (f *ViewField) Name() string {
+	return f.ColumnName
+}
+
+// CommentSQL returns no statement; the columns of views are not commented.
+func (f *ViewField) CommentSQL(tableName string) string {
+	return ""
+}
+
+// Validate checks that the field has a name and a source.
+func (f *ViewField) Validate() error {
+	if f.ColumnName == "" {
+		return errors.New("column name cannot be empty")
+	}
+	if f.Source == nil {
+		return fmt.Errorf("ViewField %s has no source", f.ColumnName)
+	}
+	return nil
+}
+
+// GoType returns the Go type of the source, as a pointer if the view may
+// yield NULL.
+func (f *ViewField) GoType() string {
+	goType := f.Source.GoType()
+	if f.Nullable && !strings.HasPrefix(goType, "*") {
+		return "*" + goType
+	}
+	return goType
+}
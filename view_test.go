@@ -0,0 +1,302 @@
+package trenovaorm
+
+import (
+	"strings"
+	"testing"
+)
+
+type viewModel struct {
+	testModel
+	view       View
+	comment    string
+	tablespace string
+}
+
+func (m *viewModel) View() View {
+	return m.view
+}
+
+func (m *viewModel) TableComment() string {
+	return m.comment
+}
+
+func (m *viewModel) Tablespace() string {
+	return m.tablespace
+}
+
+type materializedViewModel struct {
+	testModel
+	view MaterializedView
+}
+
+func (m *materializedViewModel) MaterializedView() MaterializedView {
+	return m.view
+}
+
+func TestCompile_Views(t *testing.T) {
+	activeUsers := &viewModel{
+		testModel: testModel{name: "active_users"},
+		comment:   "Adult users",
+		view: View{
+			Query:   Select(queryUsers, "id", "email", "organizations.name").LeftJoin(queryOrganizations).Where(Ge("age", 18)),
+			Columns: []string{"id", "email", "organization_name"},
+		},
+	}
+	organizationCounts := &materializedViewModel{
+		testModel: testModel{name: "organization_counts", indexes: []Index{{Columns: []string{"organization_name"},
+			Unique: true}}},
+		view: MaterializedView{View: View{Query: Select(activeUsers, "organization_name", "count(*)").GroupBy("organization_name")}},
+	}
+	schema := mustCompile(t, organizationCounts, queryOrganizations, activeUsers, queryUsers)
+
+	got, err := schema.Statements()
+	if err != nil {
+		t.Fatalf("Schema.Statements() error = %v", err)
+	}
+	assertStatements(t, got[len(got)-4:], []string{
+		`CREATE OR REPLACE VIEW "active_users" ("id", "email", "organization_name") AS ` +
+			`SELECT "users"."id", "users"."email", "organizations"."name" FROM "users" ` +
+			`LEFT JOIN "organizations" ON "users"."organization_id" = "organizations"."id" WHERE "users"."age" >= 18;`,
+		`CREATE MATERIALIZED VIEW IF NOT EXISTS "organization_counts" ("organization_name", "count") AS ` +
+			`SELECT "organization_name", COUNT(*) FROM "active_users" GROUP BY "organization_name";`,
+		`COMMENT ON VIEW "active_users" IS 'Adult users';`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS "organization_counts_organization_name_idx" ON "organization_counts" ("organization_name");`,
+	})
+	if got[0].Kind != StatementCreateTable {
+		t.Errorf("first statement kind = %v, want %v", got[0].Kind, StatementCreateTable)
+	}
+}
+
+func TestModelFields_View(t *testing.T) {
+	activeUsers := &viewModel{
+		testModel: testModel{name: "active_users"},
+		view: View{
+			Query:   Select(queryUsers, "id", "email", "organizations.name").LeftJoin(queryOrganizations),
+			Columns: []string{"id", "email", "organization_name"},
+		},
+	}
+	tests := []struct {
+		model Model
+		want  []string
+	}{
+		{activeUsers, []string{"id uuid.UUID", "email string", "organization_name *string"}},
+		{&materializedViewModel{
+			testModel: testModel{name: "organization_counts"},
+			view:      MaterializedView{View: View{Query: Select(activeUsers, "organization_name", "count(*)").GroupBy("organization_name")}},
+		}, []string{"organization_name *string", "count int64"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model.TableName(), func(t *testing.T) {
+			var got []string
+			for _, field := range ModelFields(tt.model) {
+				got = append(got, field.Name()+" "+field.GoType())
+			}
+			if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("ModelFields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompile_ViewAggregateTypes(t *testing.T) {
+	orders := &testModel{name: "orders", fields: []Field{
+		&SmallIntegerField{ColumnName: "lines"},
+		&IntegerField{ColumnName: "quantity"},
+		&BigIntegerField{ColumnName: "total_cents"},
+		&NumericField{ColumnName: "price", Precision: 10, Scale: 2},
+		&NumericField{ColumnName: "weight", Precision: 10, CustomType: "REAL"},
+		&NumericField{ColumnName: "distance", Precision: 10, CustomType: "DOUBLE PRECISION"},
+		&IntervalField{ColumnName: "duration"},
+	}}
+	tests := []struct {
+		column string
+		want   string
+	}{
+		{"sum(lines)", "BIGINT"},
+		{"sum(quantity)", "BIGINT"},
+		{"avg(quantity)", "NUMERIC"},
+		{"sum(total_cents)", "NUMERIC"},
+		{"avg(total_cents)", "NUMERIC"},
+		{"sum(price)", "NUMERIC"},
+		{"avg(price)", "NUMERIC"},
+		{"sum(weight)", "REAL"},
+		{"avg(weight)", "DOUBLE PRECISION"},
+		{"sum(distance)", "DOUBLE PRECISION"},
+		{"avg(distance)", "DOUBLE PRECISION"},
+		{"sum(duration)", "INTERVAL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.column, func(t *testing.T) {
+			view := &viewModel{testModel: testModel{name: "order_totals"}, view: View{Query: Select(orders, tt.column)}}
+			fields := mustCompile(t, orders, view).View("order_totals").Fields
+			if got := ColumnOf(fields[0].(*ViewField).Source).Type; got != tt.want {
+				t.Errorf("%s type = %v, want %v", tt.column, got, tt.want)
+			}
+		})
+	}
+
+	view := &viewModel{testModel: testModel{name: "order_totals"}, view: View{Query: Select(queryUsers, "sum(email)")}}
+	if _, err := Compile(queryOrganizations, queryUsers, view); err == nil {
+		t.Error("Compile() of a view summing text error = nil, want error")
+	}
+}
+
+func TestCompile_ViewErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		model Model
+	}{
+		{"No query", &viewModel{testModel: testModel{name: "report"}}},
+		{"Declared fields", &viewModel{
+			testModel: testModel{name: "report", fields: []Field{&UUIDField{ColumnName: "id"}}},
+			view:      View{Query: Select(queryUsers)},
+		}},
+		{"Indexed plain view", &viewModel{
+			testModel: testModel{name: "report", indexes: []Index{{Columns: []string{"id"}}}},
+			view:      View{Query: Select(queryUsers)},
+		}},
+		{"Index on unknown column", &materializedViewModel{
+			testModel: testModel{name: "report", indexes: []Index{{Columns: []string{"name"}}}},
+			view:      MaterializedView{View: View{Query: Select(queryUsers, "id")}},
+		}},
+		{"Stored view", &viewModel{
+			testModel:  testModel{name: "report"},
+			tablespace: "fast_ssd",
+			view:       View{Query: Select(queryUsers)},
+		}},
+		{"Unknown column", &viewModel{testModel: testModel{name: "report"}, view: View{Query: Select(queryUsers, "name")}}},
+		{"Wrong number of column names", &viewModel{
+			testModel: testModel{name: "report"},
+			view:      View{Query: Select(queryUsers, "id"), Columns: []string{"id", "email"}},
+		}},
+		{"Duplicate column names", &viewModel{
+			testModel: testModel{name: "report"},
+			view:      View{Query: Select(queryUsers, "id", "organizations.id").Join(queryOrganizations)},
+		}},
+		{"Value without a literal", &viewModel{
+			testModel: testModel{name: "report"},
+			view:      View{Query: Select(queryUsers).Where(Eq("id", []int{1}))},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Compile(queryOrganizations, queryUsers, tt.model); err == nil {
+				t.Error("Compile() error = nil, want error")
+			}
+		})
+	}
+
+	joined := &viewModel{testModel: testModel{name: "report"}, view: View{Query: Select(queryUsers, "id").Join(queryOrganizations)}}
+	if _, err := Compile(queryUsers, joined); err == nil {
+		t.Error("Compile() of a view reading a table outside the schema error = nil, want error")
+	}
+}
+
+func TestCompiledView_RefreshSQL(t *testing.T) {
+	tests := []struct {
+		name         string
+		model        Model
+		concurrently bool
+		want         string
+		wantErr      bool
+	}{
+		{
+			name: "Refresh",
+			model: &materializedViewModel{
+				testModel: testModel{name: "organization_counts"},
+				view:      MaterializedView{View: View{Query: Select(queryUsers, "organization_id", "count(*)").GroupBy("organization_id")}},
+			},
+			want: `REFRESH MATERIALIZED VIEW "organization_counts";`,
+		},
+		{
+			name: "Concurrently",
+			model: &materializedViewModel{
+				testModel: testModel{name: "organization_counts", indexes: []Index{{Columns: []string{"organization_id"},
+					Unique: true}}},
+				view: MaterializedView{View: View{Query: Select(queryUsers, "organization_id", "count(*)").GroupBy("organization_id")}},
+			},
+			concurrently: true,
+			want:         `REFRESH MATERIALIZED VIEW CONCURRENTLY "organization_counts";`,
+		},
+		{
+			name: "Concurrently without a unique index",
+			model: &materializedViewModel{
+				testModel: testModel{name: "organization_counts", indexes: []Index{{Columns: []string{"organization_id"},
+					Unique: true, Where: `"count" > 0`}}},
+				view: MaterializedView{View: View{Query: Select(queryUsers, "organization_id", "count(*)").GroupBy("organization_id")}},
+			},
+			concurrently: true,
+			wantErr:      true,
+		},
+		{
+			name:    "Plain view",
+			model:   &viewModel{testModel: testModel{name: "adults"}, view: View{Query: Select(queryUsers).Where(Ge("age", 18))}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RefreshMaterializedView(tt.model, tt.concurrently)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RefreshMaterializedView() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("RefreshMaterializedView() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiff_Views(t *testing.T) {
+	adults := &viewModel{testModel: testModel{name: "adults"}, view: View{Query: Select(queryUsers, "id", "email").Where(Ge("age", 18))}}
+	counts := &materializedViewModel{
+		testModel: testModel{name: "adult_counts"},
+		view:      MaterializedView{View: View{Query: Select(adults, "count(*)")}},
+	}
+	from := mustCompile(t, queryOrganizations, queryUsers, adults, counts)
+
+	commented := &viewModel{testModel: testModel{name: "adults"}, comment: "Adult users", view: adults.view}
+	unchanged, err := Diff(from, mustCompile(t, queryOrganizations, queryUsers, commented, counts))
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	assertStatements(t, unchanged, []string{`COMMENT ON VIEW "adults" IS 'Adult users';`})
+
+	users := &testModel{name: "users", fields: []Field{
+		&UUIDField{ColumnName: "id", PrimaryKey: true},
+		&CharField{ColumnName: "email", MaxLength: 255},
+		&BigIntegerField{ColumnName: "age", Nullable: true},
+		&ForeignKeyField{ColumnName: "organization_id", ReferenceTable: "organizations", ReferenceField: "id"},
+	}}
+	got, err := Diff(from, mustCompile(t, queryOrganizations, users, adults, counts))
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	assertStatements(t, got, []string{
+		`DROP MATERIALIZED VIEW IF EXISTS "adult_counts";`,
+		`DROP VIEW IF EXISTS "adults";`,
+		`ALTER TABLE "users" ALTER COLUMN "age" TYPE BIGINT USING "age"::BIGINT;`,
+		`CREATE OR REPLACE VIEW "adults" ("id", "email") AS SELECT "id", "email" FROM "users" WHERE "age" >= 18;`,
+		`CREATE MATERIALIZED VIEW IF NOT EXISTS "adult_counts" ("count") AS SELECT COUNT(*) FROM "adults";`,
+	})
+
+	dropped, err := Diff(from, mustCompile(t, queryOrganizations, queryUsers))
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	assertStatements(t, dropped, []string{
+		`DROP MATERIALIZED VIEW IF EXISTS "adult_counts";`,
+		`DROP VIEW IF EXISTS "adults";`,
+	})
+}
+
+func TestInsertQuery_BuildView(t *testing.T) {
+	adults := &viewModel{testModel: testModel{name: "adults"}, view: View{Query: Select(queryUsers, "id", "email").Where(Ge("age", 18))}}
+	if _, _, err := Insert(adults).Columns("email").Values("a@example.com").Build(); err == nil {
+		t.Error("Build() on a view error = nil, want error")
+	}
+}